@@ -0,0 +1,268 @@
+package goats
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// stablePackages are the directories whose exported API is recorded in api.txt
+var stablePackages = []string{".", "spec", "lint"}
+
+// TestAPI fails when any declaration recorded in api.txt has been removed or changed, unless what it declares is still
+// present and marked as deprecated. The api.txt file records the API of the last release, so it is never regenerated
+// by this test and is only replaced when tagging a release using the added declarations this test logs.
+func TestAPI(t *testing.T) {
+	current := make(map[string]bool)
+	deprecated := make(map[string]bool)
+	for _, dir := range stablePackages {
+		decls, err := exportedAPI(dir)
+		if err != nil {
+			t.Fatalf("failed to read the API of %s: %s", dir, err)
+		}
+		for _, decl := range decls {
+			current[decl.line] = true
+			if decl.deprecated {
+				deprecated[apiSymbol(decl.line)] = true
+			}
+		}
+	}
+	raw, err := os.ReadFile("api.txt")
+	if err != nil {
+		t.Fatalf("failed to read api.txt: %s", err)
+	}
+	recorded := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(raw)), "\n") {
+		recorded[line] = true
+		if current[line] {
+			continue
+		}
+		if deprecated[apiSymbol(line)] {
+			t.Logf("changed after being deprecated: %s", line)
+			continue
+		}
+		t.Errorf("removed or changed without being deprecated first: %s", line)
+	}
+	added := make([]string, 0)
+	for line := range current {
+		if !recorded[line] {
+			added = append(added, line)
+		}
+	}
+	sort.Strings(added)
+	for _, line := range added {
+		t.Logf("added since the last release: %s", line)
+	}
+}
+
+func TestAPISymbol(t *testing.T) {
+	tests := map[string]struct {
+		line     string
+		expected string
+	}{
+		"identify a function by its name": {
+			line:     "pkg a, func Lint(swagger *spec.Swagger, rules ...Rule) []Finding",
+			expected: "pkg a, func Lint",
+		},
+		"identify a method by its receiver type and name": {
+			line:     "pkg a, func (*Operation) BodyParameter() *Parameter",
+			expected: "pkg a, func (Operation) BodyParameter",
+		},
+		"identify a generic function by its name": {
+			line:     "pkg a, func Map[T any](values []T) []T",
+			expected: "pkg a, func Map",
+		},
+		"identify a type by its name": {
+			line:     "pkg a, type Severity int",
+			expected: "pkg a, type Severity",
+		},
+		"identify a struct field by its type and name": {
+			line:     "pkg a, type Finding struct, DocsURL string",
+			expected: "pkg a, type Finding struct, DocsURL",
+		},
+		"identify an embedded struct field by its type": {
+			line:     "pkg a, type Result struct, embedded *Summary",
+			expected: "pkg a, type Result struct, embedded *Summary",
+		},
+		"identify an interface method by its type and name": {
+			line:     "pkg a, type Parser interface, Parse() (*spec.Swagger, error)",
+			expected: "pkg a, type Parser interface, Parse",
+		},
+		"identify a constant by its name": {
+			line:     "pkg a, const SeverityError",
+			expected: "pkg a, const SeverityError",
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			if got := apiSymbol(tt.line); got != tt.expected {
+				t.Errorf("apiSymbol() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// apiSymbol returns what a line of the API declares without its signature, so that a changed declaration can be found
+func apiSymbol(line string) string {
+	pkg, decl, found := strings.Cut(line, ", ")
+	if !found {
+		return line
+	}
+	name := func(s string) string {
+		if i := strings.IndexAny(s, "[("); i >= 0 {
+			return s[:i]
+		}
+		return s
+	}
+	switch {
+	case strings.HasPrefix(decl, "func ("):
+		recv, rest, _ := strings.Cut(strings.TrimPrefix(decl, "func ("), ") ")
+		decl = "func (" + strings.TrimLeft(recv, "*") + ") " + name(rest)
+	case strings.HasPrefix(decl, "func "):
+		decl = "func " + name(strings.TrimPrefix(decl, "func "))
+	case strings.HasPrefix(decl, "type "):
+		typeName, rest, _ := strings.Cut(strings.TrimPrefix(decl, "type "), " ")
+		decl = "type " + typeName
+		if kind, member, isMember := strings.Cut(rest, ", "); isMember {
+			switch {
+			case strings.HasPrefix(member, "embedded "):
+			case kind == "struct":
+				member, _, _ = strings.Cut(member, " ")
+			default:
+				member = name(member)
+			}
+			decl += " " + kind + ", " + member
+		}
+	}
+	return pkg + ", " + decl
+}
+
+// apiDecl is a line of the exported API and whether what it declares is deprecated
+type apiDecl struct {
+	line       string
+	deprecated bool
+}
+
+// exportedAPI returns a line for every exported declaration of the package in the directory
+func exportedAPI(dir string) ([]apiDecl, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	prefix := "pkg " + filepath.ToSlash(filepath.Join("github.com/erraggy/goats", dir))
+	var results []apiDecl
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				for _, d := range declLines(fset, decl) {
+					d.line = prefix + ", " + d.line
+					results = append(results, d)
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+func declLines(fset *token.FileSet, decl ast.Decl) []apiDecl {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return nil
+		}
+		var recv string
+		if d.Recv != nil && len(d.Recv.List) > 0 {
+			recv = nodeString(fset, d.Recv.List[0].Type)
+			if !ast.IsExported(strings.TrimLeft(recv, "*")) {
+				return nil
+			}
+			recv = "(" + recv + ") "
+		}
+		return []apiDecl{{
+			line:       "func " + recv + d.Name.Name + strings.TrimPrefix(nodeString(fset, d.Type), "func"),
+			deprecated: isDeprecated(d.Doc),
+		}}
+	case *ast.GenDecl:
+		var results []apiDecl
+		for _, s := range d.Specs {
+			switch sp := s.(type) {
+			case *ast.TypeSpec:
+				if !sp.Name.IsExported() {
+					continue
+				}
+				// the members of a deprecated type are deprecated along with it
+				deprecated := isDeprecated(sp.Doc) || (len(d.Specs) == 1 && isDeprecated(d.Doc))
+				switch t := sp.Type.(type) {
+				case *ast.StructType:
+					results = append(results, apiDecl{line: "type " + sp.Name.Name + " struct", deprecated: deprecated})
+					for _, field := range t.Fields.List {
+						typ := nodeString(fset, field.Type)
+						fieldDeprecated := deprecated || isDeprecated(field.Doc)
+						if len(field.Names) == 0 && ast.IsExported(strings.TrimLeft(typ, "*")) {
+							results = append(results, apiDecl{line: "type " + sp.Name.Name + " struct, embedded " + typ, deprecated: fieldDeprecated})
+						}
+						for _, n := range field.Names {
+							if n.IsExported() {
+								results = append(results, apiDecl{line: "type " + sp.Name.Name + " struct, " + n.Name + " " + typ, deprecated: fieldDeprecated})
+							}
+						}
+					}
+				case *ast.InterfaceType:
+					results = append(results, apiDecl{line: "type " + sp.Name.Name + " interface", deprecated: deprecated})
+					for _, m := range t.Methods.List {
+						for _, n := range m.Names {
+							results = append(results, apiDecl{
+								line:       "type " + sp.Name.Name + " interface, " + n.Name + strings.TrimPrefix(nodeString(fset, m.Type), "func"),
+								deprecated: deprecated || isDeprecated(m.Doc),
+							})
+						}
+					}
+				default:
+					results = append(results, apiDecl{line: "type " + sp.Name.Name + " " + nodeString(fset, sp.Type), deprecated: deprecated})
+				}
+			case *ast.ValueSpec:
+				kind := "var"
+				if d.Tok == token.CONST {
+					kind = "const"
+				}
+				deprecated := isDeprecated(sp.Doc) || (len(d.Specs) == 1 && isDeprecated(d.Doc))
+				for _, n := range sp.Names {
+					if n.IsExported() {
+						results = append(results, apiDecl{line: kind + " " + n.Name, deprecated: deprecated})
+					}
+				}
+			}
+		}
+		return results
+	}
+	return nil
+}
+
+// isDeprecated returns true if the doc comment has a paragraph starting with "Deprecated: "
+func isDeprecated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, paragraph := range strings.Split(doc.Text(), "\n\n") {
+		if strings.HasPrefix(paragraph, "Deprecated: ") {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeString(fset *token.FileSet, node any) string {
+	var b bytes.Buffer
+	_ = printer.Fprint(&b, fset, node)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func runBundle(env *environment, args []string) int {
+	flags := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(env.stderr, "Usage: goats bundle [-out file] [-dry-run] [dir]")
+		fmt.Fprintln(env.stderr)
+		fmt.Fprintln(env.stderr, "Merges the project in the directory, or the current directory when none is given, into a single")
+		fmt.Fprintln(env.stderr, "swagger spec written to stdout.")
+		flags.PrintDefaults()
+	}
+	out := flags.String("out", "", "the file to write the bundled spec to")
+	dryRun := flags.Bool("dry-run", false, "list the files that would be bundled instead of writing the spec")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() > 1 {
+		fmt.Fprintln(env.stderr, "goats bundle: only one project can be bundled at a time")
+		return exitUsage
+	}
+	dir := "."
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+
+	project, err := spec.LoadProject(dir)
+	var pe *spec.ParseError
+	if errors.As(err, &pe) && project != nil {
+		for _, loc := range pe.Locations() {
+			src := project.Source(loc)
+			for _, e := range pe.At(loc) {
+				fmt.Fprintf(env.stderr, "goats bundle: %s: %s: %s\n", src.File, src.Location, e)
+			}
+		}
+		return exitFailure
+	}
+	if err != nil {
+		fmt.Fprintf(env.stderr, "goats bundle: %s\n", err)
+		return exitFailure
+	}
+
+	if *dryRun {
+		dest := *out
+		if dest == "" {
+			dest = "stdout"
+		}
+		fmt.Fprintf(env.stdout, "would bundle %d files from %s into %s:\n", len(project.Files), project.Root, dest)
+		for _, file := range project.Files {
+			fmt.Fprintf(env.stdout, "  %s\n", file)
+		}
+		return exitOK
+	}
+	raw, err := project.Swagger.MarshalJSON()
+	if err == nil {
+		raw, err = spec.Format(raw, spec.FormatOptions{})
+	}
+	if err != nil {
+		fmt.Fprintf(env.stderr, "goats bundle: %s\n", err)
+		return exitFailure
+	}
+	if *out == "" {
+		_, _ = env.stdout.Write(raw)
+		return exitOK
+	}
+	if err = os.WriteFile(*out, raw, 0o644); err != nil {
+		fmt.Fprintf(env.stderr, "goats bundle: %s\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
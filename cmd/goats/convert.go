@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/erraggy/goats/convert"
+	"github.com/erraggy/goats/spec"
+)
+
+// conversion converts the raw input of the convert command, where ext is the file extension of its result
+type conversion struct {
+	ext     string
+	convert func(raw []byte) ([]byte, error)
+}
+
+// convertTargets are the formats a swagger spec can be converted to
+var convertTargets = map[string]conversion{
+	"graphql": {ext: ".graphql", convert: func(raw []byte) ([]byte, error) {
+		swagger, err := spec.NewParser(raw).Parse()
+		if err != nil {
+			return nil, err
+		}
+		return convert.ToGraphQLSDL(swagger, convert.GraphQLOptions{})
+	}},
+}
+
+// convertSources are the formats a swagger spec can be converted from
+var convertSources = map[string]conversion{
+	"har": {ext: ".json", convert: func(raw []byte) ([]byte, error) {
+		swagger, err := convert.FromHAR(raw)
+		if err != nil {
+			return nil, err
+		}
+		out, err := swagger.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return spec.Format(out, spec.FormatOptions{})
+	}},
+}
+
+func runConvert(env *environment, args []string) int {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(env.stderr, "Usage: goats convert (-to format | -from format) [-out path] [file]")
+		fmt.Fprintln(env.stderr)
+		fmt.Fprintln(env.stderr, "Converts the file, or stdin when none is given, writing the result to stdout.")
+		fmt.Fprintf(env.stderr, "Swagger specs can be converted to: %s\n", strings.Join(conversionNames(convertTargets), ", "))
+		fmt.Fprintf(env.stderr, "Swagger specs can be converted from: %s\n", strings.Join(conversionNames(convertSources), ", "))
+		flags.PrintDefaults()
+	}
+	to := flags.String("to", "", "the format to convert the swagger spec to")
+	from := flags.String("from", "", "the format to convert to a swagger spec")
+	out := flags.String("out", "", "the file to write the result to, or an existing directory to write it within")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	var (
+		conv   conversion
+		exists bool
+	)
+	switch {
+	case (*to == "") == (*from == ""):
+		fmt.Fprintln(env.stderr, "goats convert: exactly one of -to or -from is required")
+		return exitUsage
+	case *to != "":
+		if conv, exists = convertTargets[*to]; !exists {
+			fmt.Fprintf(env.stderr, "goats convert: cannot convert to %q, should be one of %v\n", *to, conversionNames(convertTargets))
+			return exitUsage
+		}
+	default:
+		if conv, exists = convertSources[*from]; !exists {
+			fmt.Fprintf(env.stderr, "goats convert: cannot convert from %q, should be one of %v\n", *from, conversionNames(convertSources))
+			return exitUsage
+		}
+	}
+	if flags.NArg() > 1 {
+		fmt.Fprintln(env.stderr, "goats convert: only one file can be converted at a time")
+		return exitUsage
+	}
+
+	var (
+		raw  []byte
+		name = "<stdin>"
+		err  error
+	)
+	if flags.NArg() == 0 {
+		raw, err = io.ReadAll(env.stdin)
+	} else {
+		name = flags.Arg(0)
+		raw, err = os.ReadFile(name)
+	}
+	if err != nil {
+		fmt.Fprintf(env.stderr, "goats convert: %s\n", err)
+		return exitFailure
+	}
+	result, err := conv.convert(raw)
+	if err != nil {
+		fmt.Fprintf(env.stderr, "goats convert: %s: %s\n", name, err)
+		return exitFailure
+	}
+
+	if *out == "" {
+		_, _ = env.stdout.Write(result)
+		return exitOK
+	}
+	path := *out
+	if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+		base := "spec"
+		if flags.NArg() > 0 {
+			base = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		}
+		path = filepath.Join(path, base+conv.ext)
+	}
+	if err = os.WriteFile(path, result, 0o644); err != nil {
+		fmt.Fprintf(env.stderr, "goats convert: %s\n", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// conversionNames returns the names of the formats in order
+func conversionNames(conversions map[string]conversion) []string {
+	names := make([]string, 0, len(conversions))
+	for name := range conversions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func runFmt(env *environment, args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(env.stderr, "Usage: goats fmt [-l] [-w] [-indent string] [file ...]")
+		fmt.Fprintln(env.stderr)
+		fmt.Fprintln(env.stderr, "Formats each file, or stdin when none are given, writing the result to stdout.")
+		flags.PrintDefaults()
+	}
+	list := flags.Bool("l", false, "list the files whose formatting differs instead of printing them")
+	write := flags.Bool("w", false, "write the result back to each file instead of printing it")
+	indent := flags.String("indent", "  ", "the indentation of each level")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	opts := spec.FormatOptions{Indent: *indent}
+
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(env.stderr, "goats fmt: cannot use -w with stdin")
+			return exitUsage
+		}
+		raw, err := io.ReadAll(env.stdin)
+		if err != nil {
+			fmt.Fprintf(env.stderr, "goats fmt: %s\n", err)
+			return exitFailure
+		}
+		formatted, err := spec.Format(raw, opts)
+		if err != nil {
+			fmt.Fprintf(env.stderr, "goats fmt: <stdin>: %s\n", err)
+			return exitFailure
+		}
+		if *list {
+			if !bytes.Equal(raw, formatted) {
+				fmt.Fprintln(env.stdout, "<stdin>")
+			}
+			return exitOK
+		}
+		_, _ = env.stdout.Write(formatted)
+		return exitOK
+	}
+
+	code := exitOK
+	for _, path := range flags.Args() {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(env.stderr, "goats fmt: %s\n", err)
+			code = exitFailure
+			continue
+		}
+		formatted, err := spec.Format(raw, opts)
+		if err != nil {
+			fmt.Fprintf(env.stderr, "goats fmt: %s: %s\n", path, err)
+			code = exitFailure
+			continue
+		}
+		changed := !bytes.Equal(raw, formatted)
+		if *list && changed {
+			fmt.Fprintln(env.stdout, path)
+		}
+		if *write {
+			if changed {
+				if err = os.WriteFile(path, formatted, 0o644); err != nil {
+					fmt.Fprintf(env.stderr, "goats fmt: %s\n", err)
+					code = exitFailure
+				}
+			}
+		} else if !*list {
+			_, _ = env.stdout.Write(formatted)
+		}
+	}
+	return code
+}
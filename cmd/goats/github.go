@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/erraggy/goats/lint"
+	"github.com/erraggy/goats/spec"
+)
+
+// GitHub Actions workflow commands require these characters to be escaped within their data and properties
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+var (
+	githubDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// githubCommand returns the workflow command of the severity
+func githubCommand(s lint.Severity) string {
+	switch s {
+	case lint.SeverityError:
+		return "error"
+	case lint.SeverityWarning:
+		return "warning"
+	}
+	return "notice"
+}
+
+// writeGitHubAnnotation writes the workflow command annotating the location within the file with the message
+func writeGitHubAnnotation(env *environment, command string, file lintedFile, loc, title, message string) {
+	props := "file=" + githubPropertyEscaper.Replace(file.path)
+	if line, column, ok := sourcePosition(file.swagger, file.raw, loc); ok {
+		props += fmt.Sprintf(",line=%d,col=%d", line, column)
+	}
+	props += ",title=" + githubPropertyEscaper.Replace(title)
+	fmt.Fprintf(env.stdout, "::%s %s::%s\n", command, props, githubDataEscaper.Replace(loc+": "+message))
+}
+
+func writeLintGitHub(env *environment, _ []lint.Rule, files []lintedFile) error {
+	for _, file := range files {
+		for _, f := range file.findings {
+			writeGitHubAnnotation(env, githubCommand(f.Severity), file, f.Location, f.RuleID, f.Message)
+		}
+	}
+	return nil
+}
+
+// writeParseAnnotations writes an error annotation for each of the parse errors of the file
+func writeParseAnnotations(env *environment, file lintedFile, pe *spec.ParseError) {
+	for _, loc := range pe.Locations() {
+		for _, err := range pe.At(loc) {
+			writeGitHubAnnotation(env, "error", file, loc, "invalid swagger", err.Error())
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/erraggy/goats/lint"
+	"github.com/erraggy/goats/spec"
+)
+
+// lintedFile is the outcome of linting a single file
+type lintedFile struct {
+	path     string
+	raw      []byte
+	swagger  *spec.Swagger
+	findings []lint.Finding
+}
+
+func runLint(env *environment, args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(env.stderr, "Usage: goats lint [-ruleset file] [-format text|json|sarif|github] file ...")
+		fmt.Fprintln(env.stderr)
+		fmt.Fprintln(env.stderr, "Lints each file, failing when any finding is an error. The github format writes the findings and")
+		fmt.Fprintln(env.stderr, "any parse errors as GitHub Actions annotations so that they are shown inline within pull requests.")
+		flags.PrintDefaults()
+	}
+	rulesetPath := flags.String("ruleset", "", "a JSON ruleset turning rules off or changing their severity")
+	format := flags.String("format", "text", "the output format: text, json, sarif or github")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(env.stderr, "goats lint: at least one file is required")
+		return exitUsage
+	}
+	var write func(env *environment, rules []lint.Rule, files []lintedFile) error
+	switch *format {
+	case "text":
+		write = writeLintText
+	case "json":
+		write = writeLintJSON
+	case "sarif":
+		write = writeLintSARIF
+	case "github":
+		write = writeLintGitHub
+	default:
+		fmt.Fprintf(env.stderr, "goats lint: format should be one of [text json sarif github] but got: '%s'\n", *format)
+		return exitUsage
+	}
+
+	rules, err := loadRules(*rulesetPath)
+	if err != nil {
+		fmt.Fprintf(env.stderr, "goats lint: %s\n", err)
+		return exitUsage
+	}
+
+	code := exitOK
+	files := make([]lintedFile, 0, flags.NArg())
+	for _, path := range flags.Args() {
+		file, err := lintFile(path, rules)
+		var pe *spec.ParseError
+		if *format == "github" && errors.As(err, &pe) {
+			// parse errors are annotated along with the findings when they can be located within the file
+			writeParseAnnotations(env, file, pe)
+			code = exitFailure
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(env.stderr, "goats lint: %s\n", err)
+			code = exitFailure
+			continue
+		}
+		for _, f := range file.findings {
+			if f.Severity == lint.SeverityError {
+				code = exitFailure
+			}
+		}
+		files = append(files, file)
+	}
+	if err := write(env, rules, files); err != nil {
+		fmt.Fprintf(env.stderr, "goats lint: %s\n", err)
+		return exitFailure
+	}
+	return code
+}
+
+// loadRules returns the default rules as configured by the ruleset file, if any
+func loadRules(rulesetPath string) ([]lint.Rule, error) {
+	rules := lint.DefaultRules()
+	if rulesetPath == "" {
+		return rules, nil
+	}
+	raw, err := os.ReadFile(rulesetPath)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := lint.ParseRuleset(raw)
+	if err == nil {
+		rules, err = rs.Apply(rules)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rulesetPath, err)
+	}
+	return rules, nil
+}
+
+// lintFile reads, parses and lints the file using the rules. When parsing fails the file is returned without any
+// findings along with the error, so that its parse errors can be located within it.
+func lintFile(path string, rules []lint.Rule) (lintedFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return lintedFile{}, err
+	}
+	swagger, err := spec.NewParser(raw, spec.WithSourceMap()).Parse()
+	file := lintedFile{path: path, raw: raw, swagger: swagger}
+	if err != nil {
+		return file, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(rules) > 0 {
+		// an empty list of rules would otherwise check the default rules
+		file.findings = lint.Lint(swagger, rules...)
+	}
+	return file, nil
+}
+
+// sourcePosition returns the line and column, both starting from 1, of the value at the location within the raw
+// document, or of its nearest enclosing value when the location was not recorded, such as for a missing field
+func sourcePosition(swagger *spec.Swagger, raw []byte, loc string) (line, column int, ok bool) {
+	for {
+		if r, exists := swagger.SourceRange(loc); exists && r.Start <= len(raw) {
+			before := raw[:r.Start]
+			lineStart := bytes.LastIndexByte(before, '\n') + 1
+			return bytes.Count(before, []byte("\n")) + 1, utf8.RuneCount(before[lineStart:]) + 1, true
+		}
+		if loc == "" {
+			return 0, 0, false
+		}
+		loc = loc[:max(strings.LastIndexByte(loc, '.'), 0)]
+	}
+}
+
+func writeLintText(env *environment, _ []lint.Rule, files []lintedFile) error {
+	for _, file := range files {
+		for _, f := range file.findings {
+			fmt.Fprintln(env.stdout, file.describe(f))
+		}
+	}
+	return nil
+}
+
+// describe returns the finding prefixed by the path of the file along with its line and column when known
+func (file lintedFile) describe(f lint.Finding) string {
+	if line, column, ok := sourcePosition(file.swagger, file.raw, f.Location); ok {
+		return fmt.Sprintf("%s:%d:%d: %s", file.path, line, column, f)
+	}
+	return fmt.Sprintf("%s: %s", file.path, f)
+}
+
+// jsonFinding is a lint.Finding written by the json format
+type jsonFinding struct {
+	File     string                `json:"file"`
+	Line     int                   `json:"line,omitempty"`
+	Column   int                   `json:"column,omitempty"`
+	Location string                `json:"location"`
+	RuleID   string                `json:"ruleId"`
+	Severity string                `json:"severity"`
+	Message  string                `json:"message"`
+	DocsURL  string                `json:"docsUrl,omitempty"`
+	Fix      []spec.PatchOperation `json:"fix,omitempty"`
+}
+
+func writeLintJSON(env *environment, _ []lint.Rule, files []lintedFile) error {
+	results := make([]jsonFinding, 0)
+	for _, file := range files {
+		for _, f := range file.findings {
+			line, column, _ := sourcePosition(file.swagger, file.raw, f.Location)
+			results = append(results, jsonFinding{
+				File:     file.path,
+				Line:     line,
+				Column:   column,
+				Location: f.Location,
+				RuleID:   f.RuleID,
+				Severity: f.Severity.String(),
+				Message:  f.Message,
+				DocsURL:  f.DocsURL,
+				Fix:      f.Fix,
+			})
+		}
+	}
+	enc := json.NewEncoder(env.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
@@ -0,0 +1,82 @@
+// Command goats provides tools for working with swagger specifications from the command line.
+//
+// Usage:
+//
+//	goats <command> [arguments]
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+// Exit codes of the commands
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// command is a single subcommand of goats
+type command struct {
+	name    string
+	summary string
+	run     func(env *environment, args []string) int
+}
+
+// environment is what commands read from and write to, so that they can be tested
+type environment struct {
+	// ctx is done when the command should stop, such as when interrupted
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// commands are the subcommands in the order they are listed by usage
+var commands = []command{
+	{name: "fmt", summary: "format swagger specs in their canonical form", run: runFmt},
+	{name: "lint", summary: "lint swagger specs for likely mistakes", run: runLint},
+	{name: "bundle", summary: "merge a multi-file project into a single swagger spec", run: runBundle},
+	{name: "watch", summary: "lint swagger specs again whenever they change", run: runWatch},
+	{name: "mock", summary: "serve sample responses to the operations of a swagger spec", run: runMock},
+	{name: "convert", summary: "convert swagger specs to and from other formats", run: runConvert},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(&environment{ctx: ctx, stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}, os.Args[1:])
+	stop()
+	os.Exit(code)
+}
+
+func run(env *environment, args []string) int {
+	if len(args) == 0 {
+		usage(env.stderr)
+		return exitUsage
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(env, args[1:])
+		}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		usage(env.stdout)
+		return exitOK
+	}
+	fmt.Fprintf(env.stderr, "goats: unknown command %q\n", args[0])
+	usage(env.stderr)
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: goats <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+}
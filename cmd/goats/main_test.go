@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runTest runs goats with the arguments and stdin, returning its exit code, stdout and stderr
+func runTest(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(&environment{ctx: context.Background(), stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}, args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	if code, _, stderr := runTest(t, ""); code != exitUsage || !strings.Contains(stderr, "Usage: goats") {
+		t.Errorf("run() without a command = %d, %q", code, stderr)
+	}
+	if code, _, stderr := runTest(t, "", "bogus"); code != exitUsage || !strings.Contains(stderr, `unknown command "bogus"`) {
+		t.Errorf("run() with an unknown command = %d, %q", code, stderr)
+	}
+	if code, stdout, _ := runTest(t, "", "help"); code != exitOK || !strings.Contains(stdout, "fmt") {
+		t.Errorf("run() help = %d, %q", code, stdout)
+	}
+}
+
+func TestRunFmt(t *testing.T) {
+	const (
+		unformatted = `{"info":{"version":"1","title":"t"},"swagger":"2.0"}`
+		formatted   = "{\n  \"swagger\": \"2.0\",\n  \"info\": {\n    \"title\": \"t\",\n    \"version\": \"1\"\n  }\n}\n"
+	)
+	if code, stdout, stderr := runTest(t, unformatted, "fmt"); code != exitOK || stdout != formatted {
+		t.Errorf("fmt of stdin = %d, %q, %q", code, stdout, stderr)
+	}
+	if code, _, _ := runTest(t, `{"swagger":`, "fmt"); code != exitFailure {
+		t.Errorf("fmt of invalid JSON = %d", code)
+	}
+
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.json")
+	b := filepath.Join(dir, "b.json")
+	if err := os.WriteFile(a, []byte(unformatted), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(formatted), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code, stdout, _ := runTest(t, "", "fmt", "-l", a, b); code != exitOK || stdout != a+"\n" {
+		t.Errorf("fmt -l = %d, %q", code, stdout)
+	}
+	if code, stdout, _ := runTest(t, "", "fmt", "-w", a); code != exitOK || stdout != "" {
+		t.Errorf("fmt -w = %d, %q", code, stdout)
+	}
+	if raw, _ := os.ReadFile(a); string(raw) != formatted {
+		t.Errorf("fmt -w wrote %q", raw)
+	}
+	if code, _, _ := runTest(t, "", "fmt", filepath.Join(dir, "missing.json")); code != exitFailure {
+		t.Errorf("fmt of a missing file = %d", code)
+	}
+}
+
+func TestRunConvert(t *testing.T) {
+	const (
+		swagger = `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"get": {"operationId": "listPets", "responses": {"200": {"description": "ok", "schema": {"type": "array", "items": {"type": "string"}}}}}}}}`
+		har     = `{"log": {"entries": [{"request": {"method": "GET", "url": "https://example.com/pets"}, "response": {"status": 200, "content": {"mimeType": "application/json", "text": "[]"}}}]}}`
+	)
+	code, stdout, stderr := runTest(t, swagger, "convert", "-to", "graphql")
+	if code != exitOK || !strings.Contains(stdout, "type Query {") || !strings.Contains(stdout, "listPets: [String]") {
+		t.Errorf("convert -to graphql = %d, %q, %q", code, stdout, stderr)
+	}
+	code, stdout, stderr = runTest(t, har, "convert", "--from", "har")
+	if code != exitOK || !strings.HasPrefix(stdout, "{\n  \"swagger\": \"2.0\"") || !strings.Contains(stdout, `"/pets"`) {
+		t.Errorf("convert -from har = %d, %q, %q", code, stdout, stderr)
+	}
+
+	for _, args := range [][]string{
+		{"convert"},
+		{"convert", "-to", "graphql", "-from", "har"},
+		{"convert", "-to", "proto"},
+		{"convert", "-from", "postman"},
+		{"convert", "-to", "graphql", "a.json", "b.json"},
+	} {
+		if code, _, _ := runTest(t, swagger, args...); code != exitUsage {
+			t.Errorf("%v = %d", args, code)
+		}
+	}
+	if code, _, _ := runTest(t, `{"swagger":`, "convert", "-to", "graphql"); code != exitFailure {
+		t.Errorf("convert of invalid JSON = %d", code)
+	}
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "pets.json")
+	if err := os.WriteFile(in, []byte(swagger), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code, stdout, _ := runTest(t, "", "convert", "-to", "graphql", "-out", dir, in); code != exitOK || stdout != "" {
+		t.Errorf("convert -out to a directory = %d, %q", code, stdout)
+	}
+	if raw, err := os.ReadFile(filepath.Join(dir, "pets.graphql")); err != nil || !bytes.Contains(raw, []byte("type Query {")) {
+		t.Errorf("convert -out to a directory wrote %q, %v", raw, err)
+	}
+	out := filepath.Join(dir, "schema.graphql")
+	if code, _, _ := runTest(t, swagger, "convert", "-to", "graphql", "-out", out); code != exitOK {
+		t.Errorf("convert -out to a file = %d", code)
+	}
+	if raw, err := os.ReadFile(out); err != nil || !bytes.Contains(raw, []byte("type Query {")) {
+		t.Errorf("convert -out to a file wrote %q, %v", raw, err)
+	}
+}
+
+func TestRunLint(t *testing.T) {
+	const raw = `{
+  "swagger": "2.0",
+  "info": {"title": "t", "version": "1"},
+  "securityDefinitions": {
+    "key": {"type": "apiKey", "name": "api_key", "in": "query"}
+  },
+  "paths": {
+    "/pets": {
+      "get": {"deprecated": true, "responses": {"200": {"description": "ok"}}}
+    }
+  }
+}`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pets.json")
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	code, stdout, stderr := runTest(t, "", "lint", path)
+	if code != exitOK {
+		t.Errorf("lint = %d, %q", code, stderr)
+	}
+	for _, want := range []string{
+		path + ":9:14: .paths./pets.get: warning [deprecated-without-sunset] deprecated operation does not declare x-sunset\n",
+		path + ":5:56: .securityDefinitions.key.in: warning [api-key-in-query] API key api_key is sent in the query\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("lint output %q does not contain %q", stdout, want)
+		}
+	}
+
+	ruleset := filepath.Join(dir, "ruleset.json")
+	if err := os.WriteFile(ruleset, []byte(`{"rules": {"api-key-in-query": "error", "deprecated-without-sunset": "off"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	code, stdout, _ = runTest(t, "", "lint", "-ruleset", ruleset, "-format", "json", path)
+	if code != exitFailure {
+		t.Errorf("lint with an error finding = %d", code)
+	}
+	var findings []jsonFinding
+	if err := json.Unmarshal([]byte(stdout), &findings); err != nil {
+		t.Fatalf("lint -format json wrote invalid JSON %q: %s", stdout, err)
+	}
+	for _, f := range findings {
+		if f.RuleID == "deprecated-without-sunset" {
+			t.Errorf("lint reported a rule turned off: %v", f)
+		}
+		if f.RuleID == "api-key-in-query" && (f.Severity != "error" || f.Line != 5 || f.File != path) {
+			t.Errorf("lint reported %v", f)
+		}
+	}
+
+	code, stdout, _ = runTest(t, "", "lint", "-format", "sarif", path)
+	if code != exitOK {
+		t.Errorf("lint -format sarif = %d", code)
+	}
+	var log sarifLog
+	if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+		t.Fatalf("lint -format sarif wrote invalid JSON %q: %s", stdout, err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) == 0 {
+		t.Fatalf("lint -format sarif wrote %+v", log)
+	}
+	for _, r := range log.Runs[0].Results {
+		if rule := log.Runs[0].Tool.Driver.Rules[r.RuleIndex]; rule.ID != r.RuleID {
+			t.Errorf("result %s has the index of rule %s", r.RuleID, rule.ID)
+		}
+		if r.RuleID == "api-key-in-query" {
+			if region := r.Locations[0].PhysicalLocation.Region; r.Level != "warning" || region == nil || region.StartLine != 5 {
+				t.Errorf("lint -format sarif reported %+v", r)
+			}
+		}
+	}
+
+	for _, args := range [][]string{
+		{"lint"},
+		{"lint", "-format", "xml", path},
+		{"lint", "-ruleset", filepath.Join(dir, "missing.json"), path},
+	} {
+		if code, _, _ := runTest(t, "", args...); code != exitUsage {
+			t.Errorf("%v = %d", args, code)
+		}
+	}
+	if code, _, _ := runTest(t, "", "lint", filepath.Join(dir, "missing.json")); code != exitFailure {
+		t.Errorf("lint of a missing file = %d", code)
+	}
+}
+
+func TestRunBundle(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"swagger.json":    `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"$ref": "paths/pets.json"}}}`,
+		"paths/pets.json": `{"get": {"responses": {"200": {"description": "ok", "schema": {"$ref": "../pet.json"}}}}}`,
+		"pet.json":        `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	code, stdout, stderr := runTest(t, "", "bundle", dir)
+	if code != exitOK || !strings.Contains(stdout, `"$ref": "#/definitions/pet"`) || !strings.Contains(stdout, `"/pets": {`) {
+		t.Errorf("bundle = %d, %q, %q", code, stdout, stderr)
+	}
+	out := filepath.Join(dir, "bundled.json")
+	code, stdout, _ = runTest(t, "", "bundle", "-dry-run", "-out", out, dir)
+	if code != exitOK || !strings.Contains(stdout, "would bundle 3 files") || !strings.Contains(stdout, "  paths/pets.json\n") {
+		t.Errorf("bundle -dry-run = %d, %q", code, stdout)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("bundle -dry-run wrote %s", out)
+	}
+	if code, _, _ = runTest(t, "", "bundle", "-out", out, dir); code != exitOK {
+		t.Errorf("bundle -out = %d", code)
+	}
+	if raw, err := os.ReadFile(out); err != nil || !bytes.Contains(raw, []byte(`"definitions": {`)) {
+		t.Errorf("bundle -out wrote %q, %v", raw, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "pet.json"), []byte(`{"type": "object", "minLength": "x"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, stderr = runTest(t, "", "bundle", dir); code != exitFailure || !strings.Contains(stderr, "goats bundle: pet.json: .minLength: ") {
+		t.Errorf("bundle of an invalid project = %d, %q", code, stderr)
+	}
+	if code, _, _ = runTest(t, "", "bundle", dir, dir); code != exitUsage {
+		t.Errorf("bundle of two projects = %d", code)
+	}
+	if code, _, _ = runTest(t, "", "bundle", t.TempDir()); code != exitFailure {
+		t.Errorf("bundle of an empty directory = %d", code)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for a command writing to it while a test reads it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRunWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pets.json")
+	writeSpec := func(op string) {
+		t.Helper()
+		raw := `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"get": ` + op + `}}}`
+		// write then rename so that the watcher never reads a partially written file
+		tmp := filepath.Join(t.TempDir(), "pets.json")
+		if err := os.WriteFile(tmp, []byte(raw), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSpec(`{"deprecated": true, "responses": {"200": {"description": "ok"}}}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stdout, stderr syncBuffer
+	done := make(chan int, 1)
+	go func() {
+		done <- run(&environment{ctx: ctx, stdin: strings.NewReader(""), stdout: &stdout, stderr: &stderr}, []string{"watch", "-interval", "5ms", dir})
+	}()
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !strings.Contains(stdout.String(), want) {
+			if time.Now().After(deadline) {
+				t.Fatalf("watch output %q does not contain %q, stderr %q", stdout.String(), want, stderr.String())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	waitFor(path + ": 1 new, 0 resolved, 1 total\n+ " + path + ":1:87: .paths./pets.get: warning [deprecated-without-sunset] deprecated operation does not declare x-sunset\n")
+	writeSpec(`{"deprecated": true, "x-sunset": "2030-01-01", "responses": {"200": {"description": "ok"}}}`)
+	waitFor(path + ": 0 new, 1 resolved, 0 total\n- " + path + ":1:87: .paths./pets.get: warning [deprecated-without-sunset] deprecated operation does not declare x-sunset\n")
+	writeSpec(`{"responses": {"200": {"description": "ok"}}, "summary": 1}`)
+	waitFor(path + ": 1 new, 0 resolved, 1 total\n+ " + path + ": .paths./pets.get.summary: ")
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(path + ": 0 new, 1 resolved, 0 total\n- ")
+
+	cancel()
+	select {
+	case code := <-done:
+		if code != exitOK {
+			t.Errorf("watch = %d", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not stop when its context was done")
+	}
+
+	for _, args := range [][]string{
+		{"watch"},
+		{"watch", "-interval", "0s", dir},
+		{"watch", "-ruleset", filepath.Join(dir, "missing.json"), dir},
+	} {
+		if code, _, _ := runTest(t, "", args...); code != exitUsage {
+			t.Errorf("%v = %d", args, code)
+		}
+	}
+}
+
+func TestRunMock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pets.json")
+	raw := `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"get": {"responses": {"200": {"description": "ok", "schema": {"type": "array", "items": {"type": "string", "example": "Rex"}}}}}}}}`
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stdout, stderr syncBuffer
+	done := make(chan int, 1)
+	go func() {
+		done <- run(&environment{ctx: ctx, stdin: strings.NewReader(""), stdout: &stdout, stderr: &stderr}, []string{"mock", "-port", "0", path})
+	}()
+	var addr string
+	for deadline := time.Now().Add(5 * time.Second); addr == ""; time.Sleep(5 * time.Millisecond) {
+		if _, after, found := strings.Cut(stdout.String(), " at "); found {
+			addr = strings.TrimSpace(after)
+		} else if time.Now().After(deadline) {
+			t.Fatalf("mock did not start: %q", stderr.String())
+		}
+	}
+	resp, err := http.Get(addr + "/pets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != `["Rex"]` {
+		t.Errorf("GET /pets = %d, %q", resp.StatusCode, body)
+	}
+
+	cancel()
+	select {
+	case code := <-done:
+		if code != exitOK {
+			t.Errorf("mock = %d, %q", code, stderr.String())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("mock did not stop when its context was done")
+	}
+
+	for _, args := range [][]string{
+		{"mock"},
+		{"mock", "-port", "70000", path},
+		{"mock", "-delay", "-1s", path},
+	} {
+		if code, _, _ := runTest(t, "", args...); code != exitUsage {
+			t.Errorf("%v = %d", args, code)
+		}
+	}
+	if code, _, _ := runTest(t, "", "mock", path+".missing"); code != exitFailure {
+		t.Errorf("mock of a missing file = %d", code)
+	}
+}
+
+func TestRunLint_github(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "pets.json")
+	invalid := filepath.Join(dir, "invalid,pets.json")
+	for path, raw := range map[string]string{
+		valid: `{
+  "swagger": "2.0",
+  "info": {"title": "t", "version": "1"},
+  "paths": {
+    "/pets": {
+      "get": {"deprecated": true, "x-sunset": "soon", "security": [], "responses": {"200": {"description": "ok"}}}
+    }
+  }
+}`,
+		invalid: `{
+  "swagger": "2.0",
+  "info": {"title": "t", "version": 1}
+}`,
+	} {
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	code, stdout, stderr := runTest(t, "", "lint", "-format", "github", valid, invalid)
+	if code != exitFailure || stderr != "" {
+		t.Errorf("lint -format github = %d, %q", code, stderr)
+	}
+	escaped := strings.ReplaceAll(invalid, ",", "%2C")
+	for _, want := range []string{
+		"::warning file=" + valid + ",line=6,col=47,title=deprecated-without-sunset::.paths./pets.get.x-sunset: sunset 'soon' is not a date of the form 2006-01-02\n",
+		"::error file=" + escaped + ",line=3,col=37,title=invalid swagger::.info.version: ",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("lint -format github output %q does not contain %q", stdout, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/erraggy/goats/mock"
+	"github.com/erraggy/goats/spec"
+)
+
+func runMock(env *environment, args []string) int {
+	flags := flag.NewFlagSet("mock", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(env.stderr, "Usage: goats mock [-port int] [-delay duration] [-validate] file")
+		fmt.Fprintln(env.stderr)
+		fmt.Fprintln(env.stderr, "Serves sample responses to the operations of the swagger spec until interrupted. The status code of")
+		fmt.Fprintf(env.stderr, "the response is selected by the %s header or the %s query parameter.\n", mock.StatusHeader, mock.StatusParam)
+		flags.PrintDefaults()
+	}
+	port := flags.Int("port", 8080, "the port to listen on, where 0 picks any free port")
+	delay := flags.Duration("delay", 0, "how long to wait before responding to each request")
+	validate := flags.Bool("validate", false, "reject requests missing required parameters or sending invalid values")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(env.stderr, "goats mock: exactly one file is required")
+		return exitUsage
+	}
+	if *port < 0 || *port > 65535 {
+		fmt.Fprintf(env.stderr, "goats mock: invalid port: %d\n", *port)
+		return exitUsage
+	}
+	if *delay < 0 {
+		fmt.Fprintf(env.stderr, "goats mock: invalid delay: %s\n", *delay)
+		return exitUsage
+	}
+
+	path := flags.Arg(0)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(env.stderr, "goats mock: %s\n", err)
+		return exitFailure
+	}
+	swagger, err := spec.NewParser(raw).Parse()
+	if err != nil {
+		fmt.Fprintf(env.stderr, "goats mock: %s: %s\n", path, err)
+		return exitFailure
+	}
+	h, err := mock.Handler(swagger, mock.Options{Delay: *delay, ValidateRequests: *validate})
+	if err != nil {
+		fmt.Fprintf(env.stderr, "goats mock: %s\n", err)
+		return exitFailure
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(*port)))
+	if err != nil {
+		fmt.Fprintf(env.stderr, "goats mock: %s\n", err)
+		return exitFailure
+	}
+
+	srv := &http.Server{Handler: h, ReadHeaderTimeout: 10 * time.Second}
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ln)
+	}()
+	fmt.Fprintf(env.stdout, "goats mock: serving %s at http://localhost:%d\n", path, ln.Addr().(*net.TCPAddr).Port)
+	select {
+	case <-env.ctx.Done():
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err = srv.Shutdown(ctx); err != nil {
+			fmt.Fprintf(env.stderr, "goats mock: %s\n", err)
+			return exitFailure
+		}
+		return exitOK
+	case err = <-served:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(env.stderr, "goats mock: %s\n", err)
+		}
+		return exitFailure
+	}
+}
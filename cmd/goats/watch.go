@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/erraggy/goats/lint"
+	"github.com/erraggy/goats/spec"
+)
+
+func runWatch(env *environment, args []string) int {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(env.stderr, "Usage: goats watch [-interval duration] [-ruleset file] path ...")
+		fmt.Fprintln(env.stderr)
+		fmt.Fprintln(env.stderr, "Lints each file, and the JSON files within each directory, again whenever they change until")
+		fmt.Fprintln(env.stderr, "interrupted, printing the problems that are new (+) or resolved (-) since they were last linted.")
+		flags.PrintDefaults()
+	}
+	interval := flags.Duration("interval", 500*time.Millisecond, "how often to check the files for changes")
+	rulesetPath := flags.String("ruleset", "", "a JSON ruleset turning rules off or changing their severity")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(env.stderr, "goats watch: at least one path is required")
+		return exitUsage
+	}
+	if *interval <= 0 {
+		fmt.Fprintln(env.stderr, "goats watch: the interval must be positive")
+		return exitUsage
+	}
+	rules, err := loadRules(*rulesetPath)
+	if err != nil {
+		fmt.Fprintf(env.stderr, "goats watch: %s\n", err)
+		return exitUsage
+	}
+
+	w := &watcher{
+		env:      env,
+		rules:    rules,
+		roots:    flags.Args(),
+		stamps:   make(map[string]fileStamp),
+		problems: make(map[string][]problem),
+	}
+	w.check()
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-env.ctx.Done():
+			return exitOK
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// fileStamp identifies the version of a file, where a file that cannot be read has the zero value
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// problem is a parse error or lint finding within a watched file
+type problem struct {
+	// key identifies the problem regardless of where it is within the file, so moving it is not a change
+	key  string
+	text string
+}
+
+// watcher lints the files of its roots whenever they change
+type watcher struct {
+	env   *environment
+	rules []lint.Rule
+	roots []string
+	// stamps are the versions of the files when they were last linted
+	stamps map[string]fileStamp
+	// problems are those of each file when it was last linted
+	problems map[string][]problem
+}
+
+// files returns the sorted paths to watch, being each root that is not a directory and the JSON files within each
+// root that is
+func (w *watcher) files() []string {
+	unique := make(map[string]struct{})
+	for _, root := range w.roots {
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			// a missing file is still watched so that its error is reported
+			unique[root] = struct{}{}
+			continue
+		}
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".json") {
+				unique[path] = struct{}{}
+			}
+			return nil
+		})
+	}
+	results := make([]string, 0, len(unique))
+	for path := range unique {
+		results = append(results, path)
+	}
+	sort.Strings(results)
+	return results
+}
+
+// check lints each file that has changed since it was last linted and reports how its problems changed
+func (w *watcher) check() {
+	files := w.files()
+	current := make(map[string]struct{}, len(files))
+	for _, path := range files {
+		current[path] = struct{}{}
+		var stamp fileStamp
+		if info, err := os.Stat(path); err == nil {
+			stamp = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+		if prev, exists := w.stamps[path]; exists && prev.size == stamp.size && prev.modTime.Equal(stamp.modTime) {
+			continue
+		}
+		w.stamps[path] = stamp
+		w.report(path, w.lint(path))
+	}
+
+	var removed []string
+	for path := range w.stamps {
+		if _, exists := current[path]; !exists {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(removed)
+	for _, path := range removed {
+		w.report(path, nil)
+		delete(w.stamps, path)
+		delete(w.problems, path)
+	}
+}
+
+// lint returns the problems of the file
+func (w *watcher) lint(path string) []problem {
+	file, err := lintFile(path, w.rules)
+	var pe *spec.ParseError
+	if errors.As(err, &pe) {
+		var results []problem
+		for _, loc := range pe.Locations() {
+			for _, e := range pe.At(loc) {
+				text := fmt.Sprintf("%s: %s: %s", path, loc, e)
+				results = append(results, problem{key: text, text: text})
+			}
+		}
+		return results
+	}
+	if err != nil {
+		return []problem{{key: err.Error(), text: err.Error()}}
+	}
+	results := make([]problem, 0, len(file.findings))
+	for _, f := range file.findings {
+		results = append(results, problem{key: f.String(), text: file.describe(f)})
+	}
+	return results
+}
+
+// report prints the problems of the file that are new or resolved since it was last linted
+func (w *watcher) report(path string, problems []problem) {
+	prev := make(map[string]struct{}, len(w.problems[path]))
+	for _, p := range w.problems[path] {
+		prev[p.key] = struct{}{}
+	}
+	now := make(map[string]struct{}, len(problems))
+	var added, resolved []string
+	for _, p := range problems {
+		now[p.key] = struct{}{}
+		if _, exists := prev[p.key]; !exists {
+			added = append(added, p.text)
+		}
+	}
+	for _, p := range w.problems[path] {
+		if _, exists := now[p.key]; !exists {
+			resolved = append(resolved, p.text)
+		}
+	}
+	w.problems[path] = problems
+
+	fmt.Fprintf(w.env.stdout, "%s: %d new, %d resolved, %d total\n", path, len(added), len(resolved), len(problems))
+	for _, text := range added {
+		fmt.Fprintf(w.env.stdout, "+ %s\n", text)
+	}
+	for _, text := range resolved {
+		fmt.Fprintf(w.env.stdout, "- %s\n", text)
+	}
+}
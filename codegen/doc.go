@@ -0,0 +1,2 @@
+// Package codegen provides the generation of source code from parsed swagger specifications
+package codegen
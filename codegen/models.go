@@ -0,0 +1,494 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+	"github.com/valyala/fastjson"
+)
+
+// PointerPolicy defines which generated struct fields are rendered as pointers
+type PointerPolicy int
+
+const (
+	// PointerOptional renders only the optional (not required) fields as pointers
+	PointerOptional PointerPolicy = iota
+	// PointerNever renders all fields as values and relies upon omitempty for optional fields
+	PointerNever
+	// PointerAlways renders all fields as pointers
+	PointerAlways
+)
+
+// ModelOptions defines the configuration used when generating Go model types from swagger definitions
+type ModelOptions struct {
+	// PackageName is the name of the package declared by the generated source
+	PackageName string
+	// TypeName returns the Go type name for a definition name, when nil GoName is used
+	TypeName func(definition string) string
+	// FieldName returns the Go field name for a property name, when nil GoName is used
+	FieldName func(property string) string
+	// JSONTags will add `json` struct tags to each field
+	JSONTags bool
+	// XMLTags will add `xml` struct tags to each field honoring any XML object of the property
+	XMLTags bool
+	// ValidateTags will add `validate` struct tags to each field using github.com/go-playground/validator syntax
+	ValidateTags bool
+	// Pointers defines which fields are rendered as pointers, though slices, maps and any are never pointers
+	Pointers PointerPolicy
+	// Enums will generate a named type with constants for each enumerated value of any primitive schema
+	Enums bool
+}
+
+// DefaultModelOptions returns the ModelOptions used when none are specified
+func DefaultModelOptions() ModelOptions {
+	return ModelOptions{
+		PackageName: "models",
+		JSONTags:    true,
+		Enums:       true,
+	}
+}
+
+// GenerateModels returns the formatted Go source declaring a type for each of the definitions within the swagger spec
+// with the types declared in the dependency order of the definitions
+func GenerateModels(swagger *spec.Swagger, opts ModelOptions) ([]byte, error) {
+	if swagger == nil {
+		return nil, errors.New("cannot generate models from a nil swagger")
+	}
+	g := newModelGenerator(opts)
+	names := make([]string, 0, len(swagger.Definitions))
+	for name := range swagger.Definitions {
+		names = append(names, name)
+	}
+	// the names are reserved in sorted order so that any colliding type names are de-duplicated consistently
+	sort.Strings(names)
+	for _, name := range names {
+		g.typeNames[name] = g.reserve(g.opts.TypeName(name))
+	}
+	for _, group := range swagger.DefinitionsInDependencyOrder() {
+		for _, name := range group {
+			def := swagger.Definitions[name]
+			g.genType(g.typeName(name), &def)
+			g.genPending()
+		}
+	}
+	return g.source()
+}
+
+type pendingType struct {
+	name   string
+	schema *spec.Schema
+}
+
+type modelGenerator struct {
+	opts    ModelOptions
+	body    bytes.Buffer
+	imports map[string]struct{}
+	names   map[string]struct{}
+	// typeNames are the unique type names reserved for each definition
+	typeNames map[string]string
+	pending   []pendingType
+}
+
+func newModelGenerator(opts ModelOptions) *modelGenerator {
+	if opts.PackageName == "" {
+		opts.PackageName = "models"
+	}
+	if opts.TypeName == nil {
+		opts.TypeName = GoName
+	}
+	if opts.FieldName == nil {
+		opts.FieldName = GoName
+	}
+	return &modelGenerator{
+		opts:      opts,
+		imports:   make(map[string]struct{}),
+		names:     make(map[string]struct{}),
+		typeNames: make(map[string]string),
+	}
+}
+
+// source returns the complete formatted source, though if formatting fails the unformatted source is returned with the error
+func (g *modelGenerator) source() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by goats. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", g.opts.PackageName)
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for imp := range g.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		b.WriteString("import (\n")
+		for _, imp := range imports {
+			fmt.Fprintf(&b, "\t%q\n", imp)
+		}
+		b.WriteString(")\n\n")
+	}
+	b.Write(g.body.Bytes())
+	src := b.Bytes()
+	formatted, err := format.Source(src)
+	if err != nil {
+		return src, fmt.Errorf("failed to format generated models: %w", err)
+	}
+	return formatted, nil
+}
+
+// reserve will return a unique type name based upon the specified name
+func (g *modelGenerator) reserve(name string) string {
+	result := name
+	for i := 2; ; i++ {
+		if _, exists := g.names[result]; !exists {
+			break
+		}
+		result = name + strconv.Itoa(i)
+	}
+	g.names[result] = struct{}{}
+	return result
+}
+
+// typeName returns the type name reserved for the definition, or else its configured type name when it is not defined
+func (g *modelGenerator) typeName(definition string) string {
+	if name, exists := g.typeNames[definition]; exists {
+		return name
+	}
+	return g.opts.TypeName(definition)
+}
+
+func (g *modelGenerator) enqueue(name string, s *spec.Schema) string {
+	name = g.reserve(name)
+	g.pending = append(g.pending, pendingType{name: name, schema: s})
+	return name
+}
+
+func (g *modelGenerator) genPending() {
+	for len(g.pending) > 0 {
+		next := g.pending[0]
+		g.pending = g.pending[1:]
+		g.genType(next.name, next.schema)
+	}
+}
+
+func (g *modelGenerator) genType(name string, s *spec.Schema) {
+	writeComment(&g.body, "", name, s)
+	switch {
+	case g.isEnum(s):
+		g.genEnum(name, s)
+	case isStruct(s):
+		g.genStruct(name, s)
+	default:
+		fmt.Fprintf(&g.body, "type %s %s\n\n", name, g.goType(s, name))
+	}
+}
+
+func (g *modelGenerator) genEnum(name string, s *spec.Schema) {
+	fmt.Fprintf(&g.body, "type %s %s\n\n", name, g.goType(&spec.Schema{Type: s.Type, Format: s.Format}, name))
+	used := make(map[string]struct{}, len(s.Enum))
+	g.body.WriteString("const (\n")
+	for _, e := range s.Enum {
+		lit, label, ok := enumLiteral(e)
+		if !ok {
+			continue
+		}
+		suffix := camelWords(label)
+		if suffix == "" {
+			suffix = "Empty"
+		}
+		constName := name + suffix
+		for i := 2; ; i++ {
+			if _, exists := used[constName]; !exists {
+				break
+			}
+			constName = name + suffix + strconv.Itoa(i)
+		}
+		used[constName] = struct{}{}
+		fmt.Fprintf(&g.body, "\t%s %s = %s\n", constName, name, lit)
+	}
+	g.body.WriteString(")\n\n")
+}
+
+func (g *modelGenerator) genStruct(name string, s *spec.Schema) {
+	var (
+		props    = make(map[string]spec.Schema, len(s.Properties))
+		required = make(map[string]bool, len(s.Required))
+		embedded []string
+	)
+	for _, sub := range s.AllOf {
+		if defName, ok := sub.Ref.DefinitionName(); ok {
+			embedded = append(embedded, g.typeName(defName))
+			continue
+		}
+		for propName, prop := range sub.Properties {
+			props[propName] = prop
+		}
+		for _, r := range sub.Required {
+			required[r] = true
+		}
+	}
+	for propName, prop := range s.Properties {
+		props[propName] = prop
+	}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	propNames := make([]string, 0, len(props))
+	for propName := range props {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
+	fmt.Fprintf(&g.body, "type %s struct {\n", name)
+	for _, e := range embedded {
+		fmt.Fprintf(&g.body, "\t%s\n", e)
+	}
+	fieldNames := make(map[string]struct{}, len(propNames))
+	for _, propName := range propNames {
+		prop := props[propName]
+		fieldName := g.opts.FieldName(propName)
+		for i := 2; ; i++ {
+			if _, exists := fieldNames[fieldName]; !exists {
+				break
+			}
+			fieldName = g.opts.FieldName(propName) + strconv.Itoa(i)
+		}
+		fieldNames[fieldName] = struct{}{}
+		writeComment(&g.body, "\t", fieldName, &prop)
+		fieldType := g.fieldType(g.goType(&prop, name+fieldName), required[propName])
+		fmt.Fprintf(&g.body, "\t%s %s%s\n", fieldName, fieldType, g.fieldTags(propName, &prop, required[propName]))
+	}
+	g.body.WriteString("}\n\n")
+}
+
+// goType returns the Go type used for the schema, queueing any nested types to be generated with the specified name
+func (g *modelGenerator) goType(s *spec.Schema, name string) string {
+	if s == nil {
+		return "any"
+	}
+	if defName, ok := s.Ref.DefinitionName(); ok {
+		return g.typeName(defName)
+	}
+	if s.Ref != nil {
+		// only local definition references can be resolved
+		return "any"
+	}
+	if g.isEnum(s) || isStruct(s) {
+		return g.enqueue(name, s)
+	}
+	switch schemaType(s) {
+	case "array":
+		if items, ok := s.Items.AsSchema(); ok {
+			return "[]" + g.goType(items, name+"Item")
+		}
+		return "[]any"
+	case "object":
+		if ap, ok := s.AdditionalProperties.AsSchema(); ok {
+			return "map[string]" + g.goType(ap, name+"Value")
+		}
+		return "map[string]any"
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = struct{}{}
+			return "time.Time"
+		case "byte", "binary":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "file":
+		return "[]byte"
+	}
+	return "any"
+}
+
+func (g *modelGenerator) fieldType(goType string, required bool) string {
+	if goType == "any" || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") {
+		return goType
+	}
+	switch g.opts.Pointers {
+	case PointerAlways:
+		return "*" + goType
+	case PointerOptional:
+		if !required {
+			return "*" + goType
+		}
+	}
+	return goType
+}
+
+func (g *modelGenerator) fieldTags(propName string, prop *spec.Schema, required bool) string {
+	var tags []string
+	if g.opts.JSONTags {
+		v := propName
+		if !required {
+			v += ",omitempty"
+		}
+		tags = append(tags, fmt.Sprintf("json:%q", v))
+	}
+	if g.opts.XMLTags {
+		v := propName
+		if x := prop.XML; x != nil {
+			if x.Name != "" {
+				v = x.Name
+			}
+			if x.IsAttribute {
+				v += ",attr"
+			}
+		}
+		if !required {
+			v += ",omitempty"
+		}
+		tags = append(tags, fmt.Sprintf("xml:%q", v))
+	}
+	if g.opts.ValidateTags {
+		if v := validateTag(prop, required); v != "" {
+			tags = append(tags, fmt.Sprintf("validate:%q", v))
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	return " `" + strings.Join(tags, " ") + "`"
+}
+
+func (g *modelGenerator) isEnum(s *spec.Schema) bool {
+	if !g.opts.Enums || len(s.Enum) == 0 {
+		return false
+	}
+	switch schemaType(s) {
+	case "string", "integer", "number":
+		return true
+	}
+	return false
+}
+
+// validateTag returns the go-playground/validator rules for the schema or empty if there are none
+func validateTag(s *spec.Schema, required bool) string {
+	var rules []string
+	if required {
+		rules = append(rules, "required")
+	} else {
+		rules = append(rules, "omitempty")
+	}
+	switch schemaType(s) {
+	case "string":
+		if s.MinLength != nil {
+			rules = append(rules, fmt.Sprintf("min=%d", *s.MinLength))
+		}
+		if s.MaxLength != nil {
+			rules = append(rules, fmt.Sprintf("max=%d", *s.MaxLength))
+		}
+		if len(s.Enum) > 0 {
+			values := make([]string, 0, len(s.Enum))
+			for _, e := range s.Enum {
+				if _, label, ok := enumLiteral(e); ok && label != "" && !strings.ContainsAny(label, " ,|") {
+					values = append(values, label)
+				}
+			}
+			if len(values) == len(s.Enum) {
+				rules = append(rules, "oneof="+strings.Join(values, " "))
+			}
+		}
+	case "integer", "number":
+		if s.Minimum != nil {
+			op := "gte"
+			if s.ExclusiveMinimum {
+				op = "gt"
+			}
+			rules = append(rules, fmt.Sprintf("%s=%v", op, *s.Minimum))
+		}
+		if s.Maximum != nil {
+			op := "lte"
+			if s.ExclusiveMaximum {
+				op = "lt"
+			}
+			rules = append(rules, fmt.Sprintf("%s=%v", op, *s.Maximum))
+		}
+	case "array":
+		if s.MinItems != nil {
+			rules = append(rules, fmt.Sprintf("min=%d", *s.MinItems))
+		}
+		if s.MaxItems != nil {
+			rules = append(rules, fmt.Sprintf("max=%d", *s.MaxItems))
+		}
+		if s.UniqueItems {
+			rules = append(rules, "unique")
+		}
+	}
+	if !required && len(rules) == 1 {
+		return ""
+	}
+	return strings.Join(rules, ",")
+}
+
+// enumLiteral returns the Go literal and a label for naming the constant of an enumerated value
+func enumLiteral(v any) (literal string, label string, ok bool) {
+	val, isValue := v.(*fastjson.Value)
+	if !isValue || val == nil {
+		return "", "", false
+	}
+	switch val.Type() {
+	case fastjson.TypeString:
+		s := string(val.GetStringBytes())
+		return strconv.Quote(s), s, true
+	case fastjson.TypeNumber:
+		n := string(val.MarshalTo(nil))
+		return n, n, true
+	}
+	return "", "", false
+}
+
+// isStruct returns true when the schema should be rendered as a Go struct
+func isStruct(s *spec.Schema) bool {
+	return len(s.Properties) > 0 || len(s.AllOf) > 0
+}
+
+func schemaType(s *spec.Schema) string {
+	if types := s.Type.Values(); len(types) > 0 {
+		return types[0]
+	}
+	if len(s.Properties) > 0 {
+		return "object"
+	}
+	return ""
+}
+
+// writeComment will write the description or title of the schema as a Go comment for the named declaration
+func writeComment(b *bytes.Buffer, indent string, name string, s *spec.Schema) {
+	text := s.Description
+	if text == "" {
+		text = s.Title
+	}
+	if text == "" {
+		return
+	}
+	for i, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString(indent)
+		b.WriteString("//")
+		if i == 0 {
+			b.WriteString(" ")
+			b.WriteString(name)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			b.WriteString(" ")
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+}
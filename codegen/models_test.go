@@ -0,0 +1,186 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+const petstoreDefinitions = `{
+	"swagger": "2.0",
+	"info": {"title": "Petstore", "version": "1.0"},
+	"paths": {},
+	"definitions": {
+		"Pet": {
+			"description": "A pet for sale",
+			"required": ["name"],
+			"properties": {
+				"pet_id": {"type": "integer", "format": "int64"},
+				"name": {"type": "string", "minLength": 1, "xml": {"name": "petName", "attribute": true}},
+				"status": {"type": "string", "enum": ["available", "sold"]},
+				"tags": {"type": "array", "items": {"$ref": "#/definitions/Tag"}},
+				"born": {"type": "string", "format": "date-time"}
+			}
+		},
+		"Tag": {
+			"properties": {
+				"name": {"type": "string"}
+			}
+		},
+		"Dog": {
+			"allOf": [
+				{"$ref": "#/definitions/Pet"},
+				{"properties": {"barks": {"type": "boolean"}}}
+			]
+		}
+	}
+}`
+
+func TestGenerateModels(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(petstoreDefinitions)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse test spec: %s", err)
+	}
+	type testCase struct {
+		opts        ModelOptions
+		contains    []string
+		notContains []string
+	}
+	tests := map[string]testCase{
+		"default options should render json tags, optional pointers and enums": {
+			opts: DefaultModelOptions(),
+			contains: []string{
+				"package models",
+				`import (
+	"time"
+)`,
+				"// Pet A pet for sale",
+				"PetID *int64 `json:\"pet_id,omitempty\"`",
+				"Name string `json:\"name\"`",
+				"Status *PetStatus `json:\"status,omitempty\"`",
+				"Tags []Tag `json:\"tags,omitempty\"`",
+				"Born *time.Time `json:\"born,omitempty\"`",
+				"type PetStatus string",
+				`PetStatusAvailable PetStatus = "available"`,
+				`PetStatusSold      PetStatus = "sold"`,
+				"type Dog struct {\n\tPet\n\tBarks *bool `json:\"barks,omitempty\"`\n}",
+			},
+		},
+		"xml and validate tags without pointers or enums": {
+			opts: ModelOptions{
+				PackageName:  "api",
+				XMLTags:      true,
+				ValidateTags: true,
+				Pointers:     PointerNever,
+			},
+			contains: []string{
+				"package api",
+				"Name string `xml:\"petName,attr\" validate:\"required,min=1\"`",
+				"Status string `xml:\"status,omitempty\" validate:\"omitempty,oneof=available sold\"`",
+				"PetID int64 `xml:\"pet_id,omitempty\"`",
+			},
+			notContains: []string{
+				"json:",
+				"type PetStatus",
+			},
+		},
+		"custom naming should be used for types and fields": {
+			opts: ModelOptions{
+				TypeName: func(name string) string {
+					return "Model" + GoName(name)
+				},
+				FieldName: func(name string) string {
+					return "F" + GoName(name)
+				},
+				Pointers: PointerAlways,
+			},
+			contains: []string{
+				"type ModelPet struct",
+				"FName *string",
+				"FTags []ModelTag",
+			},
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			got, err := GenerateModels(swagger, tt.opts)
+			if err != nil {
+				t.Fatalf("GenerateModels() returned unexpected error: %s\n%s", err, got)
+			}
+			src := collapseSpaces(string(got))
+			for _, want := range tt.contains {
+				if !strings.Contains(src, collapseSpaces(want)) {
+					t.Errorf("GenerateModels() missing %q in:\n%s", want, src)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(src, collapseSpaces(unwanted)) {
+					t.Errorf("GenerateModels() unexpectedly contains %q in:\n%s", unwanted, src)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateModels_collidingNames(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {},
+		"definitions": {
+			"Pet": {"type": "object", "properties": {"name": {"type": "string"}}},
+			"pet": {"type": "object", "properties": {"age": {"type": "integer"}}},
+			"Owner": {"type": "object", "properties": {
+				"first": {"$ref": "#/definitions/Pet"},
+				"second": {"$ref": "#/definitions/pet"}
+			}}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse test spec: %s", err)
+	}
+	got, err := GenerateModels(swagger, DefaultModelOptions())
+	if err != nil {
+		t.Fatalf("GenerateModels() returned unexpected error: %s\n%s", err, got)
+	}
+	src := collapseSpaces(string(got))
+	for _, want := range []string{
+		"type Pet struct {\n\tName *string `json:\"name,omitempty\"`\n}",
+		"type Pet2 struct {\n\tAge *int64 `json:\"age,omitempty\"`\n}",
+		"First *Pet `json:\"first,omitempty\"`",
+		"Second *Pet2 `json:\"second,omitempty\"`",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("GenerateModels() missing %q in:\n%s", want, src)
+		}
+	}
+}
+
+// collapseSpaces removes the alignment added by gofmt so expectations can use single spaces
+func collapseSpaces(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, "\t"))
+		lines[i] = line[:indent] + strings.Join(strings.Fields(line[indent:]), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"pet_id":         "PetID",
+		"petId":          "PetID",
+		"created-at":     "CreatedAt",
+		"HTTPStatus":     "HTTPStatus",
+		"2fa_enabled":    "X2faEnabled",
+		"api_url":        "APIURL",
+		"alreadyPascal":  "AlreadyPascal",
+		"with spaces ok": "WithSpacesOk",
+	}
+	for name, expected := range tests {
+		if got := GoName(name); got != expected {
+			t.Errorf("GoName(%q) = %q, want %q", name, got, expected)
+		}
+	}
+}
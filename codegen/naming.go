@@ -0,0 +1,93 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms are the words rendered in all upper-case when they appear in Go identifiers
+var commonInitialisms = map[string]bool{
+	"ACL":   true,
+	"API":   true,
+	"ASCII": true,
+	"CPU":   true,
+	"CSS":   true,
+	"DNS":   true,
+	"EOF":   true,
+	"GUID":  true,
+	"HTML":  true,
+	"HTTP":  true,
+	"HTTPS": true,
+	"ID":    true,
+	"IP":    true,
+	"JSON":  true,
+	"QPS":   true,
+	"RAM":   true,
+	"RPC":   true,
+	"SLA":   true,
+	"SMTP":  true,
+	"SQL":   true,
+	"SSH":   true,
+	"TCP":   true,
+	"TLS":   true,
+	"TTL":   true,
+	"UDP":   true,
+	"UI":    true,
+	"UID":   true,
+	"UUID":  true,
+	"URI":   true,
+	"URL":   true,
+	"UTF8":  true,
+	"VM":    true,
+	"XML":   true,
+	"XSRF":  true,
+	"XSS":   true,
+}
+
+// GoName returns an exported Go identifier for the specified swagger name, e.g. "pet_id" becomes "PetID"
+func GoName(name string) string {
+	result := camelWords(name)
+	if result == "" {
+		return "X"
+	}
+	if r := rune(result[0]); unicode.IsDigit(r) {
+		return "X" + result
+	}
+	return result
+}
+
+// camelWords splits the name into words on any non-alphanumeric character or lower-to-upper case transition
+// then joins them back together with each word capitalized
+func camelWords(name string) string {
+	var (
+		b     strings.Builder
+		word  []rune
+		prev  rune
+		flush = func() {
+			if len(word) == 0 {
+				return
+			}
+			if w := strings.ToUpper(string(word)); commonInitialisms[w] {
+				b.WriteString(w)
+			} else {
+				word[0] = unicode.ToUpper(word[0])
+				b.WriteString(string(word))
+			}
+			word = word[:0]
+		}
+	)
+	for _, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	flush()
+	return b.String()
+}
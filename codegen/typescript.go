@@ -0,0 +1,289 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+	"github.com/valyala/fastjson"
+)
+
+// TypeScriptOptions defines the configuration used when generating TypeScript types from swagger definitions
+type TypeScriptOptions struct {
+	// TypeName returns the TypeScript type name for a definition name, when nil GoName is used
+	TypeName func(definition string) string
+	// Enums will generate a TypeScript enum for each definition that is a string enumeration, otherwise a union of
+	// the string literals is used
+	Enums bool
+	// BrandedDates will use branded string types for the date and date-time formats so they cannot be mistaken for any
+	// other string, otherwise they are plain strings
+	BrandedDates bool
+}
+
+// DefaultTypeScriptOptions returns the TypeScriptOptions used when none are specified
+func DefaultTypeScriptOptions() TypeScriptOptions {
+	return TypeScriptOptions{
+		Enums:        true,
+		BrandedDates: true,
+	}
+}
+
+const (
+	// tsDateTime is the branded type used for the date-time format
+	tsDateTime = "ISODateTime"
+	// tsDate is the branded type used for the date format
+	tsDate = "ISODate"
+)
+
+// tsIdentifier matches the property names that need not be quoted
+var tsIdentifier = regexp.MustCompile(`^[_$A-Za-z][_$0-9A-Za-z]*$`)
+
+// GenerateTypeScript returns TypeScript source exporting a type for each of the definitions within the swagger spec in
+// the order of their names. Objects become interfaces extending any definitions referenced within their allOf, where
+// properties that are not required are optional and read-only properties are readonly.
+func GenerateTypeScript(swagger *spec.Swagger, opts TypeScriptOptions) ([]byte, error) {
+	if swagger == nil {
+		return nil, errors.New("cannot generate TypeScript from a nil swagger")
+	}
+	if opts.TypeName == nil {
+		opts.TypeName = GoName
+	}
+	g := &tsGenerator{opts: opts}
+	names := make([]string, 0, len(swagger.Definitions))
+	for name := range swagger.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := swagger.Definitions[name]
+		g.genType(g.opts.TypeName(name), &def)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by goats. DO NOT EDIT.\n\n")
+	if g.dateTime {
+		fmt.Fprintf(&b, "/** An RFC 3339 date-time string */\nexport type %s = string & { readonly __brand: %q };\n\n", tsDateTime, tsDateTime)
+	}
+	if g.date {
+		fmt.Fprintf(&b, "/** An RFC 3339 full-date string */\nexport type %s = string & { readonly __brand: %q };\n\n", tsDate, tsDate)
+	}
+	b.Write(g.body.Bytes())
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+type tsGenerator struct {
+	opts     TypeScriptOptions
+	body     bytes.Buffer
+	dateTime bool
+	date     bool
+}
+
+func (g *tsGenerator) genType(name string, s *spec.Schema) {
+	writeTSComment(&g.body, "", s)
+	switch {
+	case g.opts.Enums && isStringEnum(s):
+		fmt.Fprintf(&g.body, "export enum %s {\n", name)
+		used := make(map[string]struct{}, len(s.Enum))
+		for _, e := range s.Enum {
+			lit, label, _ := enumLiteral(e)
+			member := camelWords(label)
+			if member == "" {
+				member = "Empty"
+			} else if !tsIdentifier.MatchString(member) {
+				member = "X" + member
+			}
+			base := member
+			for i := 2; ; i++ {
+				if _, exists := used[member]; !exists {
+					break
+				}
+				member = base + strconv.Itoa(i)
+			}
+			used[member] = struct{}{}
+			fmt.Fprintf(&g.body, "\t%s = %s,\n", member, lit)
+		}
+		g.body.WriteString("}\n\n")
+	case isStruct(s) && schemaType(s) != "array":
+		var extends []string
+		for _, sub := range s.AllOf {
+			if defName, ok := sub.Ref.DefinitionName(); ok {
+				extends = append(extends, g.opts.TypeName(defName))
+			}
+		}
+		fmt.Fprintf(&g.body, "export interface %s ", name)
+		if len(extends) > 0 {
+			fmt.Fprintf(&g.body, "extends %s ", strings.Join(extends, ", "))
+		}
+		g.writeObject(s, "")
+		g.body.WriteString("\n\n")
+	default:
+		fmt.Fprintf(&g.body, "export type %s = %s;\n\n", name, g.tsType(s, ""))
+	}
+}
+
+// writeObject writes the object type literal of the properties of the schema, including those declared inline within
+// its allOf, where nested lines are indented by one more tab than the indent
+func (g *tsGenerator) writeObject(s *spec.Schema, indent string) {
+	var (
+		props    = make(map[string]spec.Schema, len(s.Properties))
+		required = make(map[string]bool, len(s.Required))
+	)
+	for _, sub := range s.AllOf {
+		if sub.Ref != nil {
+			continue
+		}
+		for propName, prop := range sub.Properties {
+			props[propName] = prop
+		}
+		for _, r := range sub.Required {
+			required[r] = true
+		}
+	}
+	for propName, prop := range s.Properties {
+		props[propName] = prop
+	}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	propNames := make([]string, 0, len(props))
+	for propName := range props {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
+	g.body.WriteString("{\n")
+	for _, propName := range propNames {
+		prop := props[propName]
+		writeTSComment(&g.body, indent+"\t", &prop)
+		g.body.WriteString(indent + "\t")
+		if prop.IsReadOnly {
+			g.body.WriteString("readonly ")
+		}
+		if tsIdentifier.MatchString(propName) {
+			g.body.WriteString(propName)
+		} else {
+			g.body.WriteString(strconv.Quote(propName))
+		}
+		if !required[propName] {
+			g.body.WriteString("?")
+		}
+		g.body.WriteString(": " + g.tsType(&prop, indent+"\t") + ";\n")
+	}
+	if ap, ok := s.AdditionalProperties.AsSchema(); ok && len(props) == 0 {
+		fmt.Fprintf(&g.body, "%s\t[key: string]: %s;\n", indent, g.tsType(ap, indent+"\t"))
+	}
+	g.body.WriteString(indent + "}")
+}
+
+// tsType returns the TypeScript type used for the schema, where any nested object literal is written with the indent
+func (g *tsGenerator) tsType(s *spec.Schema, indent string) string {
+	if s == nil {
+		return "unknown"
+	}
+	if defName, ok := s.Ref.DefinitionName(); ok {
+		return g.opts.TypeName(defName)
+	}
+	if s.Ref != nil {
+		// only local definition references can be resolved
+		return "unknown"
+	}
+	if len(s.Enum) > 0 {
+		var literals []string
+		for _, e := range s.Enum {
+			if lit, _, ok := enumLiteral(e); ok {
+				literals = append(literals, lit)
+			}
+		}
+		if len(literals) == len(s.Enum) {
+			return strings.Join(literals, " | ")
+		}
+	}
+	if isStruct(s) && schemaType(s) != "array" {
+		// write the nested object literal to a separate buffer as this type is written after the name of its property
+		nested := &tsGenerator{opts: g.opts}
+		nested.writeObject(s, indent)
+		g.dateTime = g.dateTime || nested.dateTime
+		g.date = g.date || nested.date
+		return nested.body.String()
+	}
+	switch schemaType(s) {
+	case "array":
+		items, ok := s.Items.AsSchema()
+		if !ok {
+			return "unknown[]"
+		}
+		itemType := g.tsType(items, indent)
+		if strings.Contains(itemType, " | ") {
+			return "Array<" + itemType + ">"
+		}
+		return itemType + "[]"
+	case "object":
+		if ap, ok := s.AdditionalProperties.AsSchema(); ok {
+			return "Record<string, " + g.tsType(ap, indent) + ">"
+		}
+		return "Record<string, unknown>"
+	case "string":
+		if g.opts.BrandedDates {
+			switch s.Format {
+			case "date-time":
+				g.dateTime = true
+				return tsDateTime
+			case "date":
+				g.date = true
+				return tsDate
+			}
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "file":
+		return "Blob"
+	}
+	return "unknown"
+}
+
+// isStringEnum returns true when the schema is a string enumeration
+func isStringEnum(s *spec.Schema) bool {
+	if len(s.Enum) == 0 || schemaType(s) != "string" {
+		return false
+	}
+	for _, e := range s.Enum {
+		if val, ok := e.(*fastjson.Value); !ok || val == nil || val.Type() != fastjson.TypeString {
+			return false
+		}
+	}
+	return true
+}
+
+// writeTSComment will write the description or title of the schema as a TSDoc comment
+func writeTSComment(b *bytes.Buffer, indent string, s *spec.Schema) {
+	text := s.Description
+	if text == "" {
+		text = s.Title
+	}
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+	text = strings.ReplaceAll(text, "*/", "*\\/")
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, text)
+		return
+	}
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			b.WriteString(indent + " * " + line + "\n")
+		} else {
+			b.WriteString(indent + " *\n")
+		}
+	}
+	b.WriteString(indent + " */\n")
+}
@@ -0,0 +1,94 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestGenerateTypeScript(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(petstoreDefinitions)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	got, err := GenerateTypeScript(swagger, DefaultTypeScriptOptions())
+	if err != nil {
+		t.Fatalf("GenerateTypeScript() unexpected error: %s", err)
+	}
+	expected := `// Code generated by goats. DO NOT EDIT.
+
+/** An RFC 3339 date-time string */
+export type ISODateTime = string & { readonly __brand: "ISODateTime" };
+
+export interface Dog extends Pet {
+	barks?: boolean;
+}
+
+/** A pet for sale */
+export interface Pet {
+	born?: ISODateTime;
+	name: string;
+	pet_id?: number;
+	status?: "available" | "sold";
+	tags?: Tag[];
+}
+
+export interface Tag {
+	name?: string;
+}
+`
+	if string(got) != expected {
+		t.Errorf("GenerateTypeScript() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestGenerateTypeScript_options(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {},
+		"definitions": {
+			"Status": {"type": "string", "enum": ["in-stock", "sold"]},
+			"Labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"Event": {
+				"required": ["at"],
+				"properties": {
+					"id": {"type": "string", "readOnly": true},
+					"at": {"type": "string", "format": "date"},
+					"content-type": {"type": "string"},
+					"source": {"type": "object", "properties": {"host": {"type": "string"}}, "required": ["host"]},
+					"codes": {"type": "array", "items": {"type": "integer", "enum": [1, 2]}}
+				}
+			}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	got, err := GenerateTypeScript(swagger, TypeScriptOptions{Enums: true})
+	if err != nil {
+		t.Fatalf("GenerateTypeScript() unexpected error: %s", err)
+	}
+	expected := `// Code generated by goats. DO NOT EDIT.
+
+export interface Event {
+	at: string;
+	codes?: Array<1 | 2>;
+	"content-type"?: string;
+	readonly id?: string;
+	source?: {
+		host: string;
+	};
+}
+
+export type Labels = Record<string, string>;
+
+export enum Status {
+	InStock = "in-stock",
+	Sold = "sold",
+}
+`
+	if string(got) != expected {
+		t.Errorf("GenerateTypeScript() =\n%s\nwant\n%s", got, expected)
+	}
+}
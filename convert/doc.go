@@ -0,0 +1,2 @@
+// Package convert provides the conversion of parsed swagger specifications to and from other formats
+package convert
@@ -0,0 +1,482 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/erraggy/goats/codegen"
+	"github.com/erraggy/goats/spec"
+	"github.com/valyala/fastjson"
+)
+
+// GraphQLOptions defines the configuration used when converting a swagger spec to a GraphQL schema
+type GraphQLOptions struct {
+	// TypeName returns the GraphQL type name for a definition name, when nil codegen.GoName is used
+	TypeName func(definition string) string
+	// FieldName returns the name of the Query or Mutation field of an operation, when nil its operationId is used in
+	// lower camel case, or else its method and path when it has none
+	FieldName func(op *spec.Operation) string
+}
+
+// graphQLName matches the names allowed by GraphQL for types, fields, arguments and enum values
+var graphQLName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// graphQLJSON is the custom scalar used for any schema without an equivalent GraphQL type
+const graphQLJSON = "JSON"
+
+// ToGraphQLSDL returns a GraphQL schema in the Schema Definition Language with an object type for each object
+// definition and an enum for each string enumeration definition, where other definitions are inlined as the type they
+// declare. Each GET operation becomes a field of Query and every other operation a field of Mutation, whose arguments
+// are the parameters of the operation and whose result is its first successful response. Body parameters use input
+// types generated from the definitions they reference, and anything without an equivalent GraphQL type, such as maps
+// or free-form objects, uses a JSON scalar.
+func ToGraphQLSDL(swagger *spec.Swagger, opts GraphQLOptions) ([]byte, error) {
+	if swagger == nil {
+		return nil, errors.New("cannot convert a nil swagger to GraphQL")
+	}
+	g := newGraphQLGenerator(swagger, opts)
+	var queries, mutations bytes.Buffer
+	queryFields := map[string]struct{}{}
+	mutationFields := map[string]struct{}{}
+	for _, op := range swagger.Operations() {
+		if strings.EqualFold(op.Key.Method, http.MethodGet) {
+			g.genOperation(&queries, queryFields, op)
+		} else {
+			g.genOperation(&mutations, mutationFields, op)
+		}
+	}
+	names := make([]string, 0, len(swagger.Definitions))
+	for name := range swagger.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := swagger.Definitions[name]
+		typeName := g.opts.TypeName(name)
+		switch {
+		case isGraphQLObject(&def):
+			g.genObject(typeName, &def, false)
+		case isGraphQLEnum(&def):
+			g.genEnum(typeName, &def)
+		}
+		g.genPending()
+	}
+	g.genPending()
+
+	var b bytes.Buffer
+	if queries.Len() > 0 {
+		fmt.Fprintf(&b, "type Query {\n%s}\n\n", queries.Bytes())
+	}
+	if mutations.Len() > 0 {
+		fmt.Fprintf(&b, "type Mutation {\n%s}\n\n", mutations.Bytes())
+	}
+	b.Write(g.types.Bytes())
+	b.Write(g.inputs.Bytes())
+	if g.usesJSON {
+		fmt.Fprintf(&b, "scalar %s\n\n", graphQLJSON)
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+type graphQLPending struct {
+	name   string
+	schema *spec.Schema
+	input  bool
+}
+
+type graphQLGenerator struct {
+	swagger *spec.Swagger
+	opts    GraphQLOptions
+	types   bytes.Buffer
+	inputs  bytes.Buffer
+	names   map[string]struct{}
+	// inputTypes are the names of the input types of each definition referenced by a body parameter
+	inputTypes map[string]string
+	pending    []graphQLPending
+	usesJSON   bool
+}
+
+func newGraphQLGenerator(swagger *spec.Swagger, opts GraphQLOptions) *graphQLGenerator {
+	if opts.TypeName == nil {
+		opts.TypeName = codegen.GoName
+	}
+	if opts.FieldName == nil {
+		opts.FieldName = graphQLFieldName
+	}
+	g := &graphQLGenerator{
+		swagger:    swagger,
+		opts:       opts,
+		names:      map[string]struct{}{"Query": {}, "Mutation": {}, graphQLJSON: {}},
+		inputTypes: make(map[string]string),
+	}
+	for name, def := range swagger.Definitions {
+		def := def
+		if isGraphQLObject(&def) || isGraphQLEnum(&def) {
+			g.names[opts.TypeName(name)] = struct{}{}
+		}
+	}
+	return g
+}
+
+// graphQLFieldName returns the operationId of the operation in lower camel case, or else its method and path
+func graphQLFieldName(op *spec.Operation) string {
+	if op.ID != "" {
+		return lowerCamel(codegen.GoName(op.ID))
+	}
+	return strings.ToLower(op.Key.Method) + codegen.GoName(op.Key.Path)
+}
+
+// reserve will return a unique type name based upon the specified name
+func (g *graphQLGenerator) reserve(name string) string {
+	result := name
+	for i := 2; ; i++ {
+		if _, exists := g.names[result]; !exists {
+			break
+		}
+		result = name + strconv.Itoa(i)
+	}
+	g.names[result] = struct{}{}
+	return result
+}
+
+func (g *graphQLGenerator) enqueue(name string, s *spec.Schema, input bool) string {
+	name = g.reserve(name)
+	g.pending = append(g.pending, graphQLPending{name: name, schema: s, input: input})
+	return name
+}
+
+func (g *graphQLGenerator) genPending() {
+	for len(g.pending) > 0 {
+		next := g.pending[0]
+		g.pending = g.pending[1:]
+		if isGraphQLEnum(next.schema) && !isGraphQLObject(next.schema) {
+			g.genEnum(next.name, next.schema)
+		} else {
+			g.genObject(next.name, next.schema, next.input)
+		}
+	}
+}
+
+func (g *graphQLGenerator) genOperation(b *bytes.Buffer, used map[string]struct{}, op *spec.Operation) {
+	name := g.opts.FieldName(op)
+	if !graphQLName.MatchString(name) {
+		name = lowerCamel(codegen.GoName(name))
+	}
+	base := name
+	for i := 2; ; i++ {
+		if _, exists := used[name]; !exists {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+	used[name] = struct{}{}
+
+	description := op.Summary
+	if description == "" {
+		description = op.Description
+	}
+	writeGraphQLDescription(b, "\t", description)
+	var args []string
+	for _, param := range g.operationParameters(op) {
+		var typ string
+		if param.In == spec.InBody {
+			typ = g.typeOf(param.Schema, codegen.GoName(name)+"Input", true)
+		} else {
+			typ = g.parameterType(param.Type, param.Items)
+		}
+		if param.Required || param.In == spec.InPath {
+			typ += "!"
+		}
+		args = append(args, graphQLFieldKey(param.Name)+": "+typ)
+	}
+	b.WriteString("\t" + name)
+	if len(args) > 0 {
+		b.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	b.WriteString(": " + g.resultType(op, codegen.GoName(name)+"Result") + "\n")
+}
+
+// operationParameters returns the parameters of the operation along with those of its PathItem it does not override
+func (g *graphQLGenerator) operationParameters(op *spec.Operation) []spec.Parameter {
+	return op.EffectiveParameters(g.swagger.Paths.Items[op.Key.Path], g.swagger.Parameters)
+}
+
+// resultType returns the type of the schema of the first successful response, or else the default response, where
+// operations without any response schema return a Boolean
+func (g *graphQLGenerator) resultType(op *spec.Operation, name string) string {
+	codes := make([]int, 0, len(op.Responses.ByStatusCode))
+	for code := range op.Responses.ByStatusCode {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		if r := op.Responses.ByStatusCode[code]; code >= 200 && code < 300 && r != nil && r.Schema != nil {
+			return g.typeOf(r.Schema, name, false)
+		}
+	}
+	if r := op.Responses.Default; r != nil && r.Schema != nil {
+		return g.typeOf(r.Schema, name, false)
+	}
+	return "Boolean"
+}
+
+// typeOf returns the nullable GraphQL type used for the schema, queueing any nested types to be generated with the
+// specified name
+func (g *graphQLGenerator) typeOf(s *spec.Schema, name string, input bool) string {
+	return g.typeOfVisiting(s, name, input, map[string]bool{})
+}
+
+func (g *graphQLGenerator) typeOfVisiting(s *spec.Schema, name string, input bool, visiting map[string]bool) string {
+	if s == nil {
+		return g.jsonType()
+	}
+	if defName, ok := s.Ref.DefinitionName(); ok {
+		def, defined := g.swagger.Definitions[defName]
+		if !defined || visiting[defName] {
+			return g.jsonType()
+		}
+		switch {
+		case isGraphQLObject(&def):
+			if input {
+				return g.inputType(defName, &def)
+			}
+			return g.opts.TypeName(defName)
+		case isGraphQLEnum(&def):
+			return g.opts.TypeName(defName)
+		}
+		// other definitions are inlined as the type they declare
+		visiting[defName] = true
+		return g.typeOfVisiting(&def, g.opts.TypeName(defName), input, visiting)
+	}
+	if s.Ref != nil {
+		// only local definition references can be resolved
+		return g.jsonType()
+	}
+	if isGraphQLObject(s) {
+		return g.enqueue(name, s, input)
+	}
+	if isGraphQLEnum(s) {
+		return g.enqueue(name, s, false)
+	}
+	switch schemaTypeOf(s) {
+	case "array":
+		if items, ok := s.Items.AsSchema(); ok {
+			return "[" + g.typeOfVisiting(items, name+"Item", input, visiting) + "]"
+		}
+		return "[" + g.jsonType() + "]"
+	case "string", "file":
+		return "String"
+	case "integer":
+		return "Int"
+	case "number":
+		return "Float"
+	case "boolean":
+		return "Boolean"
+	}
+	return g.jsonType()
+}
+
+// inputType returns the name of the input type of the definition, queueing it to be generated the first time
+func (g *graphQLGenerator) inputType(defName string, def *spec.Schema) string {
+	if name, exists := g.inputTypes[defName]; exists {
+		return name
+	}
+	name := g.enqueue(g.opts.TypeName(defName)+"Input", def, true)
+	g.inputTypes[defName] = name
+	return name
+}
+
+// parameterType returns the GraphQL type used for a parameter or items that is not in the body
+func (g *graphQLGenerator) parameterType(typ string, items *spec.Items) string {
+	switch typ {
+	case "array":
+		if items == nil {
+			return "[String]"
+		}
+		return "[" + g.parameterType(items.Type, items.Items) + "]"
+	case "integer":
+		return "Int"
+	case "number":
+		return "Float"
+	case "boolean":
+		return "Boolean"
+	}
+	return "String"
+}
+
+func (g *graphQLGenerator) jsonType() string {
+	g.usesJSON = true
+	return graphQLJSON
+}
+
+func (g *graphQLGenerator) genObject(name string, s *spec.Schema, input bool) {
+	props, required := g.flatten(s, map[*spec.Schema]bool{})
+	b, keyword := &g.types, "type"
+	if input {
+		b, keyword = &g.inputs, "input"
+	}
+	writeGraphQLDescription(b, "", schemaDescription(s))
+	if len(props) == 0 {
+		// object types must declare at least one field
+		fmt.Fprintf(b, "scalar %s\n\n", name)
+		return
+	}
+	propNames := make([]string, 0, len(props))
+	for propName := range props {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+	fmt.Fprintf(b, "%s %s {\n", keyword, name)
+	used := make(map[string]struct{}, len(propNames))
+	for _, propName := range propNames {
+		prop := props[propName]
+		fieldName := graphQLFieldKey(propName)
+		for i := 2; ; i++ {
+			if _, exists := used[fieldName]; !exists {
+				break
+			}
+			fieldName = graphQLFieldKey(propName) + strconv.Itoa(i)
+		}
+		used[fieldName] = struct{}{}
+		typ := g.typeOf(&prop, name+codegen.GoName(propName), input)
+		if required[propName] {
+			typ += "!"
+		}
+		writeGraphQLDescription(b, "\t", schemaDescription(&prop))
+		fmt.Fprintf(b, "\t%s: %s\n", fieldName, typ)
+	}
+	b.WriteString("}\n\n")
+}
+
+// flatten returns the properties of the object schema including those of its allOf, where referenced definitions
+// are included only once so that cyclic allOf references end
+func (g *graphQLGenerator) flatten(s *spec.Schema, seen map[*spec.Schema]bool) (map[string]spec.Schema, map[string]bool) {
+	props := make(map[string]spec.Schema, len(s.Properties))
+	required := make(map[string]bool, len(s.Required))
+	if seen[s] {
+		return props, required
+	}
+	seen[s] = true
+	for i := range s.AllOf {
+		sub := &s.AllOf[i]
+		if defName, ok := sub.Ref.DefinitionName(); ok {
+			def, defined := g.swagger.Definitions[defName]
+			if !defined {
+				continue
+			}
+			sub = &def
+		}
+		subProps, subRequired := g.flatten(sub, seen)
+		for propName, prop := range subProps {
+			props[propName] = prop
+		}
+		for propName := range subRequired {
+			required[propName] = true
+		}
+	}
+	for propName, prop := range s.Properties {
+		props[propName] = prop
+	}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	return props, required
+}
+
+func (g *graphQLGenerator) genEnum(name string, s *spec.Schema) {
+	writeGraphQLDescription(&g.types, "", schemaDescription(s))
+	fmt.Fprintf(&g.types, "enum %s {\n", name)
+	for _, e := range s.Enum {
+		v, _ := enumString(e)
+		fmt.Fprintf(&g.types, "\t%s\n", v)
+	}
+	g.types.WriteString("}\n\n")
+}
+
+// isGraphQLObject returns true if the schema is an object with properties, directly or within its allOf
+func isGraphQLObject(s *spec.Schema) bool {
+	typ := schemaTypeOf(s)
+	return (typ == "" || typ == "object") && (len(s.Properties) > 0 || len(s.AllOf) > 0)
+}
+
+// isGraphQLEnum returns true if the schema is a string enumeration whose values are all valid GraphQL enum values
+func isGraphQLEnum(s *spec.Schema) bool {
+	if len(s.Enum) == 0 || schemaTypeOf(s) != "string" {
+		return false
+	}
+	for _, e := range s.Enum {
+		v, ok := enumString(e)
+		if !ok || !graphQLName.MatchString(v) || v == "true" || v == "false" || v == "null" {
+			return false
+		}
+	}
+	return true
+}
+
+// enumString returns the value of the enumerated JSON string
+func enumString(e any) (string, bool) {
+	val, ok := e.(*fastjson.Value)
+	if !ok || val == nil || val.Type() != fastjson.TypeString {
+		return "", false
+	}
+	return string(val.GetStringBytes()), true
+}
+
+func schemaTypeOf(s *spec.Schema) string {
+	if types := s.Type.Values(); len(types) > 0 {
+		return types[0]
+	}
+	if len(s.Properties) > 0 {
+		return "object"
+	}
+	return ""
+}
+
+func schemaDescription(s *spec.Schema) string {
+	if s.Description != "" {
+		return s.Description
+	}
+	return s.Title
+}
+
+// graphQLFieldKey returns the name unchanged when it is already a valid GraphQL name or else in lower camel case
+func graphQLFieldKey(name string) string {
+	if graphQLName.MatchString(name) {
+		return name
+	}
+	return lowerCamel(codegen.GoName(name))
+}
+
+// lowerCamel returns the Go identifier with its leading upper-case word, or initialism, in lower-case
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsUpper(r) {
+			break
+		}
+		// keep the last upper-case letter of an initialism followed by another word, e.g. "HTTPServer" is "httpServer"
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return string(runes)
+}
+
+// writeGraphQLDescription writes the text as the GraphQL description of the following declaration
+func writeGraphQLDescription(b *bytes.Buffer, indent string, text string) {
+	if text == "" || !utf8.ValidString(text) {
+		return
+	}
+	quoted, _ := json.Marshal(text)
+	fmt.Fprintf(b, "%s%s\n", indent, quoted)
+}
@@ -0,0 +1,127 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestToGraphQLSDL(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"operationId": "list_pets",
+					"summary": "Lists the pets",
+					"parameters": [
+						{"name": "limit", "in": "query", "type": "integer"},
+						{"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}}
+					],
+					"responses": {"200": {"description": "ok", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}}
+				},
+				"post": {
+					"operationId": "createPet",
+					"parameters": [{"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}],
+					"responses": {"201": {"description": "created", "schema": {"$ref": "#/definitions/Pet"}}}
+				}
+			},
+			"/pets/{pet-id}": {
+				"parameters": [{"name": "pet-id", "in": "path", "required": true, "type": "string"}],
+				"delete": {"responses": {"204": {"description": "deleted"}}}
+			}
+		},
+		"definitions": {
+			"Pet": {
+				"description": "A pet",
+				"type": "object",
+				"required": ["name"],
+				"allOf": [{"$ref": "#/definitions/Named"}],
+				"properties": {
+					"id": {"$ref": "#/definitions/ID"},
+					"kind": {"$ref": "#/definitions/Kind"},
+					"owner": {"type": "object", "properties": {"email": {"type": "string"}}},
+					"attributes": {"type": "object", "additionalProperties": {"type": "string"}},
+					"weight": {"type": "number"}
+				}
+			},
+			"Named": {"type": "object", "properties": {"name": {"type": "string"}}},
+			"ID": {"type": "integer", "format": "int64"},
+			"Kind": {"type": "string", "enum": ["cat", "dog"]}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	got, err := ToGraphQLSDL(swagger, GraphQLOptions{})
+	if err != nil {
+		t.Fatalf("ToGraphQLSDL() unexpected error: %s", err)
+	}
+	expected := `type Query {
+	"Lists the pets"
+	listPets(limit: Int, tags: [String]): [Pet]
+}
+
+type Mutation {
+	createPet(pet: PetInput!): Pet
+	deletePetsPetID(petID: String!): Boolean
+}
+
+enum Kind {
+	cat
+	dog
+}
+
+type Named {
+	name: String
+}
+
+"A pet"
+type Pet {
+	attributes: JSON
+	id: Int
+	kind: Kind
+	name: String!
+	owner: PetOwner
+	weight: Float
+}
+
+type PetOwner {
+	email: String
+}
+
+"A pet"
+input PetInput {
+	attributes: JSON
+	id: Int
+	kind: Kind
+	name: String!
+	owner: PetInputOwner
+	weight: Float
+}
+
+input PetInputOwner {
+	email: String
+}
+
+scalar JSON
+`
+	if string(got) != expected {
+		t.Errorf("ToGraphQLSDL() =\n%s\nwant\n%s", got, expected)
+	}
+
+	renamed, err := ToGraphQLSDL(swagger, GraphQLOptions{
+		TypeName:  func(name string) string { return "Api" + name },
+		FieldName: func(op *spec.Operation) string { return strings.ToLower(op.Key.Method) + "_" + op.ID },
+	})
+	if err != nil {
+		t.Fatalf("ToGraphQLSDL() unexpected error: %s", err)
+	}
+	for _, want := range []string{"get_list_pets(", "post_createPet(pet: ApiPetInput!): ApiPet", "type ApiPet {", "enum ApiKind {"} {
+		if !strings.Contains(string(renamed), want) {
+			t.Errorf("ToGraphQLSDL() with naming options is missing %q:\n%s", want, renamed)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// harEntry is the subset of an HTTP Archive entry used to synthesize a spec
+type harEntry struct {
+	Request struct {
+		Method   string `json:"method"`
+		URL      string `json:"url"`
+		PostData *struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Status  int `json:"status"`
+		Content struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+// idSegment matches path segments that are most likely identifiers, being numbers or UUIDs
+var idSegment = regexp.MustCompile(`^([0-9]+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+
+// FromHAR returns a draft swagger spec describing the requests and responses within the HTTP Archive. Path segments
+// that are numbers or UUIDs become path parameters, query parameters are required only when sent with every request
+// of the operation, and schemas are inferred from JSON bodies.
+func FromHAR(har []byte) (*spec.Swagger, error) {
+	var archive struct {
+		Log struct {
+			Entries []harEntry `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(har, &archive); err != nil {
+		return nil, fmt.Errorf("invalid HAR: %w", err)
+	}
+	swagger := spec.NewSwagger()
+	swagger.Swagger = "2.0"
+	swagger.Info = *spec.NewInfo()
+	swagger.Info.Title = "Draft"
+	swagger.Info.Version = "0.0.0"
+
+	type observed struct {
+		op      *spec.Operation
+		count   int
+		queries map[string]int
+	}
+	var (
+		ops     = make(map[spec.OperationKey]*observed)
+		order   []spec.OperationKey
+		schemes = make(map[string]struct{})
+	)
+	for i, entry := range archive.Log.Entries {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HAR: entry %d has an invalid url: %w", i, err)
+		}
+		if swagger.Host == "" {
+			swagger.Host = u.Host
+		}
+		if u.Scheme != "" {
+			schemes[u.Scheme] = struct{}{}
+		}
+		path, pathParams := templatePath(u.Path)
+		key := spec.OperationKey{Path: path, Method: strings.ToUpper(entry.Request.Method)}
+		obs := ops[key]
+		if obs == nil {
+			op := spec.NewOperation(key.Path, key.Method)
+			op.Responses = *spec.NewResponses()
+			for _, name := range pathParams {
+				param := spec.NewParameter()
+				param.Name, param.In, param.Type, param.Required = name, spec.InPath, "string", true
+				op.Parameters = append(op.Parameters, *param)
+			}
+			obs = &observed{op: op, queries: make(map[string]int)}
+			ops[key] = obs
+			order = append(order, key)
+		}
+		obs.count++
+		for name := range u.Query() {
+			obs.queries[name]++
+		}
+		if pd := entry.Request.PostData; pd != nil && isJSON(pd.MimeType) {
+			var body any
+			if json.Unmarshal([]byte(pd.Text), &body) == nil {
+				obs.op.Consumes = appendUnique(obs.op.Consumes, mediaType(pd.MimeType))
+				addBodyParameter(obs.op, inferSchema(body))
+			}
+		}
+		addResponse(obs.op, entry)
+	}
+
+	for _, key := range order {
+		obs := ops[key]
+		names := make([]string, 0, len(obs.queries))
+		for name := range obs.queries {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			param := spec.NewParameter()
+			param.Name, param.In, param.Type = name, spec.InQuery, "string"
+			param.Required = obs.queries[name] == obs.count
+			obs.op.Parameters = append(obs.op.Parameters, *param)
+		}
+		swagger.AddOperation(obs.op)
+	}
+	for scheme := range schemes {
+		swagger.Schemes = append(swagger.Schemes, scheme)
+	}
+	sort.Strings(swagger.Schemes)
+	return swagger, nil
+}
+
+// templatePath returns the path with identifier segments replaced by path parameters named after the segment
+// preceding them, along with the names of those parameters
+func templatePath(path string) (string, []string) {
+	segs := strings.Split(path, "/")
+	var names []string
+	for i, seg := range segs {
+		if !idSegment.MatchString(seg) {
+			continue
+		}
+		name := "id"
+		if i > 0 && segs[i-1] != "" && !strings.HasPrefix(segs[i-1], "{") {
+			name = strings.TrimSuffix(segs[i-1], "s") + "Id"
+		}
+		for n := 2; contains(names, name); n++ {
+			name = fmt.Sprintf("%s%d", strings.TrimRight(name, "0123456789"), n)
+		}
+		names = append(names, name)
+		segs[i] = "{" + name + "}"
+	}
+	return strings.Join(segs, "/"), names
+}
+
+func addBodyParameter(op *spec.Operation, schema *spec.Schema) {
+	for i := range op.Parameters {
+		if op.Parameters[i].In == spec.InBody {
+			op.Parameters[i].Schema = mergeSchemas(op.Parameters[i].Schema, schema)
+			return
+		}
+	}
+	param := spec.NewParameter()
+	param.Name, param.In, param.Required, param.Schema = "body", spec.InBody, true, schema
+	op.Parameters = append(op.Parameters, *param)
+}
+
+func addResponse(op *spec.Operation, entry harEntry) {
+	status := entry.Response.Status
+	if status < 100 || status > 599 {
+		return
+	}
+	resp := op.Responses.ByStatusCode[status]
+	if resp == nil {
+		resp = spec.NewResponse()
+		resp.Description = http.StatusText(status)
+		op.Responses.ByStatusCode[status] = resp
+	}
+	content := entry.Response.Content
+	if !isJSON(content.MimeType) {
+		return
+	}
+	var body any
+	if json.Unmarshal([]byte(content.Text), &body) == nil {
+		op.Produces = appendUnique(op.Produces, mediaType(content.MimeType))
+		resp.Schema = mergeSchemas(resp.Schema, inferSchema(body))
+	}
+}
+
+// inferSchema returns the schema of the JSON value decoded by encoding/json or nil if it is null
+func inferSchema(v any) *spec.Schema {
+	schema := spec.NewSchema()
+	switch t := v.(type) {
+	case map[string]any:
+		schema.Type = spec.NewStringOrStrings("object")
+		for name, prop := range t {
+			if ps := inferSchema(prop); ps != nil {
+				if schema.Properties == nil {
+					schema.Properties = make(map[string]spec.Schema, len(t))
+				}
+				schema.Properties[name] = *ps
+			}
+		}
+	case []any:
+		schema.Type = spec.NewStringOrStrings("array")
+		var items *spec.Schema
+		for _, item := range t {
+			items = mergeSchemas(items, inferSchema(item))
+		}
+		if items == nil {
+			items = spec.NewSchema()
+		}
+		schema.Items = spec.NewSchemaOrSchemas(*items)
+	case string:
+		schema.Type = spec.NewStringOrStrings("string")
+	case float64:
+		if t == float64(int64(t)) {
+			schema.Type = spec.NewStringOrStrings("integer")
+		} else {
+			schema.Type = spec.NewStringOrStrings("number")
+		}
+	case bool:
+		schema.Type = spec.NewStringOrStrings("boolean")
+	default:
+		return nil
+	}
+	return schema
+}
+
+// mergeSchemas returns the union of the properties of both object schemas, otherwise the first non-nil schema where
+// an integer widens to a number
+func mergeSchemas(a, b *spec.Schema) *spec.Schema {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	aType, bType := a.Type.Values(), b.Type.Values()
+	if len(aType) != 1 || len(bType) != 1 {
+		return a
+	}
+	switch {
+	case aType[0] == "object" && bType[0] == "object":
+		for name, prop := range b.Properties {
+			if existing, exists := a.Properties[name]; exists {
+				prop = *mergeSchemas(&existing, &prop)
+			}
+			if a.Properties == nil {
+				a.Properties = make(map[string]spec.Schema, len(b.Properties))
+			}
+			a.Properties[name] = prop
+		}
+	case aType[0] == "array" && bType[0] == "array":
+		aItems, _ := a.Items.AsSchema()
+		bItems, _ := b.Items.AsSchema()
+		if items := mergeSchemas(aItems, bItems); items != nil {
+			a.Items = spec.NewSchemaOrSchemas(*items)
+		}
+	case aType[0] == "integer" && bType[0] == "number":
+		a.Type = spec.NewStringOrStrings("number")
+	}
+	return a
+}
+
+// mediaType returns the media type without any parameters
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.TrimSpace(contentType)
+	}
+	return mt
+}
+
+func isJSON(contentType string) bool {
+	mt := mediaType(contentType)
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+func appendUnique(ss []string, s string) []string {
+	if contains(ss, s) {
+		return ss
+	}
+	return append(ss, s)
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestFromHAR(t *testing.T) {
+	har := `{"log": {"entries": [
+		{
+			"request": {"method": "GET", "url": "https://api.example.com/pets?limit=10&tag=cat"},
+			"response": {"status": 200, "content": {"mimeType": "application/json; charset=utf-8", "text": "[{\"id\": 1, \"name\": \"Tom\"}]"}}
+		},
+		{
+			"request": {"method": "GET", "url": "https://api.example.com/pets?limit=5"},
+			"response": {"status": 200, "content": {"mimeType": "application/json", "text": "[{\"id\": 2, \"weight\": 4.5}]"}}
+		},
+		{
+			"request": {"method": "POST", "url": "https://api.example.com/pets", "postData": {"mimeType": "application/json", "text": "{\"name\": \"Rex\", \"tags\": [\"dog\"]}"}},
+			"response": {"status": 201, "content": {"mimeType": "text/plain", "text": "created"}}
+		},
+		{
+			"request": {"method": "GET", "url": "https://api.example.com/pets/123/toys/9"},
+			"response": {"status": 404, "content": {"mimeType": "text/plain", "text": "not found"}}
+		}
+	]}}`
+	swagger, err := FromHAR([]byte(har))
+	if err != nil {
+		t.Fatalf("FromHAR() failed: %s", err)
+	}
+	raw, err := swagger.MarshalJSON()
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	expected := `{"swagger":"2.0","info":{"title":"Draft","version":"0.0.0"},"host":"api.example.com","schemes":["https"],"paths":{` +
+		`"/pets":{"get":{"produces":["application/json"],"parameters":[{"name":"limit","in":"query","required":true,"type":"string"},{"name":"tag","in":"query","type":"string"}],` +
+		`"responses":{"200":{"description":"OK","schema":{"type":"array","items":{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string"},"weight":{"type":"number"}}}}}}},` +
+		`"post":{"consumes":["application/json"],"parameters":[{"name":"body","in":"body","required":true,"schema":{"type":"object","properties":{"name":{"type":"string"},"tags":{"type":"array","items":{"type":"string"}}}}}],` +
+		`"responses":{"201":{"description":"Created"}}}},` +
+		`"/pets/{petId}/toys/{toyId}":{"get":{"parameters":[{"name":"petId","in":"path","required":true,"type":"string"},{"name":"toyId","in":"path","required":true,"type":"string"}],` +
+		`"responses":{"404":{"description":"Not Found"}}}}}}`
+	if string(raw) != expected {
+		t.Errorf("FromHAR() =\n%s\nwant\n%s", raw, expected)
+	}
+	if _, err = spec.NewParser(raw).Parse(); err != nil {
+		t.Errorf("the draft spec should be valid: %s", err)
+	}
+}
@@ -0,0 +1,66 @@
+package coverage
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// Request is an observed HTTP request
+type Request struct {
+	Method string
+	// Path is the URL path of the request including any base path of the spec, where any query is ignored
+	Path string
+}
+
+// Report is the coverage of the operations of a spec by observed requests
+type Report struct {
+	// Exercised counts the requests matched to each operation
+	Exercised map[spec.OperationKey]int
+	// Unused are the sorted operations without any matched requests
+	Unused []spec.OperationKey
+	// Unmatched counts the requests that did not match any operation
+	Unmatched map[Request]int
+}
+
+// Coverage returns the percentage of operations exercised
+func (r *Report) Coverage() float64 {
+	total := len(r.Exercised) + len(r.Unused)
+	if total == 0 {
+		return 0
+	}
+	return float64(len(r.Exercised)) * 100 / float64(total)
+}
+
+// Analyze matches each request to the operations of the spec by method and templated path, preferring literal path
+// segments over templated ones when more than one operation matches
+func Analyze(swagger *spec.Swagger, requests []Request) *Report {
+	report := &Report{
+		Exercised: make(map[spec.OperationKey]int),
+		Unmatched: make(map[Request]int),
+	}
+	for _, req := range requests {
+		req.Method = strings.ToUpper(req.Method)
+		if i := strings.IndexByte(req.Path, '?'); i >= 0 {
+			req.Path = req.Path[:i]
+		}
+		if op, _, found := swagger.FindOperation(req.Method, req.Path); found {
+			report.Exercised[op.Key]++
+		} else {
+			report.Unmatched[req]++
+		}
+	}
+	for _, op := range swagger.Operations() {
+		if _, exercised := report.Exercised[op.Key]; !exercised {
+			report.Unused = append(report.Unused, op.Key)
+		}
+	}
+	sort.Slice(report.Unused, func(i, j int) bool {
+		if report.Unused[i].Path != report.Unused[j].Path {
+			return report.Unused[i].Path < report.Unused[j].Path
+		}
+		return report.Unused[i].Method < report.Unused[j].Method
+	})
+	return report
+}
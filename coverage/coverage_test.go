@@ -0,0 +1,65 @@
+package coverage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestAnalyze(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"basePath": "/v1",
+		"paths": {
+			"/pets": {
+				"get": {"responses": {"200": {"description": "ok"}}},
+				"post": {"responses": {"201": {"description": "ok"}}}
+			},
+			"/pets/{id}": {
+				"get": {"responses": {"200": {"description": "ok"}}}
+			},
+			"/pets/mine": {
+				"get": {"responses": {"200": {"description": "ok"}}}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	requests, err := RequestsFromHAR([]byte(`{"log": {"entries": [
+		{"request": {"method": "GET", "url": "https://example.com/v1/pets?limit=10"}},
+		{"request": {"method": "get", "url": "https://example.com/v1/pets/123"}},
+		{"request": {"method": "GET", "url": "https://example.com/v1/pets/mine"}},
+		{"request": {"method": "GET", "url": "https://example.com/v1/pets/456"}},
+		{"request": {"method": "DELETE", "url": "https://example.com/v1/pets/123"}},
+		{"request": {"method": "GET", "url": "https://example.com/pets"}}
+	]}}`))
+	if err != nil {
+		t.Fatalf("RequestsFromHAR() failed: %s", err)
+	}
+	report := Analyze(swagger, requests)
+	expectedExercised := map[spec.OperationKey]int{
+		{Path: "/pets", Method: "GET"}:      1,
+		{Path: "/pets/{id}", Method: "GET"}: 2,
+		{Path: "/pets/mine", Method: "GET"}: 1,
+	}
+	if !reflect.DeepEqual(report.Exercised, expectedExercised) {
+		t.Errorf("Exercised = %v", report.Exercised)
+	}
+	if !reflect.DeepEqual(report.Unused, []spec.OperationKey{{Path: "/pets", Method: "POST"}}) {
+		t.Errorf("Unused = %v", report.Unused)
+	}
+	expectedUnmatched := map[Request]int{
+		{Method: "DELETE", Path: "/v1/pets/123"}: 1,
+		{Method: "GET", Path: "/pets"}:           1,
+	}
+	if !reflect.DeepEqual(report.Unmatched, expectedUnmatched) {
+		t.Errorf("Unmatched = %v", report.Unmatched)
+	}
+	if got := report.Coverage(); got != 75 {
+		t.Errorf("Coverage() = %f", got)
+	}
+}
@@ -0,0 +1,3 @@
+// Package coverage provides the analysis of which operations of parsed swagger specifications are exercised by
+// observed requests
+package coverage
@@ -0,0 +1,36 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// RequestsFromHAR returns the requests of every entry within the HTTP Archive
+func RequestsFromHAR(har []byte) ([]Request, error) {
+	var archive struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					Method string `json:"method"`
+					URL    string `json:"url"`
+				} `json:"request"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(har, &archive); err != nil {
+		return nil, fmt.Errorf("invalid HAR: %w", err)
+	}
+	results := make([]Request, 0, len(archive.Log.Entries))
+	for i, entry := range archive.Log.Entries {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HAR: entry %d has an invalid url: %w", i, err)
+		}
+		results = append(results, Request{
+			Method: entry.Request.Method,
+			Path:   u.Path,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,8 @@
+// Package goats is the stable entry point for parsing and checking swagger 2.0 specifications.
+//
+// The interfaces of this package, along with the exported API of the spec and lint packages, follow semantic
+// versioning: nothing is removed or changed incompatibly without a major version, and anything to be removed is first
+// marked as deprecated for at least one minor version. The api.txt file records that API as of the last release and the
+// tests of this package fail when any of it is removed or changed without first being deprecated. All other packages
+// are experimental and may change in any release.
+package goats
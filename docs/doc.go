@@ -0,0 +1,2 @@
+// Package docs provides the rendering of parsed swagger specifications into self-contained HTML API references
+package docs
@@ -0,0 +1,192 @@
+package docs
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// UntaggedGroup is the name of the group of operations without any tags
+const UntaggedGroup = "default"
+
+//go:embed template.html
+var defaultTemplate string
+
+// Options defines the configuration of Render
+type Options struct {
+	// Overrides is template text parsed after the default template, so that any of its named templates may be
+	// redefined: page, style, header, operation, definition and security
+	Overrides string
+}
+
+// Page is the data the templates are executed with
+type Page struct {
+	Info     spec.Info
+	Host     string
+	BasePath string
+	Schemes  []string
+	// Groups are the operations by tag in the order the tags are declared followed by undeclared tags by name and then
+	// the UntaggedGroup, where an operation with many tags is in each of their groups
+	Groups []Group
+	// Definitions are sorted by name
+	Definitions []Definition
+	// SecuritySchemes are sorted by name
+	SecuritySchemes []SecurityScheme
+}
+
+// Group is the operations of a single tag
+type Group struct {
+	Name        string
+	Description string
+	Operations  []*spec.OperationDescription
+}
+
+// Definition is a named schema of the definitions of a spec
+type Definition struct {
+	Name       string
+	Schema     *spec.Schema
+	Properties []Property
+}
+
+// Property is a single property of a Definition
+type Property struct {
+	Name     string
+	Type     string
+	Required bool
+	Schema   *spec.Schema
+}
+
+// SecurityScheme is a named security scheme of a spec
+type SecurityScheme struct {
+	Name string
+	spec.SecurityScheme
+}
+
+// Render writes the HTML reference of the swagger spec to w
+func Render(w io.Writer, swagger *spec.Swagger, opts Options) error {
+	if swagger == nil {
+		return errors.New("cannot render a nil swagger")
+	}
+	tmpl, err := template.New("docs").Funcs(funcs).Parse(defaultTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid default template: %w", err)
+	}
+	if opts.Overrides != "" {
+		if tmpl, err = tmpl.Parse(opts.Overrides); err != nil {
+			return fmt.Errorf("invalid template overrides: %w", err)
+		}
+	}
+	return tmpl.ExecuteTemplate(w, "page", NewPage(swagger))
+}
+
+// NewPage returns the data of the reference of the swagger spec
+func NewPage(swagger *spec.Swagger) *Page {
+	page := &Page{
+		Info:     swagger.Info,
+		Host:     swagger.Host,
+		BasePath: swagger.BasePath,
+		Schemes:  swagger.Schemes,
+	}
+
+	byTag := swagger.OperationsByTag()
+	addGroup := func(name, description string, ops spec.Operations) {
+		group := Group{Name: name, Description: description}
+		for _, op := range ops {
+			if desc, found := swagger.DescribeOperation(op.Key); found {
+				group.Operations = append(group.Operations, desc)
+			}
+		}
+		page.Groups = append(page.Groups, group)
+	}
+	for _, tag := range swagger.Tags {
+		if ops, used := byTag[tag.Name]; used {
+			addGroup(tag.Name, tag.Description, ops)
+		}
+	}
+	for _, name := range swagger.UndeclaredTags() {
+		addGroup(name, "", byTag[name])
+	}
+	var untagged spec.Operations
+	for _, op := range swagger.Operations() {
+		if len(op.Tags) == 0 {
+			untagged = append(untagged, op)
+		}
+	}
+	if len(untagged) > 0 {
+		addGroup(UntaggedGroup, "", untagged)
+	}
+
+	for _, name := range sortedKeys(swagger.Definitions) {
+		def := swagger.Definitions[name]
+		page.Definitions = append(page.Definitions, Definition{
+			Name:       name,
+			Schema:     &def,
+			Properties: properties(&def),
+		})
+	}
+	for _, name := range sortedKeys(swagger.SecurityDefinitions) {
+		page.SecuritySchemes = append(page.SecuritySchemes, SecurityScheme{
+			Name:           name,
+			SecurityScheme: swagger.SecurityDefinitions[name],
+		})
+	}
+	return page
+}
+
+func properties(s *spec.Schema) []Property {
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	results := make([]Property, 0, len(s.Properties))
+	for _, name := range sortedKeys(s.Properties) {
+		prop := s.Properties[name]
+		results = append(results, Property{
+			Name:     name,
+			Type:     prop.TypeName(),
+			Required: required[name],
+			Schema:   &prop,
+		})
+	}
+	return results
+}
+
+var funcs = template.FuncMap{
+	"anchor": anchor,
+	"parameterType": func(p spec.Parameter) string {
+		return p.TypeName()
+	},
+	"join": strings.Join,
+}
+
+// anchor returns an HTML id from the parts, replacing anything other than letters and digits with hyphens
+func anchor(parts ...string) string {
+	var b strings.Builder
+	for _, part := range parts {
+		for _, r := range part {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(unicode.ToLower(r))
+			} else {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteByte('-')
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
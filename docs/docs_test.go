@@ -0,0 +1,113 @@
+package docs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+const testSpec = `{
+	"swagger": "2.0",
+	"info": {"title": "Pet Store", "version": "1.0", "description": "Pets <and> owners"},
+	"host": "api.example.com",
+	"basePath": "/v1",
+	"schemes": ["https"],
+	"tags": [{"name": "pets", "description": "Everything about pets"}, {"name": "unused"}],
+	"securityDefinitions": {
+		"key": {"type": "apiKey", "name": "X-Key", "in": "header"},
+		"oauth": {"type": "oauth2", "flow": "implicit", "authorizationUrl": "https://example.com/auth", "scopes": {"read": "read pets"}}
+	},
+	"paths": {
+		"/pets/{id}": {
+			"get": {
+				"tags": ["pets"],
+				"summary": "Find a pet",
+				"operationId": "getPet",
+				"security": [{"oauth": ["read"]}],
+				"parameters": [{"name": "id", "in": "path", "required": true, "type": "integer", "format": "int64"}],
+				"responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}}}
+			}
+		},
+		"/stores": {
+			"get": {"tags": ["stores"], "responses": {"200": {"description": "ok", "schema": {"type": "array", "items": {"type": "string"}}}}}
+		},
+		"/health": {
+			"get": {"responses": {"200": {"description": "ok"}}}
+		}
+	},
+	"definitions": {
+		"Pet": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {"name": {"type": "string", "description": "the name"}, "owner": {"$ref": "#/definitions/Owner"}}
+		},
+		"Owner": {"type": "string"}
+	}
+}`
+
+func TestRender(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(testSpec)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	var groups []string
+	for _, g := range NewPage(swagger).Groups {
+		groups = append(groups, g.Name)
+	}
+	if got := strings.Join(groups, ","); got != "pets,stores,"+UntaggedGroup {
+		t.Errorf("NewPage() groups = %s", got)
+	}
+	var buf bytes.Buffer
+	if err = Render(&buf, swagger, Options{}); err != nil {
+		t.Fatalf("Render() unexpected error: %s", err)
+	}
+	html := buf.String()
+	for _, expected := range []string{
+		"<title>Pet Store 1.0</title>",
+		"<p>Pets &lt;and&gt; owners</p>",
+		"<code>https://api.example.com/v1</code>",
+		`<section id="tag-pets">`,
+		"<p>Everything about pets</p>",
+		`<article id="op-get--pets--id">`,
+		"<p>Operation ID: <code>getPet</code></p>",
+		`<a href="#security-oauth">oauth</a> (read)`,
+		"<tr><td>id</td><td>path</td><td>integer</td><td>true</td><td></td></tr>",
+		`<tr><td>200</td><td>ok</td><td><a href="#definition-pet">Pet</a></td></tr>`,
+		"<tr><td>200</td><td>ok</td><td><code>[]string</code></td></tr>",
+		`<section id="tag-default">`,
+		"<tr><td>name</td><td><code>string</code></td><td>true</td><td>the name</td></tr>",
+		"<tr><td>owner</td><td><code>Owner</code></td><td>false</td><td></td></tr>",
+		"<p>Type: apiKey, header parameter <code>X-Key</code></p>",
+		"<tr><td>read</td><td>read pets</td></tr>",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Render() is missing %s in:\n%s", expected, html)
+		}
+	}
+	if strings.Contains(html, "tag-unused") {
+		t.Error("Render() included a tag without operations")
+	}
+}
+
+func TestRender_overrides(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(testSpec)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	var buf bytes.Buffer
+	err = Render(&buf, swagger, Options{Overrides: `{{define "style"}}body{color:red}{{end}}{{define "definition"}}<p>{{.Name}}</p>{{end}}`})
+	if err != nil {
+		t.Fatalf("Render() unexpected error: %s", err)
+	}
+	html := buf.String()
+	for _, expected := range []string{"<style>body{color:red}</style>", "<p>Owner</p>", "<p>Pet</p>", "<h3><span class=\"method\">GET</span>"} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Render() is missing %s in:\n%s", expected, html)
+		}
+	}
+	if err = Render(&buf, swagger, Options{Overrides: `{{define "style"}}{{end`}); err == nil {
+		t.Error("Render() expected an error for invalid overrides")
+	}
+}
@@ -0,0 +1,142 @@
+package docs
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// UI selects the interactive documentation served by Handler
+type UI int
+
+const (
+	// SwaggerUI serves Swagger UI
+	SwaggerUI UI = iota
+	// ReDoc serves ReDoc
+	ReDoc
+)
+
+// The default locations of the assets of each UI
+const (
+	DefaultSwaggerUIAssetsURL = "https://unpkg.com/swagger-ui-dist@5"
+	DefaultReDocAssetsURL     = "https://cdn.redoc.ly/redoc/latest/bundles"
+)
+
+//go:embed ui.html
+var uiTemplate string
+
+// HandlerOptions defines the configuration of Handler
+type HandlerOptions struct {
+	UI UI
+	// BasePath is the path the handler is mounted at, such as /docs, which is prefixed to all of the paths it serves
+	BasePath string
+	// AssetsURL is the base URL of the assets of the UI, such as where they are self-hosted, when empty the default
+	// for the UI is used
+	AssetsURL string
+	// Authorize is called for every request and those it returns false for are rejected as unauthorized, when nil
+	// every request is allowed
+	Authorize func(r *http.Request) bool
+}
+
+// Handler returns an http.Handler serving the interactive documentation of the swagger spec at the base path, the
+// spec itself at swagger.json and its static HTML reference at reference.html. The spec is marshalled and rendered
+// once, so later changes to it are not served.
+func Handler(swagger *spec.Swagger, opts HandlerOptions) (http.Handler, error) {
+	if swagger == nil {
+		return nil, errors.New("cannot serve a nil swagger")
+	}
+	basePath := strings.TrimSuffix(opts.BasePath, "/")
+	if basePath != "" && !strings.HasPrefix(basePath, "/") {
+		return nil, fmt.Errorf("invalid base path '%s': must start with '/'", opts.BasePath)
+	}
+	h := &handler{
+		basePath:  basePath,
+		authorize: opts.Authorize,
+	}
+
+	var err error
+	if h.spec, err = swagger.MarshalJSON(); err != nil {
+		return nil, fmt.Errorf("failed to marshal swagger: %w", err)
+	}
+	var buf bytes.Buffer
+	if err = Render(&buf, swagger, Options{}); err != nil {
+		return nil, fmt.Errorf("failed to render reference: %w", err)
+	}
+	h.reference = buf.Bytes()
+
+	name, assetsURL := "swagger-ui", DefaultSwaggerUIAssetsURL
+	switch opts.UI {
+	case SwaggerUI:
+	case ReDoc:
+		name, assetsURL = "redoc", DefaultReDocAssetsURL
+	default:
+		return nil, fmt.Errorf("invalid UI: %d", opts.UI)
+	}
+	if opts.AssetsURL != "" {
+		assetsURL = strings.TrimSuffix(opts.AssetsURL, "/")
+	}
+	tmpl, err := template.New("ui").Parse(uiTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UI template: %w", err)
+	}
+	var index bytes.Buffer
+	err = tmpl.ExecuteTemplate(&index, name, struct {
+		Title     string
+		AssetsURL string
+		SpecURL   string
+	}{
+		Title:     swagger.Info.Title,
+		AssetsURL: assetsURL,
+		SpecURL:   basePath + "/swagger.json",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render UI: %w", err)
+	}
+	h.index = index.Bytes()
+	return h, nil
+}
+
+type handler struct {
+	basePath  string
+	authorize func(r *http.Request) bool
+	spec      []byte
+	reference []byte
+	index     []byte
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.authorize != nil && !h.authorize(r) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	var (
+		body        []byte
+		contentType string
+	)
+	switch r.URL.Path {
+	case h.basePath, h.basePath + "/", h.basePath + "/index.html":
+		body, contentType = h.index, "text/html; charset=utf-8"
+	case h.basePath + "/reference.html":
+		body, contentType = h.reference, "text/html; charset=utf-8"
+	case h.basePath + "/swagger.json":
+		body, contentType = h.spec, "application/json"
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(body)
+	}
+}
@@ -0,0 +1,122 @@
+package docs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestHandler(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(testSpec)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	tests := map[string]struct {
+		opts         HandlerOptions
+		method, path string
+		header       string
+		status       int
+		contentType  string
+		contains     string
+	}{
+		"swagger ui should be served at the base path": {
+			opts:        HandlerOptions{BasePath: "/docs/"},
+			path:        "/docs/",
+			status:      http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			contains:    `SwaggerUIBundle({url: "/docs/swagger.json"`,
+		},
+		"redoc should be served with its assets": {
+			opts:        HandlerOptions{UI: ReDoc, AssetsURL: "/assets/"},
+			path:        "/index.html",
+			status:      http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			contains:    `<script src="/assets/redoc.standalone.js"></script>`,
+		},
+		"spec should be served as JSON": {
+			opts:        HandlerOptions{BasePath: "/docs"},
+			path:        "/docs/swagger.json",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			contains:    `"title":"Pet Store"`,
+		},
+		"reference should be served as HTML": {
+			opts:        HandlerOptions{BasePath: "/docs"},
+			path:        "/docs/reference.html",
+			status:      http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			contains:    "<title>Pet Store 1.0</title>",
+		},
+		"unknown paths should not be found": {
+			opts:   HandlerOptions{BasePath: "/docs"},
+			path:   "/swagger.json",
+			status: http.StatusNotFound,
+		},
+		"other methods should not be allowed": {
+			method: http.MethodPost,
+			path:   "/swagger.json",
+			status: http.StatusMethodNotAllowed,
+		},
+		"unauthorized requests should be rejected": {
+			opts: HandlerOptions{Authorize: func(r *http.Request) bool {
+				return r.Header.Get("Authorization") == "secret"
+			}},
+			path:   "/swagger.json",
+			status: http.StatusUnauthorized,
+		},
+		"authorized requests should be served": {
+			opts: HandlerOptions{Authorize: func(r *http.Request) bool {
+				return r.Header.Get("Authorization") == "secret"
+			}},
+			path:   "/swagger.json",
+			header: "secret",
+			status: http.StatusOK,
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			h, err := Handler(swagger, tt.opts)
+			if err != nil {
+				t.Fatalf("Handler() unexpected error: %s", err)
+			}
+			if tt.method == "" {
+				tt.method = http.MethodGet
+			}
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("ServeHTTP() status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Content-Type"); tt.contentType != "" && got != tt.contentType {
+				t.Errorf("ServeHTTP() Content-Type = %s, want %s", got, tt.contentType)
+			}
+			if body := rec.Body.String(); !strings.Contains(body, tt.contains) {
+				t.Errorf("ServeHTTP() body is missing %s in:\n%s", tt.contains, body)
+			}
+		})
+	}
+}
+
+func TestHandler_invalidOptions(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(testSpec)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	for should, opts := range map[string]HandlerOptions{
+		"relative base path": {BasePath: "docs"},
+		"unknown UI":         {UI: UI(9)},
+	} {
+		t.Run(should, func(t *testing.T) {
+			if _, err := Handler(swagger, opts); err == nil {
+				t.Error("Handler() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package formats provides the registry of the primitive types and formats defined by the swagger specification along
+// with the validation of JSON value literals, such as defaults, enums and examples, against them
+package formats
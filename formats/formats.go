@@ -0,0 +1,202 @@
+package formats
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/valyala/fastjson"
+)
+
+// The primitive types of the swagger specification
+// https://swagger.io/specification/v2/#data-types
+const (
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeString  = "string"
+	TypeBoolean = "boolean"
+	TypeArray   = "array"
+	TypeObject  = "object"
+	TypeFile    = "file"
+)
+
+// The formats defined by the swagger specification
+const (
+	Int32    = "int32"
+	Int64    = "int64"
+	Float    = "float"
+	Double   = "double"
+	Byte     = "byte"
+	Binary   = "binary"
+	Date     = "date"
+	DateTime = "date-time"
+	Password = "password"
+)
+
+// Format is a format of a primitive type
+type Format struct {
+	// Name is the value of the format field
+	Name string
+	// Type is the primitive type the format applies to
+	Type string
+	// Validate returns an error if the value, already known to be of the Type, is invalid for the format and may be
+	// nil when any value of the Type is valid
+	Validate func(v *fastjson.Value) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Format{
+		Int32:    {Name: Int32, Type: TypeInteger, Validate: validateInt(math.MinInt32, math.MaxInt32)},
+		Int64:    {Name: Int64, Type: TypeInteger, Validate: validateInt(math.MinInt64, math.MaxInt64)},
+		Float:    {Name: Float, Type: TypeNumber, Validate: validateFloat},
+		Double:   {Name: Double, Type: TypeNumber},
+		Byte:     {Name: Byte, Type: TypeString, Validate: validateByte},
+		Binary:   {Name: Binary, Type: TypeString},
+		Date:     {Name: Date, Type: TypeString, Validate: validateTime("2006-01-02")},
+		DateTime: {Name: DateTime, Type: TypeString, Validate: validateTime(time.RFC3339)},
+		Password: {Name: Password, Type: TypeString},
+	}
+)
+
+// Register adds the format to the registry, replacing any registered with the same name, so that values declared
+// with it are validated
+func Register(format Format) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[format.Name] = format
+}
+
+// Lookup returns the registered format with the name
+func Lookup(name string) (Format, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, found := registry[name]
+	return f, found
+}
+
+// All returns every registered format sorted by name
+func All() []Format {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	results := make([]Format, 0, len(registry))
+	for _, f := range registry {
+		results = append(results, f)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// ValidType returns true if the type is one of the primitive types of the swagger specification
+func ValidType(typ string) bool {
+	switch typ {
+	case TypeInteger, TypeNumber, TypeString, TypeBoolean, TypeArray, TypeObject, TypeFile:
+		return true
+	}
+	return false
+}
+
+// MatchesType returns true if the value is of the type, where integers are numbers without any fraction, any value
+// matches an empty type and none match a file
+func MatchesType(typ string, v *fastjson.Value) bool {
+	switch typ {
+	case "":
+		return true
+	case TypeInteger:
+		if v.Type() != fastjson.TypeNumber {
+			return false
+		}
+		_, err := strconv.ParseInt(string(v.MarshalTo(nil)), 10, 64)
+		if err == nil {
+			return true
+		}
+		f := v.GetFloat64()
+		return f == math.Trunc(f) && !math.IsInf(f, 0)
+	case TypeNumber:
+		return v.Type() == fastjson.TypeNumber
+	case TypeString:
+		return v.Type() == fastjson.TypeString
+	case TypeBoolean:
+		return v.Type() == fastjson.TypeTrue || v.Type() == fastjson.TypeFalse
+	case TypeArray:
+		return v.Type() == fastjson.TypeArray
+	case TypeObject:
+		return v.Type() == fastjson.TypeObject
+	}
+	return false
+}
+
+// Validate returns an error if the value is not of the type or is invalid for the format, where formats that are not
+// registered or that apply to another type are ignored as the specification allows custom formats
+func Validate(typ, format string, v *fastjson.Value) error {
+	if v == nil {
+		return nil
+	}
+	if !MatchesType(typ, v) {
+		return fmt.Errorf("expected type %s but got %s", typ, v.Type())
+	}
+	f, found := Lookup(format)
+	if !found || f.Validate == nil || !MatchesType(f.Type, v) {
+		return nil
+	}
+	if typ != "" && typ != f.Type && !(typ == TypeNumber && f.Type == TypeInteger) {
+		return nil
+	}
+	if err := f.Validate(v); err != nil {
+		return fmt.Errorf("invalid %s value %s: %w", f.Name, v, err)
+	}
+	return nil
+}
+
+func validateInt(min, max int64) func(v *fastjson.Value) error {
+	return func(v *fastjson.Value) error {
+		raw := string(v.MarshalTo(nil))
+		i, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			var numErr *strconv.NumError
+			if errors.As(err, &numErr) && errors.Is(numErr.Err, strconv.ErrRange) {
+				return errors.New("out of range")
+			}
+			f := v.GetFloat64()
+			if f < float64(min) || f > float64(max) {
+				return errors.New("out of range")
+			}
+			return nil
+		}
+		if i < min || i > max {
+			return errors.New("out of range")
+		}
+		return nil
+	}
+}
+
+func validateFloat(v *fastjson.Value) error {
+	if f := math.Abs(v.GetFloat64()); f > math.MaxFloat32 {
+		return errors.New("out of range")
+	}
+	return nil
+}
+
+func validateByte(v *fastjson.Value) error {
+	_, err := base64.StdEncoding.DecodeString(string(v.GetStringBytes()))
+	if err != nil {
+		return errors.New("not base64 encoded")
+	}
+	return nil
+}
+
+func validateTime(layout string) func(v *fastjson.Value) error {
+	return func(v *fastjson.Value) error {
+		if _, err := time.Parse(layout, string(v.GetStringBytes())); err != nil {
+			return fmt.Errorf("not of the form %s", layout)
+		}
+		return nil
+	}
+}
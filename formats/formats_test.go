@@ -0,0 +1,76 @@
+package formats
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/valyala/fastjson"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		typ, format, value string
+		wantErr            string
+	}{
+		"match integer":             {typ: TypeInteger, value: `5`},
+		"match integral number":     {typ: TypeInteger, value: `5.0`},
+		"mismatch integer":          {typ: TypeInteger, value: `5.5`, wantErr: "expected type integer but got number"},
+		"mismatch string":           {typ: TypeString, value: `5`, wantErr: "expected type string but got number"},
+		"match boolean":             {typ: TypeBoolean, value: `false`},
+		"match any without a type":  {value: `{"a": 1}`},
+		"valid int32":               {typ: TypeInteger, format: Int32, value: `2147483647`},
+		"invalid int32":             {typ: TypeInteger, format: Int32, value: `2147483648`, wantErr: "invalid int32 value 2147483648: out of range"},
+		"invalid int64":             {typ: TypeInteger, format: Int64, value: `9223372036854775808`, wantErr: "invalid int64 value 9223372036854775808: out of range"},
+		"invalid float":             {typ: TypeNumber, format: Float, value: `1e39`, wantErr: "invalid float value 1e39: out of range"},
+		"valid double":              {typ: TypeNumber, format: Double, value: `1e39`},
+		"valid byte":                {typ: TypeString, format: Byte, value: `"aGVsbG8="`},
+		"invalid byte":              {typ: TypeString, format: Byte, value: `"hello!"`, wantErr: `invalid byte value "hello!": not base64 encoded`},
+		"valid date":                {typ: TypeString, format: Date, value: `"2024-02-29"`},
+		"invalid date":              {typ: TypeString, format: Date, value: `"2023-02-29"`, wantErr: `invalid date value "2023-02-29": not of the form 2006-01-02`},
+		"valid date-time":           {typ: TypeString, format: DateTime, value: `"2024-02-29T10:00:00Z"`},
+		"invalid date-time":         {typ: TypeString, format: DateTime, value: `"2024-02-29"`, wantErr: `invalid date-time value "2024-02-29": not of the form 2006-01-02T15:04:05Z07:00`},
+		"custom format is ignored":  {typ: TypeString, format: "uuid", value: `"nope"`},
+		"format of other type":      {typ: TypeString, format: Int32, value: `"nope"`},
+		"integer format for number": {typ: TypeNumber, format: Int32, value: `1e10`, wantErr: "invalid int32 value 1e10: out of range"},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			err := Validate(tt.typ, tt.format, fastjson.MustParse(tt.value))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if _, found := Lookup("even"); found {
+		t.Fatal("Lookup() found an unregistered format")
+	}
+	Register(Format{
+		Name: "even",
+		Type: TypeInteger,
+		Validate: func(v *fastjson.Value) error {
+			if v.GetInt()%2 != 0 {
+				return errors.New("odd")
+			}
+			return nil
+		},
+	})
+	if err := Validate(TypeInteger, "even", fastjson.MustParse(`3`)); err == nil {
+		t.Error("Validate() expected an error for a registered format")
+	}
+	var names []string
+	for _, f := range All() {
+		names = append(names, f.Name)
+	}
+	if names[0] != Binary || names[len(names)-1] != Password {
+		t.Errorf("All() = %v is not sorted by name", names)
+	}
+}
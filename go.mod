@@ -1,5 +1,5 @@
 module github.com/erraggy/goats
 
-go 1.19
+go 1.23
 
 require github.com/valyala/fastjson v1.6.4
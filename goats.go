@@ -0,0 +1,41 @@
+package goats
+
+import (
+	"context"
+
+	"github.com/erraggy/goats/lint"
+	"github.com/erraggy/goats/spec"
+)
+
+// Parser parses a single swagger spec
+type Parser interface {
+	Parse() (*spec.Swagger, error)
+	ParseContext(ctx context.Context) (*spec.Swagger, error)
+}
+
+// Linter checks a parsed swagger spec for issues
+type Linter interface {
+	Lint(ctx context.Context, swagger *spec.Swagger) lint.Result
+}
+
+// LinterFunc adapts a func to a Linter
+type LinterFunc func(ctx context.Context, swagger *spec.Swagger) lint.Result
+
+// Lint calls the func
+func (f LinterFunc) Lint(ctx context.Context, swagger *spec.Swagger) lint.Result {
+	return f(ctx, swagger)
+}
+
+var _ Parser = (*spec.Parser)(nil)
+
+// NewParser returns a Parser for the raw swagger JSON bytes configured with any options
+func NewParser(raw []byte, opts ...spec.ParserOption) Parser {
+	return spec.NewParser(raw, opts...)
+}
+
+// NewLinter returns a Linter checking the rules, or the default rules if none are specified
+func NewLinter(rules ...lint.Rule) Linter {
+	return LinterFunc(func(ctx context.Context, swagger *spec.Swagger) lint.Result {
+		return lint.LintContext(ctx, swagger, rules...)
+	})
+}
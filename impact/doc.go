@@ -0,0 +1,3 @@
+// Package impact provides the graph of which operations of parsed swagger specifications are affected by changes to
+// their definitions
+package impact
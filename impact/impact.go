@@ -0,0 +1,163 @@
+package impact
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// NodeKind defines what a Node of the Graph is
+type NodeKind string
+
+const (
+	// KindOperation is used for nodes of operations, whose IDs are their method and path such as "GET /pets"
+	KindOperation NodeKind = "operation"
+	// KindDefinition is used for nodes of definitions, whose IDs are their references such as "#/definitions/Pet"
+	KindDefinition NodeKind = "definition"
+)
+
+// Node is an operation or a definition within the Graph
+type Node struct {
+	ID    string   `json:"id"`
+	Kind  NodeKind `json:"kind"`
+	Label string   `json:"label"`
+}
+
+// Edge is a direct reference from an operation or definition to a definition
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph is the directed graph of the references from the operations of a spec to its definitions and from those
+// definitions to any nested definitions, which encodes as JSON
+type Graph struct {
+	// Nodes are the sorted operations followed by the sorted definitions
+	Nodes []Node `json:"nodes"`
+	// Edges are sorted in the order of the nodes they are from, then the names of the definitions they are to
+	Edges []Edge `json:"edges"`
+
+	operations map[string]spec.OperationKey
+}
+
+// New returns the Graph of the references within the swagger spec, where references to undefined definitions are
+// ignored
+func New(swagger *spec.Swagger) *Graph {
+	g := &Graph{operations: make(map[string]spec.OperationKey)}
+	if swagger == nil {
+		return g
+	}
+	for _, op := range swagger.Operations() {
+		id := OperationID(op.Key)
+		g.operations[id] = op.Key
+		g.Nodes = append(g.Nodes, Node{ID: id, Kind: KindOperation, Label: id})
+		refs := op.ResolvedReferencedDefinitions(swagger)
+		for _, param := range op.EffectiveParameters(swagger.Paths.Items[op.Key.Path], swagger.Parameters) {
+			refs = refs.Merge(param.Schema.ReferencedDefinitions())
+		}
+		g.addEdges(swagger, id, refs)
+	}
+	names := make([]string, 0, len(swagger.Definitions))
+	for name := range swagger.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		id := DefinitionID(name)
+		g.Nodes = append(g.Nodes, Node{ID: id, Kind: KindDefinition, Label: name})
+		def := swagger.Definitions[name]
+		g.addEdges(swagger, id, def.ReferencedDefinitions())
+	}
+	return g
+}
+
+func (g *Graph) addEdges(swagger *spec.Swagger, from string, refs *spec.UniqueDefinitionRefs) {
+	names := refs.Values()
+	sort.Strings(names)
+	for _, name := range names {
+		if _, defined := swagger.Definitions[name]; defined {
+			g.Edges = append(g.Edges, Edge{From: from, To: DefinitionID(name)})
+		}
+	}
+}
+
+// OperationID returns the ID of the Node of the operation
+func OperationID(key spec.OperationKey) string {
+	key = key.Canonicalize()
+	return key.Method + " " + key.Path
+}
+
+// DefinitionID returns the ID of the Node of the named definition
+func DefinitionID(name string) string {
+	return "#/definitions/" + name
+}
+
+// BlastRadius returns the subgraph of the named definition and every operation and definition referencing it,
+// directly or transitively, which are those affected by any change to it. The result is empty if it is not defined.
+func (g *Graph) BlastRadius(definition string) *Graph {
+	result := &Graph{operations: make(map[string]spec.OperationKey)}
+	target := DefinitionID(definition)
+	reached := map[string]bool{}
+	for _, n := range g.Nodes {
+		if n.ID == target {
+			reached[target] = true
+		}
+	}
+	for changed := len(reached) > 0; changed; {
+		changed = false
+		for _, e := range g.Edges {
+			if reached[e.To] && !reached[e.From] {
+				reached[e.From] = true
+				changed = true
+			}
+		}
+	}
+	for _, n := range g.Nodes {
+		if reached[n.ID] {
+			result.Nodes = append(result.Nodes, n)
+			if key, isOp := g.operations[n.ID]; isOp {
+				result.operations[n.ID] = key
+			}
+		}
+	}
+	for _, e := range g.Edges {
+		if reached[e.From] && reached[e.To] {
+			result.Edges = append(result.Edges, e)
+		}
+	}
+	return result
+}
+
+// Affected returns the sorted keys of the operations referencing the named definition, directly or transitively
+func (g *Graph) Affected(definition string) []spec.OperationKey {
+	var results []spec.OperationKey
+	for _, n := range g.BlastRadius(definition).Nodes {
+		if n.Kind == KindOperation {
+			results = append(results, g.operations[n.ID])
+		}
+	}
+	return results
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language, drawing operations as boxes and definitions as ellipses
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph impact {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	for _, n := range g.Nodes {
+		shape := "ellipse"
+		if n.Kind == KindOperation {
+			shape = "box"
+		}
+		fmt.Fprintf(bw, "\t%s [label=%s, shape=%s];\n", strconv.Quote(n.ID), strconv.Quote(n.Label), shape)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "\t%s -> %s;\n", strconv.Quote(e.From), strconv.Quote(e.To))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
@@ -0,0 +1,121 @@
+package impact
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestGraph(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/owners": {
+				"get": {"responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Owner"}}}}
+			},
+			"/pets": {
+				"post": {
+					"parameters": [{"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}],
+					"responses": {"201": {"description": "created"}}
+				}
+			},
+			"/status": {
+				"get": {"responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Missing"}}}}
+			}
+		},
+		"definitions": {
+			"Owner": {"type": "object", "properties": {"pets": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}},
+			"Pet": {"type": "object", "properties": {"tag": {"$ref": "#/definitions/Tag"}}},
+			"Tag": {"type": "string"}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	g := New(swagger)
+
+	var dot bytes.Buffer
+	if err = g.WriteDOT(&dot); err != nil {
+		t.Fatalf("WriteDOT() unexpected error: %s", err)
+	}
+	expectedDOT := `digraph impact {
+	rankdir=LR;
+	"GET /owners" [label="GET /owners", shape=box];
+	"POST /pets" [label="POST /pets", shape=box];
+	"GET /status" [label="GET /status", shape=box];
+	"#/definitions/Owner" [label="Owner", shape=ellipse];
+	"#/definitions/Pet" [label="Pet", shape=ellipse];
+	"#/definitions/Tag" [label="Tag", shape=ellipse];
+	"GET /owners" -> "#/definitions/Owner";
+	"POST /pets" -> "#/definitions/Pet";
+	"#/definitions/Owner" -> "#/definitions/Pet";
+	"#/definitions/Pet" -> "#/definitions/Tag";
+}
+`
+	if dot.String() != expectedDOT {
+		t.Errorf("WriteDOT() =\n%s\nwant\n%s", dot.String(), expectedDOT)
+	}
+
+	expectedAffected := []spec.OperationKey{
+		{Path: "/owners", Method: "GET"},
+		{Path: "/pets", Method: "POST"},
+	}
+	if got := g.Affected("Tag"); !reflect.DeepEqual(got, expectedAffected) {
+		t.Errorf("Affected(Tag) = %v, want %v", got, expectedAffected)
+	}
+	if got := g.Affected("Owner"); !reflect.DeepEqual(got, expectedAffected[:1]) {
+		t.Errorf("Affected(Owner) = %v, want %v", got, expectedAffected[:1])
+	}
+	if got := g.BlastRadius("Missing"); len(got.Nodes) != 0 || len(got.Edges) != 0 {
+		t.Errorf("BlastRadius(Missing) = %v, want empty", got)
+	}
+
+	raw, err := json.Marshal(g.BlastRadius("Pet"))
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	expectedJSON := `{"nodes":[` +
+		`{"id":"GET /owners","kind":"operation","label":"GET /owners"},` +
+		`{"id":"POST /pets","kind":"operation","label":"POST /pets"},` +
+		`{"id":"#/definitions/Owner","kind":"definition","label":"Owner"},` +
+		`{"id":"#/definitions/Pet","kind":"definition","label":"Pet"}],` +
+		`"edges":[` +
+		`{"from":"GET /owners","to":"#/definitions/Owner"},` +
+		`{"from":"POST /pets","to":"#/definitions/Pet"},` +
+		`{"from":"#/definitions/Owner","to":"#/definitions/Pet"}]}`
+	if string(raw) != expectedJSON {
+		t.Errorf("BlastRadius(Pet) JSON =\n%s\nwant\n%s", raw, expectedJSON)
+	}
+}
+
+func TestGraph_parameterRefs(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"parameters": {
+			"petBody": {"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}
+		},
+		"paths": {
+			"/pets": {
+				"post": {
+					"parameters": [{"$ref": "#/parameters/petBody"}],
+					"responses": {"201": {"description": "created"}}
+				}
+			}
+		},
+		"definitions": {
+			"Pet": {"type": "object"}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	expected := []spec.OperationKey{{Path: "/pets", Method: "POST"}}
+	if got := New(swagger).Affected("Pet"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Affected(Pet) = %v, want %v", got, expected)
+	}
+}
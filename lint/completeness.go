@@ -0,0 +1,132 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// UntaggedOperations is the tag used by Completeness for the operations without any tags
+const UntaggedOperations = "(untagged)"
+
+// completenessChecks are the recommended parts of an operation in the order they are reported
+var completenessChecks = []struct {
+	name  string
+	check func(swagger *spec.Swagger, op *spec.Operation) bool
+}{
+	{"summary", func(_ *spec.Swagger, op *spec.Operation) bool { return op.Summary != "" }},
+	{"description", func(_ *spec.Swagger, op *spec.Operation) bool { return op.Description != "" }},
+	{"tags", func(_ *spec.Swagger, op *spec.Operation) bool { return len(op.Tags) > 0 }},
+	{"error responses", func(_ *spec.Swagger, op *spec.Operation) bool {
+		if op.Responses.Default != nil {
+			return true
+		}
+		for code := range op.Responses.ByStatusCode {
+			if code >= 400 {
+				return true
+			}
+		}
+		return false
+	}},
+	{"examples", hasExamples},
+	{"security", func(swagger *spec.Swagger, op *spec.Operation) bool {
+		return op.Security != nil || swagger.Security != nil
+	}},
+}
+
+// OperationCompleteness lists what an operation is missing of the recommended parts
+type OperationCompleteness struct {
+	Key     spec.OperationKey
+	Missing []string
+	// Percent is the percentage of the recommended parts present
+	Percent float64
+}
+
+// Completeness is the authoring completeness of every operation of a spec
+type Completeness struct {
+	// Operations are sorted by their keys
+	Operations []OperationCompleteness
+	// ByTag is the average Percent of the operations with each tag, or UntaggedOperations for those without any
+	ByTag map[string]float64
+	// Percent is the average Percent of all operations
+	Percent float64
+}
+
+// CheckCompleteness returns which recommended parts each operation of the spec is missing, being a summary,
+// description, tags, error responses, examples of its response schemas and security
+func CheckCompleteness(swagger *spec.Swagger) *Completeness {
+	result := &Completeness{
+		ByTag: make(map[string]float64),
+	}
+	tagCounts := make(map[string]int)
+	for _, op := range swagger.Operations() {
+		oc := OperationCompleteness{Key: op.Key}
+		for _, c := range completenessChecks {
+			if !c.check(swagger, op) {
+				oc.Missing = append(oc.Missing, c.name)
+			}
+		}
+		oc.Percent = float64(len(completenessChecks)-len(oc.Missing)) * 100 / float64(len(completenessChecks))
+		result.Operations = append(result.Operations, oc)
+		result.Percent += oc.Percent
+		tags := op.Tags
+		if len(tags) == 0 {
+			tags = []string{UntaggedOperations}
+		}
+		for _, tag := range tags {
+			result.ByTag[tag] += oc.Percent
+			tagCounts[tag]++
+		}
+	}
+	if len(result.Operations) > 0 {
+		result.Percent /= float64(len(result.Operations))
+	}
+	for tag, count := range tagCounts {
+		result.ByTag[tag] /= float64(count)
+	}
+	return result
+}
+
+// String renders the completeness by tag followed by the missing parts of each incomplete operation
+func (c *Completeness) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Completeness: %.0f%%\n", c.Percent)
+	tags := make([]string, 0, len(c.ByTag))
+	for tag := range c.ByTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		fmt.Fprintf(&b, "  %s: %.0f%%\n", tag, c.ByTag[tag])
+	}
+	for _, oc := range c.Operations {
+		if len(oc.Missing) > 0 {
+			fmt.Fprintf(&b, "%s %s: missing %s\n", oc.Key.Method, oc.Key.Path, strings.Join(oc.Missing, ", "))
+		}
+	}
+	return b.String()
+}
+
+// hasExamples returns true if every response schema of the operation, or the definition it references, has an
+// example
+func hasExamples(swagger *spec.Swagger, op *spec.Operation) bool {
+	responses := make([]*spec.Response, 0, len(op.Responses.ByStatusCode)+1)
+	for _, code := range statusCodes(op) {
+		responses = append(responses, op.Responses.ByStatusCode[code])
+	}
+	if op.Responses.Default != nil {
+		responses = append(responses, op.Responses.Default)
+	}
+	for _, r := range responses {
+		if r.Schema == nil || r.Schema.Example != nil {
+			continue
+		}
+		name, isRef := r.Schema.Ref.DefinitionName()
+		if def, exists := swagger.Definitions[name]; !isRef || !exists || def.Example == nil {
+			return false
+		}
+	}
+	return true
+}
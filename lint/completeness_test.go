@@ -0,0 +1,72 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestCheckCompleteness(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"summary": "List pets",
+					"description": "Lists all pets",
+					"tags": ["pets"],
+					"security": [],
+					"responses": {
+						"200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}},
+						"default": {"description": "error"}
+					}
+				},
+				"post": {
+					"tags": ["pets"],
+					"responses": {"201": {"description": "created", "schema": {"type": "object"}}}
+				}
+			},
+			"/health": {
+				"get": {"responses": {"200": {"description": "ok"}}}
+			}
+		},
+		"definitions": {
+			"Pet": {"type": "object", "example": {"name": "Tom"}}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	c := CheckCompleteness(swagger)
+	missing := make(map[string][]string)
+	for _, oc := range c.Operations {
+		missing[oc.Key.Method+" "+oc.Key.Path] = oc.Missing
+	}
+	expected := map[string][]string{
+		"GET /pets":   nil,
+		"POST /pets":  {"summary", "description", "error responses", "examples", "security"},
+		"GET /health": {"summary", "description", "tags", "error responses", "security"},
+	}
+	if !reflect.DeepEqual(missing, expected) {
+		t.Errorf("missing = %v", missing)
+	}
+	expectedByTag := map[string]float64{
+		"pets":             (100 + 100.0/6) / 2,
+		UntaggedOperations: 100.0 / 6,
+	}
+	if !reflect.DeepEqual(c.ByTag, expectedByTag) {
+		t.Errorf("ByTag = %v", c.ByTag)
+	}
+	expectedString := `Completeness: 44%
+  (untagged): 17%
+  pets: 58%
+GET /health: missing summary, description, tags, error responses, security
+POST /pets: missing summary, description, error responses, examples, security
+`
+	if got := c.String(); got != expectedString {
+		t.Errorf("String() =\n%s\nwant\n%s", got, expectedString)
+	}
+}
@@ -0,0 +1,45 @@
+package lint
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// sunsetExtension is the extension declaring the date after which a deprecated operation will be removed, which is
+// the same one added by transform.Deprecate
+const sunsetExtension = "x-sunset"
+
+var deprecatedWithoutSunsetRule = Rule{
+	ID:          "deprecated-without-sunset",
+	Description: "deprecated operations should declare the date they will be removed with the x-sunset extension",
+	Severity:    SeverityWarning,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		for _, op := range swagger.Operations() {
+			if !op.Deprecated {
+				continue
+			}
+			sunset, found := op.Extensions.GetString(sunsetExtension)
+			switch {
+			case !found:
+				results = append(results, Finding{
+					Location: op.Key.Location(),
+					Message:  fmt.Sprintf("deprecated operation does not declare %s", sunsetExtension),
+				})
+			case !validSunset(sunset):
+				results = append(results, Finding{
+					Location: op.Key.Location() + "." + sunsetExtension,
+					Message:  fmt.Sprintf("sunset '%s' is not a date of the form 2006-01-02", sunset),
+				})
+			}
+		}
+		return results
+	},
+}
+
+func validSunset(sunset string) bool {
+	_, err := time.Parse("2006-01-02", sunset)
+	return err == nil
+}
@@ -0,0 +1,38 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestLint_deprecatedWithoutSunset(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"get": {"deprecated": true, "responses": {"200": {"description": "ok"}}},
+				"post": {"deprecated": true, "x-sunset": "2027-01-31", "responses": {"201": {"description": "created"}}},
+				"put": {"deprecated": true, "x-sunset": "next year", "responses": {"200": {"description": "ok"}}},
+				"delete": {"x-sunset": "soon", "responses": {"204": {"description": "deleted"}}}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	var got []string
+	for _, f := range Lint(swagger, deprecatedWithoutSunsetRule) {
+		got = append(got, f.String())
+	}
+	expected := []string{
+		".paths./pets.get: warning [deprecated-without-sunset] deprecated operation does not declare x-sunset",
+		".paths./pets.put.x-sunset: warning [deprecated-without-sunset] sunset 'next year' is not a date of the form 2006-01-02",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Lint() =\n%v\nwant\n%v", got, expected)
+	}
+}
@@ -0,0 +1,3 @@
+// Package lint provides the checking of parsed swagger specifications for issues that are valid per the
+// specification but are likely mistakes or poor practice
+package lint
@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// RequiredResponseHeaders returns a rule reporting each response missing any of the named headers, where status
+// selects the responses to check by their status code or "default" and all are checked when it is nil
+func RequiredResponseHeaders(names []string, status func(status string) bool) Rule {
+	if status == nil {
+		status = func(string) bool { return true }
+	}
+	return Rule{
+		ID:          "required-response-headers",
+		Description: "responses should declare the standard response headers",
+		Severity:    SeverityWarning,
+		Check: func(swagger *spec.Swagger) []Finding {
+			var results []Finding
+			check := func(op *spec.Operation, code string, r *spec.Response) {
+				if r == nil || !status(code) {
+					return
+				}
+				var missing []string
+				for _, name := range names {
+					if !hasHeader(r, name) {
+						missing = append(missing, name)
+					}
+				}
+				if len(missing) > 0 {
+					results = append(results, Finding{
+						Location: op.Key.Location() + ".responses." + code,
+						Message:  fmt.Sprintf("response is missing the headers: %s", strings.Join(missing, ", ")),
+					})
+				}
+			}
+			for _, op := range swagger.Operations() {
+				for _, code := range statusCodes(op) {
+					check(op, strconv.Itoa(code), op.Responses.ByStatusCode[code])
+				}
+				check(op, "default", op.Responses.Default)
+			}
+			return results
+		},
+	}
+}
+
+// hasHeader returns true if the response declares the header, where header names are case-insensitive
+func hasHeader(r *spec.Response, name string) bool {
+	for h := range r.Headers {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+	return false
+}
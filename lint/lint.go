@@ -0,0 +1,139 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// Severity defines how serious a Finding is
+type Severity int
+
+const (
+	// SeverityInfo is used for suggestions
+	SeverityInfo Severity = iota
+	// SeverityWarning is used for likely mistakes
+	SeverityWarning
+	// SeverityError is used for definite mistakes
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Finding is a single issue found within a swagger spec by a Rule
+type Finding struct {
+	RuleID   string
+	Severity Severity
+	// Location is the location within the swagger spec in the same form used by spec.ParseError
+	Location string
+	Message  string
+	// Fix is an optional JSON Patch against the swagger spec which corrects the issue
+	Fix []spec.PatchOperation
+	// DocsURL optionally links to an explanation of the issue and how to avoid it
+	DocsURL string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s [%s] %s", f.Location, f.Severity, f.RuleID, f.Message)
+}
+
+// Rule defines a single check of a swagger spec
+type Rule struct {
+	// ID uniquely identifies the rule
+	ID string
+	// Description explains what the rule checks
+	Description string
+	// Severity is used for each Finding of the rule
+	Severity Severity
+	// DocsURL optionally links to an explanation of the rule, which is used for each Finding that does not declare its
+	// own
+	DocsURL string
+	// Check returns the findings of the rule, which need only declare their location and message
+	Check func(swagger *spec.Swagger) []Finding
+}
+
+// DefaultRules returns all the built-in rules
+func DefaultRules() []Rule {
+	return []Rule{
+		responseSchemaWithoutJSONRule,
+		jsonWithoutResponseSchemaRule,
+		noContentResponseSchemaRule,
+		operationWithoutSecurityRule,
+		insecureSchemeRule,
+		apiKeyInQueryRule,
+		missingAuthResponsesRule,
+		undeclaredScopeRule,
+		invalidSecuritySchemeRule,
+		invalidLiteralRule,
+		nonConformingValueRule,
+		requiredReadOnlyRule,
+		deprecatedWithoutSunsetRule,
+	}
+}
+
+// Result is the outcome of linting with a deadline
+type Result struct {
+	// Findings are sorted by location and rule
+	Findings []Finding
+	// Truncated is true when the context was done before all rules were checked
+	Truncated bool
+	// Checked are the IDs of the rules checked
+	Checked []string
+	// Skipped are the IDs of the rules not checked because the context was done
+	Skipped []string
+}
+
+// Lint checks the swagger spec using the rules, or the DefaultRules if none are specified, and returns the findings
+// sorted by location and rule
+func Lint(swagger *spec.Swagger, rules ...Rule) []Finding {
+	return LintContext(context.Background(), swagger, rules...).Findings
+}
+
+// LintContext checks the swagger spec like Lint but stops checking further rules once the context is done, returning
+// the partial findings marked as truncated along with which rules were skipped
+func LintContext(ctx context.Context, swagger *spec.Swagger, rules ...Rule) Result {
+	var result Result
+	if swagger == nil {
+		return result
+	}
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	for i, rule := range rules {
+		if ctx.Err() != nil {
+			result.Truncated = true
+			for _, skipped := range rules[i:] {
+				result.Skipped = append(result.Skipped, skipped.ID)
+			}
+			break
+		}
+		for _, f := range rule.Check(swagger) {
+			f.RuleID = rule.ID
+			f.Severity = rule.Severity
+			if f.DocsURL == "" {
+				f.DocsURL = rule.DocsURL
+			}
+			result.Findings = append(result.Findings, f)
+		}
+		result.Checked = append(result.Checked, rule.ID)
+	}
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		if result.Findings[i].Location != result.Findings[j].Location {
+			return result.Findings[i].Location < result.Findings[j].Location
+		}
+		return result.Findings[i].RuleID < result.Findings[j].RuleID
+	})
+	return result
+}
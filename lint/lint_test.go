@@ -0,0 +1,79 @@
+package lint
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestLintContext(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(`{"swagger": "2.0"}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rules := []Rule{
+		{ID: "first", Check: func(*spec.Swagger) []Finding {
+			return []Finding{{Location: ".", Message: "first"}}
+		}},
+		{ID: "slow", Check: func(*spec.Swagger) []Finding {
+			cancel()
+			return []Finding{{Location: ".", Message: "slow"}}
+		}},
+		{ID: "never", Check: func(*spec.Swagger) []Finding {
+			t.Error("rule should not be checked after the context is done")
+			return nil
+		}},
+	}
+	result := LintContext(ctx, swagger, rules...)
+	if !result.Truncated {
+		t.Error("result should be truncated")
+	}
+	if len(result.Findings) != 2 {
+		t.Errorf("got %d findings, want 2: %v", len(result.Findings), result.Findings)
+	}
+	if !reflect.DeepEqual(result.Checked, []string{"first", "slow"}) {
+		t.Errorf("Checked = %v", result.Checked)
+	}
+	if !reflect.DeepEqual(result.Skipped, []string{"never"}) {
+		t.Errorf("Skipped = %v", result.Skipped)
+	}
+}
+
+func TestLint_docsURL(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(`{"swagger": "2.0"}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	rule := Rule{
+		ID:      "documented",
+		DocsURL: "https://example.com/rules/documented",
+		Check: func(*spec.Swagger) []Finding {
+			return []Finding{
+				{Location: ".a", Message: "from the rule"},
+				{Location: ".b", Message: "its own", DocsURL: "https://example.com/rules/documented#b"},
+			}
+		},
+	}
+	var got []string
+	for _, f := range Lint(swagger, rule) {
+		got = append(got, f.DocsURL)
+	}
+	expected := []string{"https://example.com/rules/documented", "https://example.com/rules/documented#b"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("DocsURL = %v, want %v", got, expected)
+	}
+}
+
+func TestDefaultRules_uniqueIDs(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, rule := range DefaultRules() {
+		if rule.ID == "" || seen[rule.ID] {
+			t.Errorf("rule ID '%s' is empty or duplicated", rule.ID)
+		}
+		seen[rule.ID] = true
+	}
+}
@@ -0,0 +1,69 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/erraggy/goats/formats"
+	"github.com/erraggy/goats/spec"
+)
+
+var invalidLiteralRule = Rule{
+	ID:          "invalid-literal",
+	Description: "default, enum and example values must match their declared type and format",
+	Severity:    SeverityError,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		for _, lit := range swagger.Literals() {
+			if err := validateLiteral(lit); err != nil {
+				results = append(results, Finding{
+					Location: lit.Location,
+					Message:  err.Error(),
+				})
+			}
+		}
+		return results
+	},
+}
+
+var nonConformingValueRule = Rule{
+	ID:          "non-conforming-value",
+	Description: "default and example values must conform to the constraints of their own schema or type",
+	Severity:    SeverityError,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		for _, lit := range swagger.Literals() {
+			// enum values define the constraint and values of the wrong type are reported by invalid-literal
+			if lit.Field == "enum" || validateLiteral(lit) != nil {
+				continue
+			}
+			for _, err := range swagger.ValidateValue(lit.Schema, lit.Value) {
+				f := Finding{
+					Location: lit.Location,
+					Message:  err.Error(),
+				}
+				var valueErr *spec.ValueError
+				if errors.As(err, &valueErr) {
+					f.Location += valueErr.Location
+					f.Message = valueErr.Message
+				}
+				results = append(results, f)
+			}
+		}
+		return results
+	},
+}
+
+// validateLiteral returns an error unless the literal is valid for one of its declared types
+func validateLiteral(lit spec.Literal) error {
+	if len(lit.Types) == 0 {
+		return formats.Validate("", lit.Format, lit.Value)
+	}
+	for _, typ := range lit.Types {
+		if formats.MatchesType(typ, lit.Value) {
+			return formats.Validate(typ, lit.Format, lit.Value)
+		}
+	}
+	return fmt.Errorf("expected type %s but got %s", strings.Join(lit.Types, " or "), lit.Value.Type())
+}
@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestLint_literals(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"parameters": {
+			"limit": {"name": "limit", "in": "query", "type": "integer", "format": "int32", "default": 5000000000}
+		},
+		"paths": {
+			"/pets": {
+				"get": {
+					"parameters": [
+						{"name": "since", "in": "query", "type": "string", "format": "date", "enum": ["2024-01-01", "yesterday"]},
+						{"name": "ids", "in": "query", "type": "array", "items": {"type": "integer", "default": "1"}}
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"headers": {"X-Rate": {"type": "number", "format": "float", "default": 1.5}},
+							"schema": {"$ref": "#/definitions/Pet"}
+						}
+					}
+				}
+			}
+		},
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "example": 5},
+					"avatar": {"type": "string", "format": "byte", "example": "not base64"},
+					"born": {"type": "string", "format": "date-time", "example": "2024-01-01T00:00:00Z"}
+				}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	var got []string
+	for _, f := range Lint(swagger, invalidLiteralRule) {
+		got = append(got, f.String())
+	}
+	expected := []string{
+		`.definitions.Pet.properties.avatar.example: error [invalid-literal] invalid byte value "not base64": not base64 encoded`,
+		`.definitions.Pet.properties.name.example: error [invalid-literal] expected type string but got number`,
+		`.parameters.limit.default: error [invalid-literal] invalid int32 value 5000000000: out of range`,
+		`.paths./pets.get.parameters[0].enum[1]: error [invalid-literal] invalid date value "yesterday": not of the form 2006-01-02`,
+		`.paths./pets.get.parameters[1].items.default: error [invalid-literal] expected type integer but got string`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Lint() =\n%v\nwant\n%v", got, expected)
+	}
+}
+
+func TestLint_nonConformingValues(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"parameters": [
+						{"name": "limit", "in": "query", "type": "integer", "maximum": 100, "default": 500},
+						{"name": "sort", "in": "query", "type": "string", "enum": ["asc", "desc"], "default": "up"},
+						{"name": "ids", "in": "query", "type": "array", "maxItems": 2, "items": {"type": "string", "minLength": 2, "default": "a"}, "default": ["ab", "c", "de"]}
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"headers": {"X-Rate": {"type": "string", "pattern": "^[0-9]+$", "default": "fast"}},
+							"schema": {"$ref": "#/definitions/Pet"}
+						}
+					}
+				}
+			}
+		},
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string", "minLength": 2, "example": "T"},
+					"age": {"type": "integer", "example": "old"}
+				},
+				"example": {"name": "Tom", "age": -1, "tags": [{"name": "x"}]}
+			},
+			"Owner": {
+				"type": "object",
+				"properties": {"pets": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+				"example": {"pets": [{"name": "Tom"}, {"name": "J"}]}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	var got []string
+	for _, f := range Lint(swagger, nonConformingValueRule) {
+		got = append(got, f.String())
+	}
+	expected := []string{
+		`.definitions.Owner.example.pets[1].name: error [non-conforming-value] length of 1 is less than the minLength of 2`,
+		`.definitions.Pet.properties.name.example: error [non-conforming-value] length of 1 is less than the minLength of 2`,
+		`.paths./pets.get.parameters[0].default: error [non-conforming-value] 500 exceeds the maximum of 100`,
+		`.paths./pets.get.parameters[1].default: error [non-conforming-value] value "up" is not one of the enum values`,
+		`.paths./pets.get.parameters[2].default: error [non-conforming-value] has 3 items which is more than the maxItems of 2`,
+		`.paths./pets.get.parameters[2].default[1]: error [non-conforming-value] length of 1 is less than the minLength of 2`,
+		`.paths./pets.get.parameters[2].items.default: error [non-conforming-value] length of 1 is less than the minLength of 2`,
+		`.paths./pets.get.responses.200.headers.X-Rate.default: error [non-conforming-value] 'fast' does not match the pattern '^[0-9]+$'`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Lint() =\n%v\nwant\n%v", got, expected)
+	}
+}
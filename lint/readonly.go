@@ -0,0 +1,88 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+)
+
+var requiredReadOnlyRule = Rule{
+	ID:          "required-read-only",
+	Description: "read-only properties must not be sent in requests so should not be required by request body schemas",
+	Severity:    SeverityWarning,
+	Check: func(swagger *spec.Swagger) []Finding {
+		c := readOnlyChecker{swagger: swagger, checked: make(map[string]bool)}
+		for _, op := range swagger.Operations() {
+			for i, p := range op.Parameters {
+				loc := fmt.Sprintf("%s.parameters[%d]", op.Key.Location(), i)
+				if name, ok := p.Ref.ParameterName(); ok {
+					// a global parameter is checked only once at its own location however many operations refer to it
+					global, exists := swagger.Parameters[name]
+					if !exists || c.checked["#/parameters/"+name] {
+						continue
+					}
+					c.checked["#/parameters/"+name] = true
+					p, loc = global, ".parameters."+name
+				}
+				if p.In == spec.InBody && p.Schema != nil {
+					c.check(loc+".schema", p.Schema)
+				}
+			}
+		}
+		return c.results
+	},
+}
+
+// readOnlyChecker finds the required read-only properties of request body schemas, checking each referenced
+// definition only once
+type readOnlyChecker struct {
+	swagger *spec.Swagger
+	checked map[string]bool
+	results []Finding
+}
+
+func (c *readOnlyChecker) check(loc string, s *spec.Schema) {
+	if name, ok := s.Ref.DefinitionName(); ok {
+		if c.checked[name] {
+			return
+		}
+		c.checked[name] = true
+		if def, exists := c.swagger.Definitions[name]; exists {
+			c.check(".definitions."+name, &def)
+		}
+		return
+	}
+	var readOnly []string
+	for _, name := range s.Required {
+		if prop, exists := s.Properties[name]; exists && prop.IsReadOnly {
+			readOnly = append(readOnly, name)
+		}
+	}
+	if len(readOnly) > 0 {
+		c.results = append(c.results, Finding{
+			Location: loc + ".required",
+			Message:  fmt.Sprintf("schema of a request body requires the read-only properties: %s", strings.Join(readOnly, ", ")),
+		})
+	}
+	if items, single := s.Items.AsSchema(); single {
+		c.check(loc+".items", items)
+	}
+	for i := range s.AllOf {
+		c.check(loc+".allOf["+strconv.Itoa(i)+"]", &s.AllOf[i])
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop := s.Properties[name]
+		c.check(loc+".properties."+name, &prop)
+	}
+	if additional, ok := s.AdditionalProperties.AsSchema(); ok {
+		c.check(loc+".additionalProperties", additional)
+	}
+}
@@ -0,0 +1,91 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestLint_requiredReadOnly(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"parameters": {
+			"report": {"name": "report", "in": "body", "schema": {"$ref": "#/definitions/Report"}},
+			"audit": {
+				"name": "audit",
+				"in": "body",
+				"schema": {"type": "object", "required": ["at"], "properties": {"at": {"type": "string", "readOnly": true}}}
+			}
+		},
+		"paths": {
+			"/reports": {
+				"post": {"parameters": [{"$ref": "#/parameters/report"}], "responses": {"201": {"description": "created"}}},
+				"put": {"parameters": [{"$ref": "#/parameters/report"}], "responses": {"200": {"description": "ok"}}}
+			},
+			"/audits": {
+				"post": {"parameters": [{"$ref": "#/parameters/audit"}], "responses": {"201": {"description": "created"}}},
+				"put": {"parameters": [{"$ref": "#/parameters/audit"}], "responses": {"200": {"description": "ok"}}}
+			},
+			"/pets": {
+				"post": {
+					"parameters": [{"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}],
+					"responses": {"201": {"description": "created", "schema": {"$ref": "#/definitions/Report"}}}
+				},
+				"put": {
+					"parameters": [{"name": "pets", "in": "body", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}],
+					"responses": {"200": {"description": "ok"}}
+				}
+			},
+			"/owners": {
+				"post": {
+					"parameters": [{
+						"name": "owner",
+						"in": "body",
+						"schema": {
+							"type": "object",
+							"required": ["name", "id"],
+							"properties": {"id": {"type": "integer", "readOnly": true}, "name": {"type": "string"}}
+						}
+					}],
+					"responses": {"201": {"description": "created"}}
+				}
+			}
+		},
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"required": ["id", "name", "created"],
+				"properties": {
+					"id": {"type": "integer", "readOnly": true},
+					"name": {"type": "string"},
+					"created": {"type": "string", "readOnly": true},
+					"owner": {"$ref": "#/definitions/Pet"}
+				}
+			},
+			"Report": {
+				"type": "object",
+				"required": ["id"],
+				"properties": {"id": {"type": "integer", "readOnly": true}}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	var got []string
+	for _, f := range Lint(swagger, requiredReadOnlyRule) {
+		got = append(got, f.String())
+	}
+	expected := []string{
+		".definitions.Pet.required: warning [required-read-only] schema of a request body requires the read-only properties: id, created",
+		".definitions.Report.required: warning [required-read-only] schema of a request body requires the read-only properties: id",
+		".parameters.audit.schema.required: warning [required-read-only] schema of a request body requires the read-only properties: at",
+		".paths./owners.post.parameters[0].schema.required: warning [required-read-only] schema of a request body requires the read-only properties: id",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Lint() =\n%v\nwant\n%v", got, expected)
+	}
+}
@@ -0,0 +1,147 @@
+package lint
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+)
+
+var responseSchemaWithoutJSONRule = Rule{
+	ID:          "response-schema-without-json",
+	Description: "operations declaring a response schema should produce a JSON media type",
+	Severity:    SeverityWarning,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		for _, op := range swagger.Operations() {
+			produces := effectiveProduces(swagger, op)
+			if len(produces) == 0 || anyJSON(produces) {
+				continue
+			}
+			for _, code := range statusCodes(op) {
+				if op.Responses.ByStatusCode[code].Schema != nil {
+					results = append(results, Finding{
+						Location: fmt.Sprintf("%s.responses.%d.schema", op.Key.Location(), code),
+						Message:  fmt.Sprintf("response schema is declared but the operation only produces %s", strings.Join(produces, ", ")),
+						Fix:      addJSONProducesFix(op, produces),
+					})
+				}
+			}
+		}
+		return results
+	},
+}
+
+var jsonWithoutResponseSchemaRule = Rule{
+	ID:          "json-without-response-schema",
+	Description: "operations producing JSON should declare a schema for at least one successful response",
+	Severity:    SeverityWarning,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		for _, op := range swagger.Operations() {
+			if op.Key.Method == http.MethodHead || !anyJSON(effectiveProduces(swagger, op)) {
+				continue
+			}
+			var hasContent, hasSchema bool
+			for _, code := range statusCodes(op) {
+				if code < 200 || code > 299 || code == http.StatusNoContent {
+					continue
+				}
+				hasContent = true
+				if op.Responses.ByStatusCode[code].Schema != nil {
+					hasSchema = true
+				}
+			}
+			if hasContent && !hasSchema {
+				results = append(results, Finding{
+					Location: op.Key.Location() + ".responses",
+					Message:  "operation produces JSON but no successful response declares a schema",
+				})
+			}
+		}
+		return results
+	},
+}
+
+var noContentResponseSchemaRule = Rule{
+	ID:          "no-content-response-schema",
+	Description: "204 No Content responses must not declare a schema",
+	Severity:    SeverityWarning,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		for _, op := range swagger.Operations() {
+			if r := op.Responses.ByStatusCode[http.StatusNoContent]; r != nil && r.Schema != nil {
+				results = append(results, Finding{
+					Location: fmt.Sprintf("%s.responses.%d.schema", op.Key.Location(), http.StatusNoContent),
+					Message:  "204 No Content response declares a schema",
+					Fix: []spec.PatchOperation{{
+						Op:   "remove",
+						Path: operationPointer(op, "responses", strconv.Itoa(http.StatusNoContent), "schema"),
+					}},
+				})
+			}
+		}
+		return results
+	},
+}
+
+// addJSONProducesFix returns the patch adding application/json to what the operation produces, declaring them on the
+// operation when they were only inherited from the swagger spec
+func addJSONProducesFix(op *spec.Operation, produces []string) []spec.PatchOperation {
+	if len(op.Produces) > 0 {
+		return []spec.PatchOperation{{
+			Op:    "add",
+			Path:  operationPointer(op, "produces", "-"),
+			Value: "application/json",
+		}}
+	}
+	return []spec.PatchOperation{{
+		Op:    "add",
+		Path:  operationPointer(op, "produces"),
+		Value: append(append([]string(nil), produces...), "application/json"),
+	}}
+}
+
+// operationPointer returns the JSON Pointer to the operation followed by the tokens
+func operationPointer(op *spec.Operation, tokens ...string) string {
+	return spec.JSONPointer(append([]string{"paths", op.Key.Path, strings.ToLower(op.Key.Method)}, tokens...)...)
+}
+
+// effectiveProduces returns the media types produced by the operation which override those of the swagger spec
+func effectiveProduces(swagger *spec.Swagger, op *spec.Operation) []string {
+	if len(op.Produces) > 0 {
+		return op.Produces
+	}
+	return swagger.Produces
+}
+
+// isJSON returns true if the media type is JSON or any structured syntax suffixed with +json
+func isJSON(mediaType string) bool {
+	if i := strings.IndexByte(mediaType, ';'); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func anyJSON(mediaTypes []string) bool {
+	for _, mt := range mediaTypes {
+		if isJSON(mt) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusCodes returns the sorted status codes of the operation's responses
+func statusCodes(op *spec.Operation) []int {
+	results := make([]int, 0, len(op.Responses.ByStatusCode))
+	for code := range op.Responses.ByStatusCode {
+		results = append(results, code)
+	}
+	sort.Ints(results)
+	return results
+}
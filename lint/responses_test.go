@@ -0,0 +1,68 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestLint_responses(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"produces": ["application/json"],
+		"paths": {
+			"/reports": {
+				"get": {
+					"produces": ["text/csv"],
+					"responses": {"200": {"description": "ok", "schema": {"type": "string"}}}
+				},
+				"post": {
+					"responses": {"201": {"description": "created"}, "400": {"description": "bad", "schema": {"type": "object"}}}
+				},
+				"delete": {
+					"responses": {"204": {"description": "deleted", "schema": {"type": "object"}}}
+				}
+			},
+			"/health": {
+				"get": {
+					"produces": ["application/problem+json; charset=utf-8"],
+					"responses": {"200": {"description": "ok", "schema": {"type": "object"}}}
+				},
+				"head": {
+					"responses": {"200": {"description": "ok"}}
+				}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	expected := []Finding{
+		{
+			RuleID:   "no-content-response-schema",
+			Severity: SeverityWarning,
+			Location: ".paths./reports.delete.responses.204.schema",
+			Message:  "204 No Content response declares a schema",
+			Fix:      []spec.PatchOperation{{Op: "remove", Path: "/paths/~1reports/delete/responses/204/schema"}},
+		},
+		{
+			RuleID:   "response-schema-without-json",
+			Severity: SeverityWarning,
+			Location: ".paths./reports.get.responses.200.schema",
+			Message:  "response schema is declared but the operation only produces text/csv",
+			Fix:      []spec.PatchOperation{{Op: "add", Path: "/paths/~1reports/get/produces/-", Value: "application/json"}},
+		},
+		{
+			RuleID:   "json-without-response-schema",
+			Severity: SeverityWarning,
+			Location: ".paths./reports.post.responses",
+			Message:  "operation produces JSON but no successful response declares a schema",
+		},
+	}
+	if got := Lint(swagger); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Lint() =\n%v\nwant\n%v", got, expected)
+	}
+}
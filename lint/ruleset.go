@@ -0,0 +1,76 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// severityOff disables a rule within a Ruleset
+const severityOff = "off"
+
+// Ruleset configures the rules to check, read from JSON of the form:
+//
+//	{"rules": {"operation-without-security": "off", "missing-auth-responses": "error"}}
+type Ruleset struct {
+	// Rules maps the ID of a rule to "off" to disable it, or to the severity used for its findings
+	Rules map[string]string `json:"rules"`
+}
+
+// ParseRuleset returns the Ruleset from its JSON, failing for any unknown fields
+func ParseRuleset(raw []byte) (Ruleset, error) {
+	var rs Ruleset
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rs); err != nil {
+		return Ruleset{}, fmt.Errorf("invalid ruleset: %w", err)
+	}
+	return rs, nil
+}
+
+// ParseSeverity returns the Severity with the name returned by its String method
+func ParseSeverity(name string) (Severity, error) {
+	for _, s := range []Severity{SeverityInfo, SeverityWarning, SeverityError} {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("severity should be one of [info warning error] but got: '%s'", name)
+}
+
+// Apply returns the rules without those turned off by the ruleset and with their configured severity, failing if the
+// ruleset configures a rule that is not one of the rules or uses an invalid severity
+func (rs Ruleset) Apply(rules []Rule) ([]Rule, error) {
+	known := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		known[rule.ID] = struct{}{}
+	}
+	ids := make([]string, 0, len(rs.Rules))
+	for id := range rs.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if _, exists := known[id]; !exists {
+			return nil, fmt.Errorf("ruleset configures the unknown rule '%s'", id)
+		}
+		if level := rs.Rules[id]; level != severityOff {
+			if _, err := ParseSeverity(level); err != nil {
+				return nil, fmt.Errorf("ruleset rule '%s': %w", id, err)
+			}
+		}
+	}
+
+	results := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if level, configured := rs.Rules[rule.ID]; configured {
+			if level == severityOff {
+				continue
+			}
+			rule.Severity, _ = ParseSeverity(level)
+		}
+		results = append(results, rule)
+	}
+	return results, nil
+}
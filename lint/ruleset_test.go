@@ -0,0 +1,66 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuleset_Apply(t *testing.T) {
+	rules := []Rule{
+		{ID: "a", Severity: SeverityWarning},
+		{ID: "b", Severity: SeverityInfo},
+		{ID: "c", Severity: SeverityError},
+	}
+	tests := map[string]struct {
+		raw     string
+		want    map[string]Severity
+		wantErr bool
+	}{
+		"empty ruleset keeps all rules": {
+			raw:  `{}`,
+			want: map[string]Severity{"a": SeverityWarning, "b": SeverityInfo, "c": SeverityError},
+		},
+		"turn off and override severity": {
+			raw:  `{"rules": {"a": "off", "b": "error"}}`,
+			want: map[string]Severity{"b": SeverityError, "c": SeverityError},
+		},
+		"unknown rule": {
+			raw:     `{"rules": {"d": "off"}}`,
+			wantErr: true,
+		},
+		"invalid severity": {
+			raw:     `{"rules": {"a": "fatal"}}`,
+			wantErr: true,
+		},
+		"unknown field": {
+			raw:     `{"rule": {"a": "off"}}`,
+			wantErr: true,
+		},
+		"invalid JSON": {
+			raw:     `{"rules":`,
+			wantErr: true,
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			rs, err := ParseRuleset([]byte(tt.raw))
+			var applied []Rule
+			if err == nil {
+				applied, err = rs.Apply(rules)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := make(map[string]Severity, len(applied))
+			for _, rule := range applied {
+				got[rule.ID] = rule.Severity
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package lint
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+)
+
+var operationWithoutSecurityRule = Rule{
+	ID:          "operation-without-security",
+	Description: "operations of a spec declaring security definitions should require security unless explicitly opted out",
+	Severity:    SeverityWarning,
+	Check: func(swagger *spec.Swagger) []Finding {
+		if len(swagger.SecurityDefinitions) == 0 {
+			// without any security schemes the API is public by design
+			return nil
+		}
+		var results []Finding
+		for _, op := range swagger.Operations() {
+			// an explicitly empty security array on the operation is a deliberate opt out
+			if op.Security == nil && len(swagger.Security) == 0 {
+				results = append(results, Finding{
+					Location: op.Key.Location(),
+					Message:  "operation has no security requirements",
+				})
+			}
+		}
+		return results
+	},
+}
+
+var insecureSchemeRule = Rule{
+	ID:          "insecure-scheme",
+	Description: "http should not be offered alongside https",
+	Severity:    SeverityWarning,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		check := func(schemes []string, loc string, pointer func(tokens ...string) string) {
+			httpIndex := -1
+			var hasHTTPS bool
+			for i, scheme := range schemes {
+				switch strings.ToLower(scheme) {
+				case "http":
+					httpIndex = i
+				case "https":
+					hasHTTPS = true
+				}
+			}
+			if httpIndex >= 0 && hasHTTPS {
+				results = append(results, Finding{
+					Location: loc,
+					Message:  "http is offered alongside https",
+					Fix:      []spec.PatchOperation{{Op: "remove", Path: pointer("schemes", strconv.Itoa(httpIndex))}},
+				})
+			}
+		}
+		check(swagger.Schemes, ".schemes", spec.JSONPointer)
+		for _, op := range swagger.Operations() {
+			op := op
+			check(op.Schemes, op.Key.Location()+".schemes", func(tokens ...string) string {
+				return operationPointer(op, tokens...)
+			})
+		}
+		return results
+	},
+}
+
+var apiKeyInQueryRule = Rule{
+	ID:          "api-key-in-query",
+	Description: "API keys should be sent in a header rather than the query where they are easily logged",
+	Severity:    SeverityWarning,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		for _, name := range sortedSchemeNames(swagger) {
+			if ss := swagger.SecurityDefinitions[name]; ss.Type == "apiKey" && ss.In == "query" {
+				results = append(results, Finding{
+					Location: ".securityDefinitions." + name + ".in",
+					Message:  fmt.Sprintf("API key %s is sent in the query", ss.Name),
+				})
+			}
+		}
+		return results
+	},
+}
+
+var missingAuthResponsesRule = Rule{
+	ID:          "missing-auth-responses",
+	Description: "secured operations should declare 401 and 403 responses",
+	Severity:    SeverityInfo,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		for _, op := range swagger.Operations() {
+			if !requiresSecurity(op.EffectiveSecurity(swagger)) {
+				continue
+			}
+			var missing []string
+			for _, code := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+				if _, exists := op.Responses.ByStatusCode[code]; !exists {
+					missing = append(missing, strconv.Itoa(code))
+				}
+			}
+			if len(missing) > 0 {
+				results = append(results, Finding{
+					Location: op.Key.Location() + ".responses",
+					Message:  fmt.Sprintf("secured operation is missing the responses: %s", strings.Join(missing, ", ")),
+				})
+			}
+		}
+		return results
+	},
+}
+
+var undeclaredScopeRule = Rule{
+	ID:          "undeclared-scope",
+	Description: "OAuth2 scopes required by security requirements must be declared by their security scheme",
+	Severity:    SeverityError,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		check := func(reqs []spec.SecurityRequirements, loc string) {
+			for i, req := range reqs {
+				names := make([]string, 0, len(req))
+				for name := range req {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					ss, declared := swagger.SecurityDefinitions[name]
+					if !declared || ss.Type != "oauth2" {
+						continue
+					}
+					for j, scope := range req[name] {
+						if _, exists := ss.Scopes.Values[scope]; !exists {
+							results = append(results, Finding{
+								Location: fmt.Sprintf("%s.%d.%s.%d", loc, i, name, j),
+								Message:  fmt.Sprintf("scope %s is not declared by the security scheme %s", scope, name),
+							})
+						}
+					}
+				}
+			}
+		}
+		check(swagger.Security, ".security")
+		for _, op := range swagger.Operations() {
+			check(op.Security, op.Key.Location()+".security")
+		}
+		return results
+	},
+}
+
+var invalidSecuritySchemeRule = Rule{
+	ID:          "invalid-security-scheme",
+	Description: "security schemes must declare the fields required by their type and flow, and only those that apply",
+	Severity:    SeverityError,
+	Check: func(swagger *spec.Swagger) []Finding {
+		var results []Finding
+		for _, name := range sortedSchemeNames(swagger) {
+			ss := swagger.SecurityDefinitions[name]
+			loc := ".securityDefinitions." + name
+			report := func(field, format string, args ...any) {
+				results = append(results, Finding{
+					Location: loc + "." + field,
+					Message:  fmt.Sprintf(format, args...),
+				})
+			}
+			// the fields that may be declared by the scheme in the order they are defined by the specification
+			fields := []struct {
+				name     string
+				declared bool
+				required bool
+				applies  bool
+			}{
+				{name: "name", declared: ss.Name != ""},
+				{name: "in", declared: ss.In != ""},
+				{name: "flow", declared: ss.Flow != ""},
+				{name: "authorizationUrl", declared: ss.AuthorizationURL != ""},
+				{name: "tokenUrl", declared: ss.TokenURL != ""},
+				{name: "scopes", declared: len(ss.Scopes.Values) > 0},
+			}
+			// apply marks the fields as applying to the scheme and if they are required
+			apply := func(required bool, names ...string) {
+				for _, n := range names {
+					for i := range fields {
+						if fields[i].name == n {
+							fields[i].applies = true
+							fields[i].required = fields[i].required || required
+						}
+					}
+				}
+			}
+			switch ss.Type {
+			case "basic":
+			case "apiKey":
+				apply(true, "name", "in")
+				if ss.In != "" && ss.In != "query" && ss.In != "header" {
+					report("in", "apiKey security scheme in should be one of [query header] but got: '%s'", ss.In)
+				}
+			case "oauth2":
+				apply(true, "flow")
+				apply(false, "scopes")
+				switch ss.Flow {
+				case "implicit":
+					apply(true, "authorizationUrl")
+				case "password", "application":
+					apply(true, "tokenUrl")
+				case "accessCode":
+					apply(true, "authorizationUrl", "tokenUrl")
+				case "":
+				default:
+					report("flow", "oauth2 security scheme flow should be one of [implicit password application accessCode] but got: '%s'", ss.Flow)
+					apply(false, "authorizationUrl", "tokenUrl")
+				}
+			default:
+				report("type", "security scheme type should be one of [basic apiKey oauth2] but got: '%s'", ss.Type)
+				continue
+			}
+			for _, f := range fields {
+				switch {
+				case f.required && !f.declared:
+					report(f.name, "%s security scheme requires %s", schemeDescription(ss), f.name)
+				case f.declared && !f.applies:
+					report(f.name, "%s is not used by %s security schemes", f.name, schemeDescription(ss))
+				}
+			}
+		}
+		return results
+	},
+}
+
+// schemeDescription returns the type of the security scheme along with its flow if it has one
+func schemeDescription(ss spec.SecurityScheme) string {
+	if ss.Type == "oauth2" && ss.Flow != "" {
+		return ss.Type + " " + ss.Flow
+	}
+	return ss.Type
+}
+
+// requiresSecurity returns true if the security requirements do not include an empty alternative allowing anonymous
+// access
+func requiresSecurity(reqs []spec.SecurityRequirements) bool {
+	for _, req := range reqs {
+		if len(req) == 0 {
+			return false
+		}
+	}
+	return len(reqs) > 0
+}
+
+func sortedSchemeNames(swagger *spec.Swagger) []string {
+	names := make([]string, 0, len(swagger.SecurityDefinitions))
+	for name := range swagger.SecurityDefinitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,137 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestLint_security(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"schemes": ["http", "https"],
+		"securityDefinitions": {
+			"key": {"type": "apiKey", "name": "api_key", "in": "query"},
+			"oauth": {"type": "oauth2", "flow": "implicit", "authorizationUrl": "https://example.com/auth", "scopes": {"read": "read access"}}
+		},
+		"paths": {
+			"/pets": {
+				"get": {
+					"security": [{"oauth": ["read", "write"]}],
+					"responses": {"200": {"description": "ok"}, "401": {"description": "unauthorized"}}
+				},
+				"post": {
+					"security": [{"key": []}],
+					"responses": {"201": {"description": "created"}, "401": {"description": "unauthorized"}, "403": {"description": "forbidden"}}
+				}
+			},
+			"/health": {
+				"get": {"security": [], "responses": {"200": {"description": "ok"}}}
+			},
+			"/status": {
+				"get": {"schemes": ["https", "http"], "responses": {"200": {"description": "ok"}}}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	rules := []Rule{
+		operationWithoutSecurityRule,
+		insecureSchemeRule,
+		apiKeyInQueryRule,
+		missingAuthResponsesRule,
+		undeclaredScopeRule,
+	}
+	expected := []Finding{
+		{
+			RuleID:   "missing-auth-responses",
+			Severity: SeverityInfo,
+			Location: ".paths./pets.get.responses",
+			Message:  "secured operation is missing the responses: 403",
+		},
+		{
+			RuleID:   "undeclared-scope",
+			Severity: SeverityError,
+			Location: ".paths./pets.get.security.0.oauth.1",
+			Message:  "scope write is not declared by the security scheme oauth",
+		},
+		{
+			RuleID:   "operation-without-security",
+			Severity: SeverityWarning,
+			Location: ".paths./status.get",
+			Message:  "operation has no security requirements",
+		},
+		{
+			RuleID:   "insecure-scheme",
+			Severity: SeverityWarning,
+			Location: ".paths./status.get.schemes",
+			Message:  "http is offered alongside https",
+			Fix:      []spec.PatchOperation{{Op: "remove", Path: "/paths/~1status/get/schemes/1"}},
+		},
+		{
+			RuleID:   "insecure-scheme",
+			Severity: SeverityWarning,
+			Location: ".schemes",
+			Message:  "http is offered alongside https",
+			Fix:      []spec.PatchOperation{{Op: "remove", Path: "/schemes/0"}},
+		},
+		{
+			RuleID:   "api-key-in-query",
+			Severity: SeverityWarning,
+			Location: ".securityDefinitions.key.in",
+			Message:  "API key api_key is sent in the query",
+		},
+	}
+	if got := Lint(swagger, rules...); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Lint() =\n%v\nwant\n%v", got, expected)
+	}
+}
+
+func TestLint_invalidSecurityScheme(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"securityDefinitions": {
+			"basic": {"type": "basic"},
+			"basicFlow": {"type": "basic", "flow": "implicit", "scopes": {"read": "read access"}},
+			"key": {"type": "apiKey", "name": "api_key", "in": "header"},
+			"keyMissing": {"type": "apiKey"},
+			"keyCookie": {"type": "apiKey", "name": "session", "in": "cookie"},
+			"implicit": {"type": "oauth2", "flow": "implicit", "authorizationUrl": "https://example.com/auth", "scopes": {"read": "read access"}},
+			"implicitToken": {"type": "oauth2", "flow": "implicit", "tokenUrl": "https://example.com/token"},
+			"accessCode": {"type": "oauth2", "flow": "accessCode", "authorizationUrl": "https://example.com/auth"},
+			"noFlow": {"type": "oauth2"},
+			"badFlow": {"type": "oauth2", "flow": "device"},
+			"mtls": {"type": "mutualTLS"}
+		},
+		"paths": {}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	var got []string
+	for _, f := range Lint(swagger, invalidSecuritySchemeRule) {
+		got = append(got, f.String())
+	}
+	expected := []string{
+		".securityDefinitions.accessCode.tokenUrl: error [invalid-security-scheme] oauth2 accessCode security scheme requires tokenUrl",
+		".securityDefinitions.badFlow.flow: error [invalid-security-scheme] oauth2 security scheme flow should be one of [implicit password application accessCode] but got: 'device'",
+		".securityDefinitions.basicFlow.flow: error [invalid-security-scheme] flow is not used by basic security schemes",
+		".securityDefinitions.basicFlow.scopes: error [invalid-security-scheme] scopes is not used by basic security schemes",
+		".securityDefinitions.implicitToken.authorizationUrl: error [invalid-security-scheme] oauth2 implicit security scheme requires authorizationUrl",
+		".securityDefinitions.implicitToken.tokenUrl: error [invalid-security-scheme] tokenUrl is not used by oauth2 implicit security schemes",
+		".securityDefinitions.keyCookie.in: error [invalid-security-scheme] apiKey security scheme in should be one of [query header] but got: 'cookie'",
+		".securityDefinitions.keyMissing.in: error [invalid-security-scheme] apiKey security scheme requires in",
+		".securityDefinitions.keyMissing.name: error [invalid-security-scheme] apiKey security scheme requires name",
+		".securityDefinitions.mtls.type: error [invalid-security-scheme] security scheme type should be one of [basic apiKey oauth2] but got: 'mutualTLS'",
+		".securityDefinitions.noFlow.flow: error [invalid-security-scheme] oauth2 security scheme requires flow",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Lint() =\n%q\nwant\n%q", got, expected)
+	}
+}
@@ -0,0 +1,2 @@
+// Package mock provides HTTP servers answering the operations of parsed swagger specifications with sample responses
+package mock
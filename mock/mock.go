@@ -0,0 +1,222 @@
+package mock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erraggy/goats/spec"
+	"github.com/valyala/fastjson"
+)
+
+const (
+	// StatusHeader is the request header selecting the status code of the response to return
+	StatusHeader = "X-Mock-Status"
+	// StatusParam is the query parameter selecting the status code of the response to return, which is used when
+	// StatusHeader is not sent
+	StatusParam = "__status"
+)
+
+// Options defines the configuration of Handler
+type Options struct {
+	// Delay is how long to wait before responding to each request
+	Delay time.Duration
+	// ValidateRequests will reject requests missing required parameters or sending invalid values with a 400
+	ValidateRequests bool
+}
+
+// Handler returns an http.Handler answering each request matching an operation of the swagger spec with one of its
+// responses. Unless selected by StatusHeader or StatusParam, the response with the lowest 2xx status code is used,
+// or else the lowest status code declared, or else the default response with a 200. The body of the response is
+// the example of its schema, or else one generated from the schema.
+func Handler(swagger *spec.Swagger, opts Options) (http.Handler, error) {
+	if swagger == nil {
+		return nil, errors.New("cannot mock a nil swagger")
+	}
+	if opts.Delay < 0 {
+		return nil, fmt.Errorf("invalid delay: %s", opts.Delay)
+	}
+	return &handler{swagger: swagger, opts: opts}, nil
+}
+
+type handler struct {
+	swagger *spec.Swagger
+	opts    Options
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.opts.Delay > 0 {
+		select {
+		case <-time.After(h.opts.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	op, _, found := h.swagger.FindOperation(r.Method, r.URL.Path)
+	if !found {
+		writeErrors(w, http.StatusNotFound, fmt.Sprintf("no operation matches %s %s", r.Method, r.URL.Path))
+		return
+	}
+	if h.opts.ValidateRequests {
+		if errs := h.validate(op, r); len(errs) > 0 {
+			writeErrors(w, http.StatusBadRequest, errs...)
+			return
+		}
+	}
+	status, resp, err := selectResponse(op, r)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var body []byte
+	if resp != nil && resp.Schema != nil && status != http.StatusNoContent {
+		if body, err = json.Marshal(sample(h.swagger, resp.Schema, make(map[string]bool))); err != nil {
+			writeErrors(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+	}
+	if resp != nil {
+		for name, header := range resp.Headers {
+			if s, ok := literalString(header.Default); ok {
+				w.Header().Set(name, s)
+			}
+		}
+	}
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(body)
+	}
+}
+
+// selectResponse returns the status code and response of the operation selected by the request
+func selectResponse(op *spec.Operation, r *http.Request) (int, *spec.Response, error) {
+	selected := r.Header.Get(StatusHeader)
+	if selected == "" {
+		selected = r.URL.Query().Get(StatusParam)
+	}
+	if selected != "" {
+		code, err := strconv.Atoi(selected)
+		if err != nil || code < 100 || code > 599 {
+			return 0, nil, fmt.Errorf("invalid status code: '%s'", selected)
+		}
+		if resp, declared := op.Responses.ByStatusCode[code]; declared {
+			return code, resp, nil
+		}
+		if op.Responses.Default != nil {
+			return code, op.Responses.Default, nil
+		}
+		return 0, nil, fmt.Errorf("status code %d is not declared by %s %s", code, op.Key.Method, op.Key.Path)
+	}
+
+	codes := make([]int, 0, len(op.Responses.ByStatusCode))
+	for code := range op.Responses.ByStatusCode {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		if code >= 200 && code < 300 {
+			return code, op.Responses.ByStatusCode[code], nil
+		}
+	}
+	if len(codes) > 0 {
+		return codes[0], op.Responses.ByStatusCode[codes[0]], nil
+	}
+	return http.StatusOK, op.Responses.Default, nil
+}
+
+// validate returns the problems with the parameters of the request
+func (h *handler) validate(op *spec.Operation, r *http.Request) []string {
+	var (
+		results []string
+		query   = r.URL.Query()
+	)
+	for _, param := range op.EffectiveParameters(h.swagger.Paths.Items[op.Key.Path], h.swagger.Parameters) {
+		var (
+			value   string
+			present bool
+		)
+		switch param.In {
+		case spec.InPath:
+			// the operation would not have matched without its path parameters
+			continue
+		case spec.InQuery:
+			value, present = query.Get(param.Name), query.Has(param.Name)
+		case spec.InHeader:
+			value = r.Header.Get(param.Name)
+			present = value != ""
+		case spec.InFormData:
+			value = r.PostFormValue(param.Name)
+			present = value != "" || (r.PostForm != nil && r.PostForm.Has(param.Name))
+		case spec.InBody:
+			results = append(results, h.validateBody(param, r)...)
+			continue
+		default:
+			continue
+		}
+		if !present {
+			if param.Required {
+				results = append(results, fmt.Sprintf("missing required %s parameter %s", param.In, param.Name))
+			}
+			continue
+		}
+		if !validPrimitive(param.Type, value) {
+			results = append(results, fmt.Sprintf("%s parameter %s should be of type %s but got: '%s'", param.In, param.Name, param.Type, value))
+		}
+	}
+	return results
+}
+
+// validateBody returns the problems with the body of the request
+func (h *handler) validateBody(param spec.Parameter, r *http.Request) []string {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to read body: %s", err)}
+	}
+	if len(strings.TrimSpace(string(raw))) == 0 {
+		if param.Required {
+			return []string{"missing required body"}
+		}
+		return nil
+	}
+	v, err := fastjson.ParseBytes(raw)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid JSON body: %s", err)}
+	}
+	var results []string
+	for _, e := range h.swagger.ValidateValue(param.Schema, v) {
+		results = append(results, "body"+strings.TrimPrefix(e.Error(), "value"))
+	}
+	return results
+}
+
+// validPrimitive returns true if the value of a parameter that is not in the body can be of the type
+func validPrimitive(typ, value string) bool {
+	var err error
+	switch typ {
+	case "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(value)
+	}
+	return err == nil
+}
+
+// writeErrors responds with the status and a JSON body listing the messages
+func writeErrors(w http.ResponseWriter, status int, messages ...string) {
+	body, _ := json.Marshal(struct {
+		Errors []string `json:"errors"`
+	}{Errors: messages})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,186 @@
+package mock
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erraggy/goats/spec"
+)
+
+const testSpec = `{
+	"swagger": "2.0",
+	"info": {"title": "Pet Store", "version": "1.0"},
+	"basePath": "/v1",
+	"parameters": {
+		"limit": {"name": "limit", "in": "query", "type": "integer"}
+	},
+	"paths": {
+		"/pets": {
+			"get": {
+				"parameters": [{"$ref": "#/parameters/limit"}],
+				"responses": {
+					"200": {"description": "ok", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+					"default": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+				}
+			},
+			"post": {
+				"parameters": [
+					{"name": "X-Request-ID", "in": "header", "required": true, "type": "string"},
+					{"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}
+				],
+				"responses": {
+					"201": {"description": "created", "schema": {"$ref": "#/definitions/Pet"}, "headers": {"X-Rate-Limit": {"type": "integer", "default": 100}}},
+					"400": {"description": "invalid", "schema": {"$ref": "#/definitions/Error"}}
+				}
+			}
+		},
+		"/pets/{petId}": {
+			"delete": {
+				"responses": {"204": {"description": "deleted"}, "404": {"description": "missing", "schema": {"$ref": "#/definitions/Error"}}}
+			}
+		}
+	},
+	"definitions": {
+		"Pet": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string", "example": "Rex"},
+				"born": {"type": "string", "format": "date"},
+				"kind": {"type": "string", "enum": ["dog", "cat"]},
+				"age": {"type": "integer", "minimum": 1},
+				"parent": {"$ref": "#/definitions/Pet"}
+			}
+		},
+		"Error": {"type": "object", "properties": {"message": {"type": "string"}}, "example": {"message": "oops"}}
+	}
+}`
+
+func TestHandler(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(testSpec)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	const pet = `{"age":1,"born":"2006-01-02","kind":"dog","name":"Rex","parent":null}`
+	tests := map[string]struct {
+		opts         Options
+		method, path string
+		header       http.Header
+		body         string
+		status       int
+		want         string
+		wantHeader   http.Header
+	}{
+		"lowest 2xx response should be generated": {
+			method: http.MethodGet,
+			path:   "/v1/pets",
+			status: http.StatusOK,
+			want:   "[" + pet + "]",
+		},
+		"status should be selected by query": {
+			method: http.MethodGet,
+			path:   "/v1/pets?__status=503",
+			status: http.StatusServiceUnavailable,
+			want:   `{"message":"oops"}`,
+		},
+		"status should be selected by header": {
+			method: http.MethodDelete,
+			path:   "/v1/pets/1",
+			header: http.Header{StatusHeader: {"404"}},
+			status: http.StatusNotFound,
+			want:   `{"message":"oops"}`,
+		},
+		"no content should have no body": {
+			method: http.MethodDelete,
+			path:   "/v1/pets/1",
+			status: http.StatusNoContent,
+		},
+		"undeclared status should be rejected": {
+			method: http.MethodDelete,
+			path:   "/v1/pets/1?__status=500",
+			status: http.StatusBadRequest,
+			want:   `{"errors":["status code 500 is not declared by DELETE /pets/{petId}"]}`,
+		},
+		"invalid status should be rejected": {
+			method: http.MethodGet,
+			path:   "/v1/pets?__status=ok",
+			status: http.StatusBadRequest,
+			want:   `{"errors":["invalid status code: 'ok'"]}`,
+		},
+		"unknown operations should not be found": {
+			method: http.MethodPut,
+			path:   "/v1/pets",
+			status: http.StatusNotFound,
+			want:   `{"errors":["no operation matches PUT /v1/pets"]}`,
+		},
+		"response headers should use their default": {
+			method:     http.MethodPost,
+			path:       "/v1/pets",
+			status:     http.StatusCreated,
+			want:       pet,
+			wantHeader: http.Header{"X-Rate-Limit": {"100"}},
+		},
+		"invalid requests should be rejected when validating": {
+			opts:   Options{ValidateRequests: true},
+			method: http.MethodPost,
+			path:   "/v1/pets",
+			body:   `{"age": "old"}`,
+			status: http.StatusBadRequest,
+			want:   `{"errors":["missing required header parameter X-Request-ID","body: missing required property 'name'","body.age: expected type [integer] but got string"]}`,
+		},
+		"invalid parameter types should be rejected when validating": {
+			opts:   Options{ValidateRequests: true},
+			method: http.MethodGet,
+			path:   "/v1/pets?limit=ten",
+			status: http.StatusBadRequest,
+			want:   `{"errors":["query parameter limit should be of type integer but got: 'ten'"]}`,
+		},
+		"valid requests should be answered when validating": {
+			opts:   Options{ValidateRequests: true, Delay: time.Millisecond},
+			method: http.MethodPost,
+			path:   "/v1/pets",
+			header: http.Header{"X-Request-Id": {"abc"}},
+			body:   `{"name": "Rex"}`,
+			status: http.StatusCreated,
+			want:   pet,
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			h, err := Handler(swagger, tt.opts)
+			if err != nil {
+				t.Fatalf("Handler() error = %v", err)
+			}
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			resp := rec.Result()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d: %s", resp.StatusCode, tt.status, body)
+			}
+			if string(body) != tt.want {
+				t.Errorf("body =\n%s\nwant\n%s", body, tt.want)
+			}
+			for k := range tt.wantHeader {
+				if got := resp.Header.Get(k); got != tt.wantHeader.Get(k) {
+					t.Errorf("header %s = %q, want %q", k, got, tt.wantHeader.Get(k))
+				}
+			}
+		})
+	}
+
+	if _, err = Handler(nil, Options{}); err == nil {
+		t.Error("Handler() of a nil swagger should fail")
+	}
+	if _, err = Handler(swagger, Options{Delay: -time.Second}); err == nil {
+		t.Error("Handler() with a negative delay should fail")
+	}
+}
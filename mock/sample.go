@@ -0,0 +1,147 @@
+package mock
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// sample returns a value for the schema preferring its example, then its default, then its first enum value, and
+// otherwise one generated from its type and format. The definitions being sampled are tracked by seen so that
+// recursive definitions end with null.
+func sample(swagger *spec.Swagger, s *spec.Schema, seen map[string]bool) any {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != nil {
+		name, ok := s.Ref.DefinitionName()
+		def, exists := swagger.Definitions[name]
+		if !ok || !exists || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return sample(swagger, &def, seen)
+	}
+	for _, literal := range []any{s.Example, s.Default} {
+		if literal == nil {
+			continue
+		}
+		var v any
+		if err := spec.DecodeValue(literal, &v); err == nil {
+			return v
+		}
+	}
+	if len(s.Enum) > 0 {
+		var v any
+		if err := spec.DecodeValue(s.Enum[0], &v); err == nil {
+			return v
+		}
+	}
+
+	var typ string
+	if types := s.Type.Values(); len(types) > 0 {
+		typ = types[0]
+	} else if len(s.Properties) > 0 || len(s.AllOf) > 0 {
+		typ = "object"
+	}
+	switch typ {
+	case "object":
+		result := make(map[string]any, len(s.Properties))
+		for i := range s.AllOf {
+			if sub, isObject := sample(swagger, &s.AllOf[i], seen).(map[string]any); isObject {
+				for k, v := range sub {
+					result[k] = v
+				}
+			}
+		}
+		for name, prop := range s.Properties {
+			result[name] = sample(swagger, &prop, seen)
+		}
+		return result
+	case "array":
+		count := 1
+		if s.MinItems != nil && *s.MinItems > count {
+			count = *s.MinItems
+		}
+		result := make([]any, count)
+		if items, ok := s.Items.AsSchema(); ok {
+			item := sample(swagger, items, seen)
+			for i := range result {
+				result[i] = item
+			}
+		}
+		return result
+	case "string":
+		return sampleString(s)
+	case "integer":
+		if s.Minimum != nil {
+			return int64(math.Ceil(*s.Minimum))
+		}
+		if s.Maximum != nil && *s.Maximum < 0 {
+			return int64(math.Floor(*s.Maximum))
+		}
+		return 0
+	case "number":
+		if s.Minimum != nil {
+			return *s.Minimum
+		}
+		if s.Maximum != nil && *s.Maximum < 0 {
+			return *s.Maximum
+		}
+		return 0
+	case "boolean":
+		return true
+	}
+	return nil
+}
+
+// sampleString returns a string of the format of the schema that is at least its min length
+func sampleString(s *spec.Schema) string {
+	var result string
+	switch s.Format {
+	case "date-time":
+		result = "2006-01-02T15:04:05Z"
+	case "date":
+		result = "2006-01-02"
+	case "uuid":
+		result = "00000000-0000-0000-0000-000000000000"
+	case "email":
+		result = "user@example.com"
+	case "uri", "url":
+		result = "https://example.com"
+	case "hostname":
+		result = "example.com"
+	case "ipv4":
+		result = "192.0.2.1"
+	case "ipv6":
+		result = "2001:db8::1"
+	case "byte":
+		result = "c3RyaW5n"
+	default:
+		result = "string"
+	}
+	if s.MinLength != nil && len(result) < *s.MinLength {
+		result += strings.Repeat("x", *s.MinLength-len(result))
+	}
+	return result
+}
+
+// literalString returns a default or example value of the spec as it would be sent within a header
+func literalString(value any) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	if s, ok := spec.StringValue(value); ok {
+		return s, true
+	}
+	if f, ok := spec.NumberValue(value); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64), true
+	}
+	if b, ok := spec.BoolValue(value); ok {
+		return strconv.FormatBool(b), true
+	}
+	return "", false
+}
@@ -0,0 +1,2 @@
+// Package owners provides the resolution of which teams own the operations of parsed swagger specifications
+package owners
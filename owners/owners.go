@@ -0,0 +1,146 @@
+package owners
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/erraggy/goats/lint"
+	"github.com/erraggy/goats/spec"
+)
+
+// Options configures how owners are resolved
+type Options struct {
+	// Extensions are checked in order on the operation, then its path item, then the spec, where the first found is
+	// used and its value is either a string or an array of strings
+	Extensions []string
+	// Mapping maps path prefixes to owners, used when no extension is found where the longest matching prefix wins and
+	// a prefix only matches whole path segments
+	Mapping map[string][]string
+}
+
+// DefaultOptions returns the Options checking the x-owner then x-team extensions without any mapping
+func DefaultOptions() Options {
+	return Options{
+		Extensions: []string{"x-owner", "x-team"},
+	}
+}
+
+// Resolve returns the owners of every operation within the spec, where operations without any owner are omitted
+func Resolve(swagger *spec.Swagger, opts Options) map[spec.OperationKey][]string {
+	results := make(map[spec.OperationKey][]string)
+	for _, op := range swagger.Operations() {
+		if owners := resolve(swagger, op, opts); len(owners) > 0 {
+			results[op.Key] = owners
+		}
+	}
+	return results
+}
+
+func resolve(swagger *spec.Swagger, op *spec.Operation, opts Options) []string {
+	sources := []spec.Extensions{op.Extensions}
+	if pi := swagger.Paths.Items[op.Key.Path]; pi != nil {
+		sources = append(sources, pi.Extensions)
+	}
+	sources = append(sources, swagger.Extensions)
+	for _, exts := range sources {
+		for _, name := range opts.Extensions {
+			if owners := extensionOwners(exts, name); len(owners) > 0 {
+				return owners
+			}
+		}
+	}
+	var longest string
+	for prefix := range opts.Mapping {
+		if hasPathPrefix(op.Key.Path, prefix) && len(prefix) > len(longest) {
+			longest = prefix
+		}
+	}
+	if longest == "" {
+		return nil
+	}
+	return opts.Mapping[longest]
+}
+
+// hasPathPrefix returns true if the path is the prefix or is within it, where the prefix must end at a path segment
+// so that /pet does not match /pets
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+// extensionOwners returns the owners declared by the extension as either a string or an array of strings
+func extensionOwners(exts spec.Extensions, name string) []string {
+	if s, ok := exts.GetString(name); ok {
+		if s = strings.TrimSpace(s); s != "" {
+			return []string{s}
+		}
+		return nil
+	}
+	v, ok := exts.Get(name)
+	if !ok {
+		return nil
+	}
+	vals, _ := v.([]any)
+	var results []string
+	for _, val := range vals {
+		if s, isString := val.(string); isString && strings.TrimSpace(s) != "" {
+			results = append(results, strings.TrimSpace(s))
+		}
+	}
+	return results
+}
+
+// GenerateCodeowners returns a CODEOWNERS style file with a line per path listing the owners of all of its
+// operations, where paths without any owned operations are omitted
+func GenerateCodeowners(swagger *spec.Swagger, opts Options) []byte {
+	byPath := make(map[string]map[string]struct{})
+	for key, owners := range Resolve(swagger, opts) {
+		set := byPath[key.Path]
+		if set == nil {
+			set = make(map[string]struct{})
+			byPath[key.Path] = set
+		}
+		for _, owner := range owners {
+			set[owner] = struct{}{}
+		}
+	}
+	paths := make([]string, 0, len(byPath))
+	for path := range byPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b bytes.Buffer
+	b.WriteString("# Code owners generated by goats. DO NOT EDIT.\n")
+	for _, path := range paths {
+		owners := make([]string, 0, len(byPath[path]))
+		for owner := range byPath[path] {
+			owners = append(owners, owner)
+		}
+		sort.Strings(owners)
+		fmt.Fprintf(&b, "%s %s\n", path, strings.Join(owners, " "))
+	}
+	return b.Bytes()
+}
+
+// Rule returns a lint rule reporting every operation without an owner
+func Rule(opts Options) lint.Rule {
+	return lint.Rule{
+		ID:          "operation-owner",
+		Description: "every operation should have an owner",
+		Severity:    lint.SeverityWarning,
+		Check: func(swagger *spec.Swagger) []lint.Finding {
+			var results []lint.Finding
+			for _, op := range swagger.Operations() {
+				if len(resolve(swagger, op, opts)) == 0 {
+					results = append(results, lint.Finding{
+						Location: op.Key.Location(),
+						Message:  fmt.Sprintf("operation has no owner declared by %s or mapped by path", strings.Join(opts.Extensions, " or ")),
+					})
+				}
+			}
+			return results
+		},
+	}
+}
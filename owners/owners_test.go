@@ -0,0 +1,78 @@
+package owners
+
+import (
+	"testing"
+
+	"github.com/erraggy/goats/lint"
+	"github.com/erraggy/goats/spec"
+)
+
+func TestGenerateCodeowners(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"x-team": "@pets",
+				"get": {"responses": {"200": {"description": "ok"}}},
+				"post": {"x-owner": ["@adoptions", "@pets"], "responses": {"201": {"description": "ok"}}}
+			},
+			"/billing/invoices": {
+				"get": {"responses": {"200": {"description": "ok"}}}
+			},
+			"/health": {
+				"get": {"responses": {"200": {"description": "ok"}}}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	opts := DefaultOptions()
+	opts.Mapping = map[string][]string{
+		"/billing":   {"@billing"},
+		"/billing/x": {"@other"},
+	}
+	expected := "# Code owners generated by goats. DO NOT EDIT.\n" +
+		"/billing/invoices @billing\n" +
+		"/pets @adoptions @pets\n"
+	if got := string(GenerateCodeowners(swagger, opts)); got != expected {
+		t.Errorf("GenerateCodeowners() =\n%s\nwant\n%s", got, expected)
+	}
+
+	findings := lint.Lint(swagger, Rule(opts))
+	if len(findings) != 1 || findings[0].Location != ".paths./health.get" {
+		t.Errorf("Lint() = %v, want only the /health operation", findings)
+	}
+}
+
+func TestGenerateCodeowners_mappingPrefixes(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pet": {"get": {"responses": {"200": {"description": "ok"}}}},
+			"/pet/{id}": {"get": {"responses": {"200": {"description": "ok"}}}},
+			"/pets": {"get": {"responses": {"200": {"description": "ok"}}}},
+			"/petstore/orders": {"get": {"responses": {"200": {"description": "ok"}}}},
+			"/store/orders": {"get": {"responses": {"200": {"description": "ok"}}}}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	opts := DefaultOptions()
+	opts.Mapping = map[string][]string{
+		"/pet":    {"@pet"},
+		"/store/": {"@store"},
+	}
+	expected := "# Code owners generated by goats. DO NOT EDIT.\n" +
+		"/pet @pet\n" +
+		"/pet/{id} @pet\n" +
+		"/store/orders @store\n"
+	if got := string(GenerateCodeowners(swagger, opts)); got != expected {
+		t.Errorf("GenerateCodeowners() =\n%s\nwant\n%s", got, expected)
+	}
+}
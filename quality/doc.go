@@ -0,0 +1,2 @@
+// Package quality provides the scoring of the overall quality of parsed swagger specifications
+package quality
@@ -0,0 +1,142 @@
+package quality
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/erraggy/goats/lint"
+	"github.com/erraggy/goats/spec"
+)
+
+// The categories of a Score
+const (
+	CategoryLint          = "lint"
+	CategoryDocumentation = "documentation"
+	CategoryExamples      = "examples"
+	CategoryResponses     = "responses"
+)
+
+// severityPenalties are the penalties of each lint finding by its severity
+var severityPenalties = map[lint.Severity]float64{
+	lint.SeverityInfo:    1,
+	lint.SeverityWarning: 3,
+	lint.SeverityError:   10,
+}
+
+// maxPenaltyPerOperation is the lint penalty averaged over the operations at which the lint category scores zero
+const maxPenaltyPerOperation = 10
+
+// Category is the score of one aspect of a spec
+type Category struct {
+	Name string
+	// Score is from 0 to 100
+	Score float64
+	// Weight is the share of the overall score given to this category
+	Weight float64
+}
+
+// Score is the quality of a spec from 0 to 100 along with its breakdown by category
+type Score struct {
+	Score      float64
+	Categories []Category
+	// Findings counts the lint findings by their severity
+	Findings map[lint.Severity]int
+}
+
+// Compute returns the quality score of the swagger spec from the findings of the lint rules, or the lint.DefaultRules
+// if none are specified, weighted by their severity along with how completely its operations are documented, have
+// examples and declare both successful and error responses
+func Compute(swagger *spec.Swagger, rules ...lint.Rule) *Score {
+	result := &Score{
+		Findings: make(map[lint.Severity]int),
+	}
+	ops := swagger.Operations()
+	var penalty float64
+	for _, f := range lint.Lint(swagger, rules...) {
+		result.Findings[f.Severity]++
+		penalty += severityPenalties[f.Severity]
+	}
+	lintScore := 100.0
+	if len(ops) > 0 {
+		lintScore = 100 * (1 - penalty/float64(len(ops)*maxPenaltyPerOperation))
+	} else if penalty > 0 {
+		lintScore = 100 * (1 - penalty/maxPenaltyPerOperation)
+	}
+
+	var documented, exemplified, responded, checks int
+	for _, oc := range lint.CheckCompleteness(swagger).Operations {
+		missing := make(map[string]bool, len(oc.Missing))
+		for _, m := range oc.Missing {
+			missing[m] = true
+		}
+		for _, field := range []string{"summary", "description", "tags"} {
+			if !missing[field] {
+				documented++
+			}
+		}
+		if !missing["examples"] {
+			exemplified++
+		}
+		if !missing["error responses"] {
+			responded++
+		}
+		checks++
+	}
+	for _, op := range ops {
+		for code := range op.Responses.ByStatusCode {
+			if code >= 200 && code < 300 {
+				responded++
+				break
+			}
+		}
+	}
+
+	result.Categories = []Category{
+		{Name: CategoryLint, Score: clamp(lintScore), Weight: 0.4},
+		{Name: CategoryDocumentation, Score: percent(documented, checks*3), Weight: 0.25},
+		{Name: CategoryExamples, Score: percent(exemplified, checks), Weight: 0.15},
+		{Name: CategoryResponses, Score: percent(responded, checks*2), Weight: 0.2},
+	}
+	for _, c := range result.Categories {
+		result.Score += c.Score * c.Weight
+	}
+	return result
+}
+
+// Category returns the category with the name and if it exists
+func (s *Score) Category(name string) (Category, bool) {
+	for _, c := range s.Categories {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// String renders the overall score followed by the score of each category
+func (s *Score) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Quality: %.0f/100\n", s.Score)
+	for _, c := range s.Categories {
+		fmt.Fprintf(&b, "  %-14s %3.0f (weight %.0f%%)\n", c.Name, c.Score, c.Weight*100)
+	}
+	return b.String()
+}
+
+// percent returns the percentage of the count within the total, or 100 when there is nothing to count
+func percent(count, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(count) * 100 / float64(total)
+}
+
+func clamp(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
@@ -0,0 +1,75 @@
+package quality
+
+import (
+	"math"
+	"testing"
+
+	"github.com/erraggy/goats/lint"
+	"github.com/erraggy/goats/spec"
+)
+
+func TestCompute(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"summary": "List pets",
+					"description": "Lists all pets",
+					"tags": ["pets"],
+					"responses": {
+						"200": {"description": "ok", "schema": {"type": "array", "example": [{"name": "Tom"}]}},
+						"404": {"description": "not found"}
+					}
+				},
+				"post": {
+					"tags": ["pets"],
+					"responses": {"201": {"description": "created", "schema": {"type": "object"}}}
+				}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	rule := lint.Rule{
+		ID:       "test",
+		Severity: lint.SeverityWarning,
+		Check: func(*spec.Swagger) []lint.Finding {
+			return []lint.Finding{{Location: ".paths./pets.post", Message: "test"}}
+		},
+	}
+	score := Compute(swagger, rule)
+	expected := map[string]float64{
+		CategoryLint:          85,
+		CategoryDocumentation: 400.0 / 6,
+		CategoryExamples:      50,
+		CategoryResponses:     75,
+	}
+	for name, want := range expected {
+		c, ok := score.Category(name)
+		if !ok {
+			t.Fatalf("missing category %s", name)
+		}
+		if math.Abs(c.Score-want) > 0.001 {
+			t.Errorf("category %s = %f, want %f", name, c.Score, want)
+		}
+	}
+	if want := 85*0.4 + 400.0/6*0.25 + 50*0.15 + 75*0.2; math.Abs(score.Score-want) > 0.001 {
+		t.Errorf("Score = %f, want %f", score.Score, want)
+	}
+	if score.Findings[lint.SeverityWarning] != 1 {
+		t.Errorf("Findings = %v, want 1 warning", score.Findings)
+	}
+	expectedString := `Quality: 73/100
+  lint            85 (weight 40%)
+  documentation   67 (weight 25%)
+  examples        50 (weight 15%)
+  responses       75 (weight 20%)
+`
+	if got := score.String(); got != expectedString {
+		t.Errorf("String() =\n%s\nwant\n%s", got, expectedString)
+	}
+}
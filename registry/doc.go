@@ -0,0 +1,2 @@
+// Package registry provides the tracking of named swagger specifications that are re-loaded as their sources change
+package registry
@@ -0,0 +1,128 @@
+package registry
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// Change is emitted when a spec is loaded with changes or fails to load
+type Change struct {
+	Name string
+	// Previous is the spec before this change, which is nil for the first load
+	Previous *spec.Swagger
+	// Current is the newly loaded spec, which is nil when Err is not
+	Current *spec.Swagger
+	Err     error
+}
+
+type entry struct {
+	source   Source
+	previous *spec.Swagger
+	current  *spec.Swagger
+}
+
+// Registry tracks named specs from their sources, keeping both the current and previous versions of each, and is
+// safe for concurrent use
+type Registry struct {
+	mu       sync.RWMutex
+	entries  map[string]*entry
+	onChange func(Change)
+}
+
+// New returns a new empty Registry calling onChange, if not nil, for every Change found while refreshing
+func New(onChange func(Change)) *Registry {
+	return &Registry{
+		entries:  make(map[string]*entry),
+		onChange: onChange,
+	}
+}
+
+// Add tracks the source by the name, replacing any source already using it
+func (r *Registry) Add(name string, source Source) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[name] = &entry{source: source}
+}
+
+// Remove stops tracking the name
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, name)
+}
+
+// Names returns the sorted names being tracked
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	results := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		results = append(results, name)
+	}
+	sort.Strings(results)
+	return results
+}
+
+// Get returns the current and previous versions of the named spec and if it is tracked
+func (r *Registry) Get(name string) (current, previous *spec.Swagger, tracked bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, tracked := r.entries[name]
+	if !tracked {
+		return nil, nil, false
+	}
+	return e.current, e.previous, true
+}
+
+// Refresh loads every source once in order of their names, returning the changes which are also passed to onChange
+func (r *Registry) Refresh(ctx context.Context) []Change {
+	var results []Change
+	for _, name := range r.Names() {
+		if ctx.Err() != nil {
+			break
+		}
+		r.mu.RLock()
+		e := r.entries[name]
+		r.mu.RUnlock()
+		if e == nil {
+			continue
+		}
+		swagger, changed, err := e.source.Load(ctx)
+		if err == nil && !changed {
+			continue
+		}
+		c := Change{Name: name, Err: err}
+		r.mu.Lock()
+		if r.entries[name] == e {
+			c.Previous = e.current
+			if err == nil {
+				c.Current = swagger
+				e.previous, e.current = e.current, swagger
+			}
+		}
+		r.mu.Unlock()
+		results = append(results, c)
+		if r.onChange != nil {
+			r.onChange(c)
+		}
+	}
+	return results
+}
+
+// Watch refreshes all sources immediately and then at every interval until the context is done, returning its error
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Refresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "swagger.json")
+	write := func(host string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(`{"swagger": "2.0", "host": "`+host+`"}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	write("v1.example.com", start)
+
+	var changes []Change
+	r := New(func(c Change) {
+		changes = append(changes, c)
+	})
+	r.Add("pets", NewFileSource(path))
+	ctx := context.Background()
+
+	r.Refresh(ctx)
+	if len(changes) != 1 || changes[0].Previous != nil || changes[0].Current.Host != "v1.example.com" {
+		t.Fatalf("first refresh should load the spec: %+v", changes)
+	}
+	if got := r.Refresh(ctx); len(got) != 0 {
+		t.Errorf("an unchanged file should not be reloaded: %+v", got)
+	}
+
+	write("v2.example.com", start.Add(time.Minute))
+	r.Refresh(ctx)
+	if len(changes) != 2 || changes[1].Previous.Host != "v1.example.com" || changes[1].Current.Host != "v2.example.com" {
+		t.Fatalf("second refresh should reload the spec: %+v", changes)
+	}
+	current, previous, tracked := r.Get("pets")
+	if !tracked || current != changes[1].Current || previous != changes[1].Previous {
+		t.Errorf("Get() = %v, %v, %t", current, previous, tracked)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Refresh(ctx); len(got) != 1 || got[0].Err == nil {
+		t.Errorf("a missing file should be reported as an error: %+v", got)
+	}
+	if current, _, _ = r.Get("pets"); current != changes[1].Current {
+		t.Error("a failed load should keep the current spec")
+	}
+}
@@ -0,0 +1,85 @@
+package registry
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// Source loads a swagger spec
+type Source interface {
+	// Load returns the spec and true if it has changed since the previous load, where the first load has always changed
+	Load(ctx context.Context) (swagger *spec.Swagger, changed bool, err error)
+}
+
+type fileSource struct {
+	path    string
+	opts    []spec.ParserOption
+	mu      sync.Mutex
+	loaded  bool
+	modTime time.Time
+	size    int64
+	swagger *spec.Swagger
+}
+
+// NewFileSource returns a Source re-parsing the file at the path whenever its modification time or size changes
+func NewFileSource(path string, opts ...spec.ParserOption) Source {
+	return &fileSource{
+		path: path,
+		opts: opts,
+	}
+}
+
+func (s *fileSource) Load(ctx context.Context) (*spec.Swagger, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, false, err
+	}
+	if s.loaded && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.swagger, false, nil
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	swagger, err := spec.ParseFile(s.path, s.opts...)
+	if err != nil {
+		return nil, false, err
+	}
+	s.loaded, s.modTime, s.size, s.swagger = true, info.ModTime(), info.Size(), swagger
+	return swagger, true, nil
+}
+
+type urlSource struct {
+	url     string
+	opts    spec.LoadOptions
+	mu      sync.Mutex
+	swagger *spec.Swagger
+}
+
+// NewURLSource returns a Source fetching the URL using conditional requests, so it is only re-parsed when changed
+func NewURLSource(url string, opts spec.LoadOptions) Source {
+	if opts.Cache == nil {
+		opts.Cache = spec.NewURLCache()
+	}
+	return &urlSource{
+		url:  url,
+		opts: opts,
+	}
+}
+
+func (s *urlSource) Load(ctx context.Context) (*spec.Swagger, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	swagger, err := spec.LoadURL(ctx, s.url, s.opts)
+	if err != nil {
+		return nil, false, err
+	}
+	changed := swagger != s.swagger
+	s.swagger = swagger
+	return swagger, changed, nil
+}
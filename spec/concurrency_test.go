@@ -0,0 +1,53 @@
+package spec
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestSwagger_concurrentReads exercises the read methods of a parsed Swagger from many goroutines at once, which is
+// only meaningful when run with -race
+func TestSwagger_concurrentReads(t *testing.T) {
+	swagger, err := NewParser(generateSpec(20), WithSourceMap(), WithKeyOrder()).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		g := g
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				path := fmt.Sprintf("/resources%d/abc", (g+i)%20)
+				if _, _, found := swagger.FindOperation("GET", path); !found {
+					t.Errorf("FindOperation(GET %s) not found", path)
+				}
+				if g == 0 {
+					// rebuilding the lazily built routes must not race with lookups
+					swagger.InvalidateRoutes()
+				}
+				if len(swagger.OperationMap()) != 40 || len(swagger.Operations()) != 40 {
+					t.Error("unexpected count of operations")
+				}
+				key := OperationKey{Path: fmt.Sprintf("/resources%d/{id}", i), Method: "PUT"}
+				if _, found := swagger.DescribeOperation(key); !found {
+					t.Errorf("DescribeOperation(%v) not found", key)
+				}
+				if _, found := swagger.SourceRange(key.Location()); !found {
+					t.Errorf("SourceRange(%s) not found", key.Location())
+				}
+				if refs := swagger.OperationsReferencing(fmt.Sprintf("Resource%d", i)); len(refs) != 2 {
+					t.Errorf("OperationsReferencing() = %v", refs)
+				}
+				_ = swagger.ReachableDefinitions()
+				_ = swagger.Literals()
+				if _, err := swagger.MarshalJSON(); err != nil {
+					t.Errorf("MarshalJSON() unexpected error: %s", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,342 @@
+package spec
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefinitionsInDependencyOrder returns the names of all definitions grouped and ordered such that every definition
+// comes after the definitions it references. Definitions that reference each other in a cycle are returned together
+// as one group, otherwise each group contains a single name. Ties are broken by name so the order is stable.
+func (s *Swagger) DefinitionsInDependencyOrder() [][]string {
+	if s == nil || len(s.Definitions) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(s.Definitions))
+	for name := range s.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	deps := make(map[string][]string, len(names))
+	for _, name := range names {
+		def := s.Definitions[name]
+		var refs []string
+		for _, ref := range def.ReferencedDefinitions().Values() {
+			if _, defined := s.Definitions[ref]; defined {
+				refs = append(refs, ref)
+			}
+		}
+		sort.Strings(refs)
+		deps[name] = refs
+	}
+	groups := stronglyConnected(names, deps)
+
+	// now order the groups by their dependencies using the first name of each group to break ties
+	var (
+		groupOf    = make(map[string]int, len(names))
+		dependents = make([][]int, len(groups))
+		pending    = make([]int, len(groups))
+	)
+	for i, group := range groups {
+		for _, name := range group {
+			groupOf[name] = i
+		}
+	}
+	for i, group := range groups {
+		seen := make(map[int]struct{})
+		for _, name := range group {
+			for _, dep := range deps[name] {
+				if j := groupOf[dep]; j != i {
+					if _, exists := seen[j]; !exists {
+						seen[j] = struct{}{}
+						dependents[j] = append(dependents[j], i)
+						pending[i]++
+					}
+				}
+			}
+		}
+	}
+	var ready []int
+	for i := range groups {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	results := make([][]string, 0, len(groups))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(a, b int) bool {
+			return groups[ready[a]][0] < groups[ready[b]][0]
+		})
+		next := ready[0]
+		ready = ready[1:]
+		results = append(results, groups[next])
+		for _, dependent := range dependents[next] {
+			if pending[dependent]--; pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	return results
+}
+
+// stronglyConnected returns the strongly connected components of the graph using Tarjan's algorithm with the names
+// within each component sorted
+func stronglyConnected(names []string, edges map[string][]string) [][]string {
+	var (
+		index   = make(map[string]int, len(names))
+		lowLink = make(map[string]int, len(names))
+		onStack = make(map[string]bool, len(names))
+		stack   []string
+		results [][]string
+		visit   func(name string)
+	)
+	visit = func(name string) {
+		index[name] = len(index)
+		lowLink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+		for _, next := range edges[name] {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				if lowLink[next] < lowLink[name] {
+					lowLink[name] = lowLink[next]
+				}
+			} else if onStack[next] && index[next] < lowLink[name] {
+				lowLink[name] = index[next]
+			}
+		}
+		if lowLink[name] != index[name] {
+			return
+		}
+		var group []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			group = append(group, last)
+			if last == name {
+				break
+			}
+		}
+		sort.Strings(group)
+		results = append(results, group)
+	}
+	for _, name := range names {
+		if _, visited := index[name]; !visited {
+			visit(name)
+		}
+	}
+	return results
+}
+
+// discriminatorValueExtension is the extension a subtype may declare to override its discriminator value, which
+// otherwise defaults to the name of its definition
+const discriminatorValueExtension = "x-discriminator-value"
+
+// Subtype defines a definition that inherits from a base definition declaring a discriminator
+type Subtype struct {
+	Name   string
+	Schema *Schema
+}
+
+// Discriminator defines the polymorphic subtypes of a base definition mapped by their discriminator values
+type Discriminator struct {
+	// Base is the name of the definition declaring the discriminator
+	Base string
+	// PropertyName is the name of the property holding the discriminator value
+	PropertyName string
+	// Subtypes maps each discriminator value to its definition, including the base definition itself
+	Subtypes map[string]Subtype
+}
+
+// Lookup returns the Subtype for the specified discriminator value and if it was found
+func (d *Discriminator) Lookup(value string) (Subtype, bool) {
+	if d == nil {
+		return Subtype{}, false
+	}
+	st, ok := d.Subtypes[value]
+	return st, ok
+}
+
+// Values returns the sorted discriminator values
+func (d *Discriminator) Values() []string {
+	if d == nil {
+		return nil
+	}
+	results := make([]string, 0, len(d.Subtypes))
+	for value := range d.Subtypes {
+		results = append(results, value)
+	}
+	sort.Strings(results)
+	return results
+}
+
+// Discriminator returns the Discriminator for the named base definition, or nil if it does not declare one.
+// Any definition that inherits from the base through allOf, directly or transitively, is a subtype.
+func (s *Swagger) Discriminator(base string) *Discriminator {
+	if s == nil {
+		return nil
+	}
+	baseDef, exists := s.Definitions[base]
+	if !exists || baseDef.Discriminator == "" {
+		return nil
+	}
+	names := make([]string, 0, len(s.Definitions))
+	for name := range s.Definitions {
+		names = append(names, name)
+	}
+	// sorting ensures the first definition wins should any declare the same discriminator value
+	sort.Strings(names)
+	result := &Discriminator{
+		Base:         base,
+		PropertyName: baseDef.Discriminator,
+		Subtypes:     make(map[string]Subtype),
+	}
+	inherits := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name != base && !s.inheritsFrom(name, base, inherits, make(map[string]bool)) {
+			continue
+		}
+		def := s.Definitions[name]
+		value := name
+		if v := def.Extensions[discriminatorValueExtension]; v != nil {
+			if b, err := v.StringBytes(); err == nil && len(b) > 0 {
+				value = string(b)
+			}
+		}
+		if _, exists := result.Subtypes[value]; !exists {
+			result.Subtypes[value] = Subtype{
+				Name:   name,
+				Schema: &def,
+			}
+		}
+	}
+	return result
+}
+
+// Discriminators returns the Discriminator of every definition declaring one mapped by the base definition name
+func (s *Swagger) Discriminators() map[string]*Discriminator {
+	if s == nil {
+		return nil
+	}
+	results := make(map[string]*Discriminator)
+	for name, def := range s.Definitions {
+		if def.Discriminator != "" {
+			results[name] = s.Discriminator(name)
+		}
+	}
+	return results
+}
+
+// ReachableDefinitions returns the sorted names of the definitions referenced from the paths, global parameters or
+// global responses of this spec, directly or through any other definition they reference
+func (s *Swagger) ReachableDefinitions() []string {
+	if s == nil {
+		return nil
+	}
+	var pending []string
+	s.walkSchemas(func(loc string, schema *Schema) {
+		if !strings.HasPrefix(loc, ".definitions.") {
+			if name, ok := schema.Ref.DefinitionName(); ok {
+				pending = append(pending, name)
+			}
+		}
+	})
+	return s.definitionClosure(pending)
+}
+
+// TransitiveDefinitions returns the sorted names of the definitions referenced from anywhere within the schema,
+// directly or through any other definition they reference, where recursive definitions are included only once
+func (s *Swagger) TransitiveDefinitions(schema *Schema) []string {
+	if s == nil || schema == nil {
+		return nil
+	}
+	return s.definitionClosure(schema.ReferencedDefinitions().Values())
+}
+
+// IsRecursive returns true if the named definition references itself, directly or through any other definition
+func (s *Swagger) IsRecursive(name string) bool {
+	if s == nil {
+		return false
+	}
+	def, defined := s.Definitions[name]
+	if !defined {
+		return false
+	}
+	return containsString(s.definitionClosure(def.ReferencedDefinitions().Values()), name)
+}
+
+// definitionClosure returns the sorted names of the pending definitions and those they reference transitively,
+// ignoring undefined names and visiting each definition only once so that cycles end
+func (s *Swagger) definitionClosure(pending []string) []string {
+	reached := make(map[string]struct{})
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if _, seen := reached[name]; seen {
+			continue
+		}
+		def, defined := s.Definitions[name]
+		if !defined {
+			continue
+		}
+		reached[name] = struct{}{}
+		pending = append(pending, def.ReferencedDefinitions().Values()...)
+	}
+	return sortedKeys(reached)
+}
+
+// inheritsFrom returns true if the named definition includes the base definition within its allOf, directly or
+// through any definition it references there
+func (s *Swagger) inheritsFrom(name string, base string, memo map[string]bool, visiting map[string]bool) bool {
+	if result, known := memo[name]; known {
+		return result
+	}
+	if visiting[name] {
+		// guard against cyclic allOf references
+		return false
+	}
+	visiting[name] = true
+	var result bool
+	for _, sub := range s.Definitions[name].AllOf {
+		parent, ok := sub.Ref.DefinitionName()
+		if !ok {
+			continue
+		}
+		if parent == base || s.inheritsFrom(parent, base, memo, visiting) {
+			result = true
+			break
+		}
+	}
+	memo[name] = result
+	return result
+}
+
+// DefinitionRefs returns the locations of the schemas referencing the named definition in a deterministic order
+func (s *Swagger) DefinitionRefs(name string) []string {
+	var results []string
+	s.walkSchemas(func(loc string, schema *Schema) {
+		if ref, ok := schema.Ref.DefinitionName(); ok && ref == name {
+			results = append(results, loc)
+		}
+	})
+	return results
+}
+
+// ReplaceDefinitionRefs rewrites every reference to a definition named by a key of the replacements to instead refer
+// to the definition named by its value, returning the count of references rewritten. The definitions themselves are
+// neither added nor removed.
+func (s *Swagger) ReplaceDefinitionRefs(replacements map[string]string) int {
+	// references are shared by every copy of a schema so are rewritten in place, though only once as cloned
+	// operations may share them too
+	rewritten := make(map[*Reference]bool)
+	s.walkSchemas(func(loc string, schema *Schema) {
+		if name, ok := schema.Ref.DefinitionName(); ok && !rewritten[schema.Ref] {
+			if replacement, replaced := replacements[name]; replaced {
+				schema.Ref.uri = "#/definitions/" + replacement
+				rewritten[schema.Ref] = true
+			}
+		}
+	})
+	return len(rewritten)
+}
@@ -0,0 +1,161 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/valyala/fastjson"
+)
+
+func TestSwagger_DefinitionsInDependencyOrder(t *testing.T) {
+	type testCase struct {
+		raw      string
+		expected [][]string
+	}
+	tests := map[string]testCase{
+		"no definitions should return nil": {
+			raw: `{"swagger": "2.0"}`,
+		},
+		"independent definitions should be ordered by name": {
+			raw: `{"definitions": {
+				"Zebra": {"type": "string"},
+				"Apple": {"type": "string"}
+			}}`,
+			expected: [][]string{{"Apple"}, {"Zebra"}},
+		},
+		"dependencies should come first": {
+			raw: `{"definitions": {
+				"Apple": {"properties": {"owner": {"$ref": "#/definitions/Owner"}}},
+				"Owner": {"properties": {"address": {"$ref": "#/definitions/Address"}}},
+				"Address": {"type": "object"}
+			}}`,
+			expected: [][]string{{"Address"}, {"Owner"}, {"Apple"}},
+		},
+		"cycles should be grouped together": {
+			raw: `{"definitions": {
+				"Node": {"properties": {"parent": {"$ref": "#/definitions/Tree"}}},
+				"Tree": {"properties": {"root": {"$ref": "#/definitions/Node"}, "meta": {"$ref": "#/definitions/Meta"}}},
+				"Self": {"properties": {"next": {"$ref": "#/definitions/Self"}}},
+				"Meta": {"type": "object"},
+				"Forest": {"type": "array", "items": {"$ref": "#/definitions/Tree"}}
+			}}`,
+			expected: [][]string{{"Meta"}, {"Node", "Tree"}, {"Forest"}, {"Self"}},
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			swagger, err := NewParser([]byte(tt.raw)).Parse()
+			if err != nil {
+				t.Fatalf("failed to parse: %s", err)
+			}
+			if got := swagger.DefinitionsInDependencyOrder(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("DefinitionsInDependencyOrder() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSwagger_Discriminator(t *testing.T) {
+	raw := `{"definitions": {
+		"Pet": {"discriminator": "petType", "required": ["petType"], "properties": {"petType": {"type": "string"}}},
+		"Cat": {"allOf": [{"$ref": "#/definitions/Pet"}, {"properties": {"meows": {"type": "boolean"}}}]},
+		"Lion": {"allOf": [{"$ref": "#/definitions/Cat"}]},
+		"Dog": {"x-discriminator-value": "dog", "allOf": [{"$ref": "#/definitions/Pet"}]},
+		"Rock": {"allOf": [{"$ref": "#/definitions/Mineral"}]},
+		"Mineral": {"type": "object"}
+	}}`
+	swagger, err := NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	if d := swagger.Discriminator("Mineral"); d != nil {
+		t.Errorf("Discriminator(Mineral) = %v, want nil", d)
+	}
+	d := swagger.Discriminator("Pet")
+	if d == nil {
+		t.Fatal("Discriminator(Pet) returned nil")
+	}
+	if d.PropertyName != "petType" {
+		t.Errorf("PropertyName = %q, want %q", d.PropertyName, "petType")
+	}
+	expected := map[string]string{
+		"Cat":  "Cat",
+		"Lion": "Lion",
+		"Pet":  "Pet",
+		"dog":  "Dog",
+	}
+	if got := d.Values(); !reflect.DeepEqual(got, []string{"Cat", "Lion", "Pet", "dog"}) {
+		t.Errorf("Values() = %v", got)
+	}
+	for value, name := range expected {
+		if st, ok := d.Lookup(value); !ok || st.Name != name || st.Schema == nil {
+			t.Errorf("Lookup(%q) = %v, %t, want %s", value, st, ok, name)
+		}
+	}
+	if all := swagger.Discriminators(); len(all) != 1 || all["Pet"] == nil {
+		t.Errorf("Discriminators() = %v, want only Pet", all)
+	}
+}
+
+func TestSwagger_recursiveDefinitions(t *testing.T) {
+	swagger, err := NewParser([]byte(`{
+		"paths": {
+			"/nodes": {
+				"post": {
+					"parameters": [{"name": "node", "in": "body", "schema": {"$ref": "#/definitions/Node"}}],
+					"responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Person"}}}
+				}
+			}
+		},
+		"definitions": {
+			"Node": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "minLength": 1},
+					"children": {"type": "array", "items": {"$ref": "#/definitions/Node"}}
+				},
+				"example": {"name": "root", "children": [{"name": "a", "children": [{"name": ""}]}]}
+			},
+			"Person": {"type": "object", "properties": {"employer": {"$ref": "#/definitions/Company"}}},
+			"Company": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Person"}, "tag": {"$ref": "#/definitions/Tag"}}},
+			"Tag": {"type": "string"},
+			"Loop": {"$ref": "#/definitions/Loop"}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	recursive := make(map[string]bool)
+	for name := range swagger.Definitions {
+		recursive[name] = swagger.IsRecursive(name)
+	}
+	if expected := map[string]bool{"Node": true, "Person": true, "Company": true, "Tag": false, "Loop": true}; !reflect.DeepEqual(recursive, expected) {
+		t.Errorf("IsRecursive() = %v, want %v", recursive, expected)
+	}
+	if got, expected := swagger.ReachableDefinitions(), []string{"Company", "Node", "Person", "Tag"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("ReachableDefinitions() = %v, want %v", got, expected)
+	}
+	person := swagger.Definitions["Person"]
+	if got, expected := swagger.TransitiveDefinitions(&person), []string{"Company", "Person", "Tag"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("TransitiveDefinitions() = %v, want %v", got, expected)
+	}
+	loop := swagger.Definitions["Loop"]
+	if got, expected := swagger.TransitiveDefinitions(&loop), []string{"Loop"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("TransitiveDefinitions() = %v, want %v", got, expected)
+	}
+	if got, expected := swagger.DefinitionsInDependencyOrder(), [][]string{{"Loop"}, {"Node"}, {"Tag"}, {"Company", "Person"}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("DefinitionsInDependencyOrder() = %v, want %v", got, expected)
+	}
+	var got []string
+	for _, lit := range swagger.Literals() {
+		for _, e := range swagger.ValidateValue(lit.Schema, lit.Value) {
+			got = append(got, lit.Location+e.(*ValueError).Location)
+		}
+	}
+	if expected := []string{".definitions.Node.example.children[0].children[0].name"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("ValidateValue() failed at %v, want %v", got, expected)
+	}
+	if errs := swagger.ValidateValue(&loop, fastjson.MustParse(`{}`)); len(errs) > 0 {
+		t.Errorf("ValidateValue() of a self reference = %v, want none", errs)
+	}
+}
@@ -0,0 +1,361 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OperationDescription is the resolved description of a single operation, where everything inherited from the path
+// item or the swagger spec has been applied
+type OperationDescription struct {
+	Key         OperationKey
+	ID          string
+	Summary     string
+	Description string
+	Deprecated  bool
+	Tags        []string
+	Consumes    []string
+	Produces    []string
+	// Parameters are those of the path item overridden by those of the operation with the same name and location
+	Parameters []Parameter
+	// Security is that of the operation or else the swagger spec, where an empty slice means no security
+	Security  []SecurityRequirements
+	Responses []ResponseDescription
+}
+
+// ResponseDescription describes a single response of an operation
+type ResponseDescription struct {
+	// Status is the status code or "default"
+	Status      string
+	Description string
+	// SchemaName is the name of the definition referenced by the response schema, if any
+	SchemaName string
+	// Schema is the response schema with any reference resolved one level to its definition
+	Schema  *Schema
+	Headers map[string]*Header
+}
+
+// DescribeOperation returns the resolved description of the operation with the key and if it exists
+func (s *Swagger) DescribeOperation(key OperationKey) (*OperationDescription, bool) {
+	if s == nil {
+		return nil, false
+	}
+	key = key.Canonicalize()
+	op := s.operationMap[key]
+	if op == nil {
+		return nil, false
+	}
+	result := &OperationDescription{
+		Key:         key,
+		ID:          op.ID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Deprecated:  op.Deprecated,
+		Tags:        op.Tags,
+		Consumes:    op.Consumes,
+		Produces:    op.Produces,
+		Security:    op.EffectiveSecurity(s),
+	}
+	if len(result.Consumes) == 0 {
+		result.Consumes = s.Consumes
+	}
+	if len(result.Produces) == 0 {
+		result.Produces = s.Produces
+	}
+	result.Parameters = op.EffectiveParameters(s.Paths.Items[key.Path], s.Parameters)
+
+	codes := make([]int, 0, len(op.Responses.ByStatusCode))
+	for code := range op.Responses.ByStatusCode {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		if r := op.Responses.ByStatusCode[code]; r != nil {
+			result.Responses = append(result.Responses, s.describeResponse(strconv.Itoa(code), r))
+		}
+	}
+	if r := op.Responses.Default; r != nil {
+		result.Responses = append(result.Responses, s.describeResponse("default", r))
+	}
+	return result, true
+}
+
+func (s *Swagger) describeResponse(status string, r *Response) ResponseDescription {
+	result := ResponseDescription{
+		Status:      status,
+		Description: r.Description,
+		Schema:      r.Schema,
+		Headers:     r.Headers,
+	}
+	if name, ok := r.Schema.referencedDefinition(); ok {
+		result.SchemaName = name
+		if def, exists := s.Definitions[name]; exists {
+			result.Schema = &def
+		}
+	}
+	return result
+}
+
+// referencedDefinition returns the name of the definition this schema only references and if it does
+func (s *Schema) referencedDefinition() (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	return s.Ref.definitionKey()
+}
+
+// Text returns a plain text rendering of the description
+func (d *OperationDescription) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", d.Key.Method, d.Key.Path)
+	if d.ID != "" {
+		fmt.Fprintf(&b, " (%s)", d.ID)
+	}
+	b.WriteByte('\n')
+	if d.Deprecated {
+		b.WriteString("DEPRECATED\n")
+	}
+	writeLine(&b, "Summary", d.Summary)
+	writeLine(&b, "Description", d.Description)
+	writeLine(&b, "Tags", strings.Join(d.Tags, ", "))
+	writeLine(&b, "Consumes", strings.Join(d.Consumes, ", "))
+	writeLine(&b, "Produces", strings.Join(d.Produces, ", "))
+	writeLine(&b, "Security", describeSecurity(d.Security))
+	if len(d.Parameters) > 0 {
+		b.WriteString("Parameters:\n")
+		for _, p := range d.Parameters {
+			fmt.Fprintf(&b, "  %s (%s)\n", p.Name, describeParameter(p))
+			if p.Description != "" {
+				fmt.Fprintf(&b, "    %s\n", p.Description)
+			}
+		}
+	}
+	if len(d.Responses) > 0 {
+		b.WriteString("Responses:\n")
+		for _, r := range d.Responses {
+			fmt.Fprintf(&b, "  %s: %s\n", r.Status, r.Description)
+			if r.Schema == nil {
+				continue
+			}
+			fmt.Fprintf(&b, "    schema: %s\n", describeResponseSchema(r))
+			for _, prop := range describeProperties(r.Schema) {
+				fmt.Fprintf(&b, "      %s: %s\n", prop[0], prop[1])
+			}
+		}
+	}
+	return b.String()
+}
+
+// Markdown returns a Markdown rendering of the description
+func (d *OperationDescription) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## `%s %s`\n\n", d.Key.Method, d.Key.Path)
+	if d.Deprecated {
+		b.WriteString("**Deprecated**\n\n")
+	}
+	if d.Summary != "" {
+		fmt.Fprintf(&b, "%s\n\n", d.Summary)
+	}
+	if d.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", d.Description)
+	}
+	writeItem := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "- **%s:** %s\n", name, value)
+		}
+	}
+	writeItem("Operation ID", d.ID)
+	writeItem("Tags", strings.Join(d.Tags, ", "))
+	writeItem("Consumes", strings.Join(d.Consumes, ", "))
+	writeItem("Produces", strings.Join(d.Produces, ", "))
+	writeItem("Security", describeSecurity(d.Security))
+	if len(d.Parameters) > 0 {
+		b.WriteString("\n### Parameters\n\n| Name | In | Type | Required | Description |\n| --- | --- | --- | --- | --- |\n")
+		for _, p := range d.Parameters {
+			fmt.Fprintf(&b, "| %s | %s | %s | %t | %s |\n", p.Name, p.In, parameterType(p), p.Required, markdownCell(p.Description))
+		}
+	}
+	if len(d.Responses) > 0 {
+		b.WriteString("\n### Responses\n")
+		for _, r := range d.Responses {
+			fmt.Fprintf(&b, "\n#### %s\n\n%s\n", r.Status, r.Description)
+			if r.Schema == nil {
+				continue
+			}
+			fmt.Fprintf(&b, "\nSchema: `%s`\n", describeResponseSchema(r))
+			if props := describeProperties(r.Schema); len(props) > 0 {
+				b.WriteString("\n| Property | Type |\n| --- | --- |\n")
+				for _, prop := range props {
+					fmt.Fprintf(&b, "| %s | %s |\n", prop[0], prop[1])
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// MarshalJSON returns the JSON encoding of the description
+func (d *OperationDescription) MarshalJSON() ([]byte, error) {
+	a := arenaPool.Get()
+	defer func() {
+		a.Reset()
+		arenaPool.Put(a)
+	}()
+	val := a.NewObject()
+	val.Set("method", a.NewString(d.Key.Method))
+	val.Set("path", a.NewString(d.Key.Path))
+	setString(a, val, "operationId", d.ID)
+	setString(a, val, "summary", d.Summary)
+	setString(a, val, "description", d.Description)
+	setBool(a, val, "deprecated", d.Deprecated)
+	setStrings(a, val, "tags", d.Tags)
+	setStrings(a, val, "consumes", d.Consumes)
+	setStrings(a, val, "produces", d.Produces)
+	if d.Security != nil {
+		val.Set("security", marshalSecurity(a, d.Security))
+	}
+	if len(d.Parameters) > 0 {
+		val.Set("parameters", marshalParameters(a, d.Parameters))
+	}
+	responses := a.NewObject()
+	for _, r := range d.Responses {
+		rv := a.NewObject()
+		rv.Set("description", a.NewString(r.Description))
+		setString(a, rv, "schemaName", r.SchemaName)
+		if r.Schema != nil {
+			rv.Set("schema", r.Schema.marshal(a))
+		}
+		if len(r.Headers) > 0 {
+			headers := a.NewObject()
+			for _, name := range sortedKeys(r.Headers) {
+				if h := r.Headers[name]; h != nil {
+					headers.Set(name, h.marshal(a))
+				}
+			}
+			rv.Set("headers", headers)
+		}
+		responses.Set(r.Status, rv)
+	}
+	val.Set("responses", responses)
+	return val.MarshalTo(nil), nil
+}
+
+func writeLine(b *strings.Builder, name, value string) {
+	if value != "" {
+		fmt.Fprintf(b, "%s: %s\n", name, value)
+	}
+}
+
+// describeSecurity returns the alternative security requirements separated by " or "
+func describeSecurity(reqs []SecurityRequirements) string {
+	if reqs == nil {
+		return ""
+	}
+	if len(reqs) == 0 {
+		return "none"
+	}
+	alternatives := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		schemes := make([]string, 0, len(req))
+		for _, name := range sortedKeys(req) {
+			if scopes := req[name]; len(scopes) > 0 {
+				schemes = append(schemes, fmt.Sprintf("%s[%s]", name, strings.Join(scopes, ", ")))
+			} else {
+				schemes = append(schemes, name)
+			}
+		}
+		alternatives = append(alternatives, strings.Join(schemes, " and "))
+	}
+	return strings.Join(alternatives, " or ")
+}
+
+func describeParameter(p Parameter) string {
+	parts := []string{string(p.In), parameterType(p)}
+	if p.Required {
+		parts = append(parts, "required")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// TypeName returns a short name for the type of the parameter, such as []string, using the names of referenced
+// definitions
+func (p *Parameter) TypeName() string {
+	if p == nil {
+		return ""
+	}
+	return parameterType(*p)
+}
+
+func parameterType(p Parameter) string {
+	if p.Schema != nil {
+		return schemaType(p.Schema)
+	}
+	if p.Type == "array" && p.Items != nil {
+		return "[]" + p.Items.Type
+	}
+	return p.Type
+}
+
+func describeResponseSchema(r ResponseDescription) string {
+	if r.SchemaName != "" {
+		return r.SchemaName
+	}
+	return schemaType(r.Schema)
+}
+
+// TypeName returns a short name for the type of the schema, such as []Pet or integer (int64), using the names of
+// referenced definitions
+func (s *Schema) TypeName() string {
+	return schemaType(s)
+}
+
+// schemaType returns a short name for the type of the schema using the names of referenced definitions
+func schemaType(s *Schema) string {
+	if s == nil {
+		return ""
+	}
+	if name, ok := s.referencedDefinition(); ok {
+		return name
+	}
+	types := s.Type.Values()
+	if len(types) == 1 && types[0] == "array" {
+		if items, ok := s.Items.AsSchema(); ok {
+			return "[]" + schemaType(items)
+		}
+	}
+	if len(types) == 0 {
+		if len(s.AllOf) > 0 {
+			return "allOf"
+		}
+		return "any"
+	}
+	result := strings.Join(types, "|")
+	if s.Format != "" {
+		result += " (" + s.Format + ")"
+	}
+	return result
+}
+
+// describeProperties returns the name and type of each property of the schema in name order
+func describeProperties(s *Schema) [][2]string {
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	results := make([][2]string, 0, len(s.Properties))
+	for _, name := range sortedKeys(s.Properties) {
+		prop := s.Properties[name]
+		typ := schemaType(&prop)
+		if required[name] {
+			typ += ", required"
+		}
+		results = append(results, [2]string{name, typ})
+	}
+	return results
+}
+
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
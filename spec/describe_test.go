@@ -0,0 +1,92 @@
+package spec
+
+import "testing"
+
+func TestSwagger_DescribeOperation(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"produces": ["application/json"],
+		"security": [{"oauth": ["read"]}, {"apiKey": []}],
+		"parameters": {
+			"fields": {"name": "fields", "in": "query", "type": "string", "description": "the fields to include"}
+		},
+		"paths": {
+			"/pets/{id}": {
+				"parameters": [
+					{"name": "id", "in": "path", "type": "string", "required": true},
+					{"name": "verbose", "in": "query", "type": "boolean"}
+				],
+				"get": {
+					"operationId": "getPet",
+					"summary": "Find a pet",
+					"tags": ["pets"],
+					"parameters": [
+						{"name": "verbose", "in": "query", "type": "string", "description": "level of detail"},
+						{"$ref": "#/parameters/fields"}
+					],
+					"responses": {
+						"200": {"description": "the pet", "schema": {"$ref": "#/definitions/Pet"}},
+						"default": {"description": "error"}
+					}
+				}
+			}
+		},
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"born": {"type": "string", "format": "date"},
+					"owner": {"$ref": "#/definitions/Owner"},
+					"toys": {"type": "array", "items": {"$ref": "#/definitions/Toy"}}
+				}
+			}
+		}
+	}`
+	swagger, err := NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	if _, found := swagger.DescribeOperation(OperationKey{Path: "/pets/{id}", Method: "DELETE"}); found {
+		t.Error("DescribeOperation() of a missing operation should not be found")
+	}
+	d, found := swagger.DescribeOperation(OperationKey{Path: "/pets/{id}", Method: "get"})
+	if !found {
+		t.Fatal("DescribeOperation() should find the operation")
+	}
+	expected := `GET /pets/{id} (getPet)
+Summary: Find a pet
+Tags: pets
+Produces: application/json
+Security: oauth[read] or apiKey
+Parameters:
+  verbose (query, string)
+    level of detail
+  fields (query, string)
+    the fields to include
+  id (path, string, required)
+Responses:
+  200: the pet
+    schema: Pet
+      born: string (date)
+      name: string, required
+      owner: Owner
+      toys: []Toy
+  default: error
+`
+	if got := d.Text(); got != expected {
+		t.Errorf("Text() =\n%s\nwant\n%s", got, expected)
+	}
+	expectedJSON := `{"method":"GET","path":"/pets/{id}","operationId":"getPet","summary":"Find a pet","tags":["pets"],` +
+		`"produces":["application/json"],"security":[{"oauth":["read"]},{"apiKey":[]}],` +
+		`"parameters":[{"name":"verbose","in":"query","description":"level of detail","type":"string"},` +
+		`{"name":"fields","in":"query","description":"the fields to include","type":"string"},` +
+		`{"name":"id","in":"path","required":true,"type":"string"}],` +
+		`"responses":{"200":{"description":"the pet","schemaName":"Pet","schema":{"type":"object","required":["name"],"properties":{` +
+		`"born":{"type":"string","format":"date"},"name":{"type":"string"},"owner":{"$ref":"#/definitions/Owner"},"toys":{"type":"array","items":{"$ref":"#/definitions/Toy"}}}}},` +
+		`"default":{"description":"error"}}}`
+	if got, _ := d.MarshalJSON(); string(got) != expectedJSON {
+		t.Errorf("MarshalJSON() =\n%s\nwant\n%s", got, expectedJSON)
+	}
+}
@@ -1,2 +1,8 @@
 // Package spec provides the basic parsing and validation of swagger specifications
+//
+// A parsed *Swagger is safe for concurrent use by multiple goroutines as long as none of them change it, so its read
+// methods such as OperationMap, Operations, FindOperation and DescribeOperation may be called at once. Methods return
+// new maps and slices that callers may change freely, though the objects within them are shared with the spec. Changing
+// the spec, whether by AddOperation, RemoveOperation, Reparse, a transform or setting its fields, must not happen
+// concurrently with any other use of it.
 package spec
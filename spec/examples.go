@@ -0,0 +1,52 @@
+package spec
+
+import "github.com/valyala/fastjson"
+
+// StaleExample is an example or default value that was valid against its schema in one spec but is invalid against
+// the schema at the same location in another
+type StaleExample struct {
+	// Location is that of the example or default value itself
+	Location string
+	Errors   []error
+}
+
+// StaleExamples re-validates every example and default value of the schemas in the from spec against the schemas at
+// the same locations in the to spec, returning those that were valid but no longer are
+func StaleExamples(from, to *Swagger) []StaleExample {
+	toSchemas := make(map[string]*Schema)
+	to.walkSchemas(func(loc string, schema *Schema) {
+		toSchemas[loc] = schema
+	})
+	var (
+		a       fastjson.Arena
+		results []StaleExample
+	)
+	from.walkSchemas(func(loc string, schema *Schema) {
+		toSchema := toSchemas[loc]
+		if toSchema == nil {
+			return
+		}
+		for _, ex := range []struct {
+			field string
+			value any
+		}{
+			{"default", schema.Default},
+			{"example", schema.Example},
+		} {
+			if ex.value == nil {
+				continue
+			}
+			val := marshalAny(&a, ex.value)
+			if len(from.ValidateValue(schema, val)) > 0 {
+				continue
+			}
+			if errs := to.ValidateValue(toSchema, val); len(errs) > 0 {
+				results = append(results, StaleExample{
+					Location: loc + "." + ex.field,
+					Errors:   errs,
+				})
+			}
+		}
+	})
+	return results
+}
@@ -0,0 +1,67 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStaleExamples(t *testing.T) {
+	from, err := NewParser([]byte(`{
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "example": "Tom"},
+					"age": {"type": "integer", "default": 3},
+					"tags": {"type": "array", "items": {"type": "string"}}
+				},
+				"example": {"name": "Tom", "tags": ["cat", "cat"], "owner": {"id": 1}}
+			},
+			"Owner": {"type": "object", "properties": {"id": {"type": "integer"}}},
+			"Broken": {"type": "string", "example": 5}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	to, err := NewParser([]byte(`{
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"required": ["name", "kind"],
+				"additionalProperties": false,
+				"properties": {
+					"name": {"type": "string", "minLength": 4},
+					"age": {"type": "string"},
+					"kind": {"type": "string", "enum": ["cat", "dog"]},
+					"owner": {"$ref": "#/definitions/Owner"},
+					"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true}
+				}
+			},
+			"Owner": {"type": "object", "properties": {"id": {"type": "string"}}},
+			"Broken": {"type": "integer"}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	got := make(map[string][]string)
+	for _, stale := range StaleExamples(from, to) {
+		for _, e := range stale.Errors {
+			got[stale.Location] = append(got[stale.Location], e.Error())
+		}
+	}
+	expected := map[string][]string{
+		".definitions.Pet.example": {
+			"value: missing required property 'kind'",
+			".name: length of 3 is less than the minLength of 4",
+			".tags: items 0 and 1 are not unique",
+			".owner.id: expected type [string] but got number",
+		},
+		".definitions.Pet.properties.age.default":  {"value: expected type [string] but got number"},
+		".definitions.Pet.properties.name.example": {"value: length of 3 is less than the minLength of 4"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("StaleExamples() =\n%v\nwant\n%v", got, expected)
+	}
+}
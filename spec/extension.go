@@ -1,6 +1,8 @@
 package spec
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/valyala/fastjson"
@@ -9,10 +11,192 @@ import (
 // Extensions defines a map of keys prefixed with 'x-' and any type of value
 type Extensions map[string]*fastjson.Value
 
-func (exts Extensions) marshalExtensions(val *fastjson.Value) {
+// Get returns the value of the extension decoded as a Go value like encoding/json would and if it exists
+func (exts Extensions) Get(key string) (any, bool) {
+	v, exists := exts[key]
+	if !exists {
+		return nil, false
+	}
+	return decodeValue(v), true
+}
+
+// GetString returns the value of the extension and if it exists as a string
+func (exts Extensions) GetString(key string) (string, bool) {
+	if v := exts[key]; v != nil && v.Type() == fastjson.TypeString {
+		return string(v.GetStringBytes()), true
+	}
+	return "", false
+}
+
+// GetBool returns the value of the extension and if it exists as a bool
+func (exts Extensions) GetBool(key string) (bool, bool) {
+	if v := exts[key]; v != nil {
+		if b, err := v.Bool(); err == nil {
+			return b, true
+		}
+	}
+	return false, false
+}
+
+// GetInt returns the value of the extension and if it exists as an integer
+func (exts Extensions) GetInt(key string) (int, bool) {
+	if v := exts[key]; v != nil {
+		if i, err := v.Int(); err == nil {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// GetFloat returns the value of the extension and if it exists as a number
+func (exts Extensions) GetFloat(key string) (float64, bool) {
+	if v := exts[key]; v != nil && v.Type() == fastjson.TypeNumber {
+		return v.GetFloat64(), true
+	}
+	return 0, false
+}
+
+// GetObject returns the value of the extension decoded as a map and if it exists as an object
+func (exts Extensions) GetObject(key string) (map[string]any, bool) {
+	if v := exts[key]; v != nil && v.Type() == fastjson.TypeObject {
+		return decodeValue(v).(map[string]any), true
+	}
+	return nil, false
+}
+
+// Set sets the extension to the Go value, which must be a *fastjson.Value or encodable by encoding/json
+func (exts Extensions) Set(key string, value any) error {
+	if exts == nil {
+		return fmt.Errorf("cannot set extension '%s' on nil extensions", key)
+	}
+	if !strings.HasPrefix(key, "x-") {
+		return fmt.Errorf("invalid extension name: '%s' must be prefixed with 'x-'", key)
+	}
+	var a fastjson.Arena
+	exts[key] = marshalAny(&a, value)
+	return nil
+}
+
+// EqualExtensions returns true if both have the same keys with deeply equal JSON values
+func (exts Extensions) EqualExtensions(other Extensions) bool {
+	if len(exts) != len(other) {
+		return false
+	}
+	for k, v := range exts {
+		o, exists := other[k]
+		if !exists || !equalValues(v, o) {
+			return false
+		}
+	}
+	return true
+}
+
+// CloneExtensions returns a deep copy whose values no longer reference the source document
+func (exts Extensions) CloneExtensions() Extensions {
+	if exts == nil {
+		return nil
+	}
+	result := make(Extensions, len(exts))
 	for k, v := range exts {
+		result[k] = cloneValue(v)
+	}
+	return result
+}
+
+func (exts Extensions) marshalExtensions(val *fastjson.Value) {
+	keys := make([]string, 0, len(exts))
+	for k := range exts {
 		if strings.HasPrefix(k, "x-") {
-			val.Set(k, v)
+			keys = append(keys, k)
 		}
 	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		val.Set(k, exts[k])
+	}
+}
+
+// decodeValue returns the JSON value as a Go value using the same types as encoding/json
+func decodeValue(v *fastjson.Value) any {
+	if v == nil {
+		return nil
+	}
+	switch v.Type() {
+	case fastjson.TypeObject:
+		obj := v.GetObject()
+		result := make(map[string]any, obj.Len())
+		obj.Visit(func(key []byte, child *fastjson.Value) {
+			result[string(key)] = decodeValue(child)
+		})
+		return result
+	case fastjson.TypeArray:
+		vals := v.GetArray()
+		result := make([]any, len(vals))
+		for i := range vals {
+			result[i] = decodeValue(vals[i])
+		}
+		return result
+	case fastjson.TypeString:
+		return string(v.GetStringBytes())
+	case fastjson.TypeNumber:
+		return v.GetFloat64()
+	case fastjson.TypeTrue:
+		return true
+	case fastjson.TypeFalse:
+		return false
+	}
+	return nil
+}
+
+// equalValues returns true if both JSON values are deeply equal, ignoring the order of object keys
+func equalValues(a, b *fastjson.Value) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Type() {
+	case fastjson.TypeObject:
+		aObj, bObj := a.GetObject(), b.GetObject()
+		if aObj.Len() != bObj.Len() {
+			return false
+		}
+		equal := true
+		aObj.Visit(func(key []byte, child *fastjson.Value) {
+			if equal {
+				equal = equalValues(child, bObj.Get(string(key)))
+			}
+		})
+		return equal
+	case fastjson.TypeArray:
+		aVals, bVals := a.GetArray(), b.GetArray()
+		if len(aVals) != len(bVals) {
+			return false
+		}
+		for i := range aVals {
+			if !equalValues(aVals[i], bVals[i]) {
+				return false
+			}
+		}
+		return true
+	case fastjson.TypeString:
+		return string(a.GetStringBytes()) == string(b.GetStringBytes())
+	case fastjson.TypeNumber:
+		return a.GetFloat64() == b.GetFloat64()
+	}
+	return true
+}
+
+// cloneValue returns a deep copy of the JSON value which does not share any memory with it
+func cloneValue(v *fastjson.Value) *fastjson.Value {
+	if v == nil {
+		return nil
+	}
+	clone, err := fastjson.ParseBytes(v.MarshalTo(nil))
+	if err != nil {
+		// the marshalled form of a parsed value is always valid JSON
+		panic(err)
+	}
+	return clone
 }
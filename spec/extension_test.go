@@ -0,0 +1,65 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtensions(t *testing.T) {
+	swagger, err := NewParser([]byte(`{
+		"x-name": "pets",
+		"x-enabled": true,
+		"x-limit": 100,
+		"x-ratio": 0.5,
+		"x-owner": {"team": "core", "slack": ["#pets"]}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	exts := swagger.Extensions
+	if s, ok := exts.GetString("x-name"); !ok || s != "pets" {
+		t.Errorf("GetString() = %q, %t", s, ok)
+	}
+	if _, ok := exts.GetString("x-limit"); ok {
+		t.Error("GetString() of a number should not be ok")
+	}
+	if b, ok := exts.GetBool("x-enabled"); !ok || !b {
+		t.Errorf("GetBool() = %t, %t", b, ok)
+	}
+	if i, ok := exts.GetInt("x-limit"); !ok || i != 100 {
+		t.Errorf("GetInt() = %d, %t", i, ok)
+	}
+	if f, ok := exts.GetFloat("x-ratio"); !ok || f != 0.5 {
+		t.Errorf("GetFloat() = %f, %t", f, ok)
+	}
+	expected := map[string]any{"team": "core", "slack": []any{"#pets"}}
+	if obj, ok := exts.GetObject("x-owner"); !ok || !reflect.DeepEqual(obj, expected) {
+		t.Errorf("GetObject() = %v, %t", obj, ok)
+	}
+	if _, ok := exts.Get("x-missing"); ok {
+		t.Error("Get() of a missing extension should not be ok")
+	}
+
+	clone := exts.CloneExtensions()
+	if !clone.EqualExtensions(exts) {
+		t.Error("clone should equal the original")
+	}
+	if err = clone.Set("x-owner", map[string]any{"slack": []any{"#pets"}, "team": "core"}); err != nil {
+		t.Fatalf("Set() failed: %s", err)
+	}
+	if !clone.EqualExtensions(exts) {
+		t.Error("equality should ignore the order of object keys")
+	}
+	if err = clone.Set("x-limit", 99); err != nil {
+		t.Fatalf("Set() failed: %s", err)
+	}
+	if clone.EqualExtensions(exts) {
+		t.Error("clone should not equal the original after a change")
+	}
+	if i, _ := exts.GetInt("x-limit"); i != 100 {
+		t.Errorf("changing the clone changed the original to %d", i)
+	}
+	if err = clone.Set("limit", 1); err == nil {
+		t.Error("Set() without the x- prefix should fail")
+	}
+}
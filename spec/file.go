@@ -0,0 +1,31 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+)
+
+// ParseFile parses the swagger spec in the file at the path configured with any options. Where supported the file is
+// memory-mapped rather than read onto the heap, and it is unmapped before returning since the parsed model does not
+// reference the raw bytes.
+func ParseFile(path string, opts ...ParserOption) (*Swagger, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	raw, unmap, err := mapFile(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer unmap()
+	p := NewParser(raw, opts...)
+	defer func() {
+		p.raw = nil
+	}()
+	return p.Parse()
+}
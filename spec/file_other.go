@@ -0,0 +1,17 @@
+//go:build !unix
+
+package spec
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the whole file as memory-mapping is not supported on this platform
+func mapFile(f *os.File, size int64) ([]byte, func(), error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, nil, err
+	}
+	return data, func() {}, nil
+}
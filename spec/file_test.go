@@ -0,0 +1,36 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "swagger.json")
+	if err := os.WriteFile(path, []byte(`{"swagger": "2.0", "host": "example.com", "x-id": "a"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	swagger, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile() failed: %s", err)
+	}
+	if swagger.Host != "example.com" {
+		t.Errorf("Host = %q", swagger.Host)
+	}
+	if id, _ := swagger.GetString("x-id"); id != "a" {
+		t.Errorf("x-id = %q after the file was unmapped", id)
+	}
+
+	empty := filepath.Join(dir, "empty.json")
+	if err = os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ParseFile(empty); err == nil {
+		t.Error("ParseFile() of an empty file should fail")
+	}
+	if _, err = ParseFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("ParseFile() of a missing file should fail")
+	}
+}
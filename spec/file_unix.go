@@ -0,0 +1,22 @@
+//go:build unix
+
+package spec
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile memory-maps the file read-only, returning its contents and the func to unmap them
+func mapFile(f *os.File, size int64) ([]byte, func(), error) {
+	if size == 0 {
+		return nil, func() {}, nil
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() {
+		_ = syscall.Munmap(data)
+	}, nil
+}
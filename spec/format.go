@@ -0,0 +1,182 @@
+package spec
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fastjson"
+)
+
+// FormatOptions defines the configuration of Format
+type FormatOptions struct {
+	// Indent is the indentation of each level, when empty two spaces are used
+	Indent string
+}
+
+// Format returns the swagger spec re-emitted in its canonical form: indented, with the fields of each object in the
+// order defined by the swagger specification followed by any other keys alphabetically, and with numbers and strings
+// in their shortest form. Only the layout changes, so any values the parser would reject are kept as they are and
+// formatting an already formatted spec returns it unchanged.
+func Format(raw []byte, opts FormatOptions) ([]byte, error) {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	jp := parserPool.Get()
+	defer parserPool.Put(jp)
+	root, err := jp.ParseBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse raw swagger bytes as JSON: %w", err)
+	}
+
+	// the canonical order of the keys of each object is that of marshalling the parsed spec
+	var order keyOrder
+	p := NewParser(raw, WithAllowUnknownFields())
+	defer p.Release()
+	if swagger, _ := p.Parse(); swagger != nil {
+		a := arenaPool.Get()
+		defer func() {
+			a.Reset()
+			arenaPool.Put(a)
+		}()
+		order = recordKeyOrder(swagger.marshal(a))
+	}
+	f := formatter{order: order, indent: opts.Indent}
+	f.write(root, "", 0)
+	f.buf = append(f.buf, '\n')
+	return f.buf, nil
+}
+
+type formatter struct {
+	buf    []byte
+	order  keyOrder
+	indent string
+}
+
+func (f *formatter) newline(depth int) {
+	f.buf = append(f.buf, '\n')
+	for i := 0; i < depth; i++ {
+		f.buf = append(f.buf, f.indent...)
+	}
+}
+
+func (f *formatter) write(v *fastjson.Value, loc string, depth int) {
+	switch v.Type() {
+	case fastjson.TypeObject:
+		type member struct {
+			key   string
+			value *fastjson.Value
+		}
+		var members []member
+		v.GetObject().Visit(func(key []byte, child *fastjson.Value) {
+			members = append(members, member{key: string(key), value: child})
+		})
+		if len(members) == 0 {
+			f.buf = append(f.buf, "{}"...)
+			return
+		}
+		rank := make(map[string]int)
+		for i, k := range f.order[rootLoc(loc)] {
+			rank[k] = i
+		}
+		sort.SliceStable(members, func(i, j int) bool {
+			ri, iok := rank[members[i].key]
+			rj, jok := rank[members[j].key]
+			switch {
+			case iok && jok:
+				return ri < rj
+			case iok != jok:
+				return iok
+			}
+			return members[i].key < members[j].key
+		})
+		f.buf = append(f.buf, '{')
+		for i, m := range members {
+			if i > 0 {
+				f.buf = append(f.buf, ',')
+			}
+			f.newline(depth + 1)
+			f.buf = appendJSONString(f.buf, m.key)
+			f.buf = append(f.buf, ": "...)
+			f.write(m.value, loc+"."+m.key, depth+1)
+		}
+		f.newline(depth)
+		f.buf = append(f.buf, '}')
+	case fastjson.TypeArray:
+		items := v.GetArray()
+		if len(items) == 0 {
+			f.buf = append(f.buf, "[]"...)
+			return
+		}
+		f.buf = append(f.buf, '[')
+		for i, item := range items {
+			if i > 0 {
+				f.buf = append(f.buf, ',')
+			}
+			f.newline(depth + 1)
+			f.write(item, loc+"["+strconv.Itoa(i)+"]", depth+1)
+		}
+		f.newline(depth)
+		f.buf = append(f.buf, ']')
+	case fastjson.TypeString:
+		f.buf = appendJSONString(f.buf, string(v.GetStringBytes()))
+	case fastjson.TypeNumber:
+		f.buf = appendJSONNumber(f.buf, v.String())
+	default:
+		f.buf = v.MarshalTo(f.buf)
+	}
+}
+
+// appendJSONString appends the string quoted with only the escapes JSON requires
+func appendJSONString(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	dst = append(dst, '"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c == '\n':
+			dst = append(dst, '\\', 'n')
+		case c == '\r':
+			dst = append(dst, '\\', 'r')
+		case c == '\t':
+			dst = append(dst, '\\', 't')
+		case c < 0x20:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return append(dst, '"')
+}
+
+// appendJSONNumber appends the number in its shortest form, keeping integers exactly as written since they may exceed
+// the precision of a float64
+func appendJSONNumber(dst []byte, num string) []byte {
+	if !strings.ContainsAny(num, ".eE") {
+		if num == "-0" {
+			num = "0"
+		}
+		return append(dst, num...)
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || math.IsInf(f, 0) {
+		return append(dst, num...)
+	}
+	if f == 0 {
+		return append(dst, '0')
+	}
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		dst = strconv.AppendFloat(dst, f, 'e', -1, 64)
+		// shorten a two digit exponent such as e-07 to e-7
+		if n := len(dst); n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+		return dst
+	}
+	return strconv.AppendFloat(dst, f, 'f', -1, 64)
+}
@@ -0,0 +1,70 @@
+package spec
+
+import (
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	raw := []byte(`{"paths":{"/pets":{"x-b":1,"get":{"responses":{"default":{"description":"error"},"200":{"description":"ok"}},"unknown":true,"summary":"List pets\ttab"}}},
+	"info":{"version":"1.0","title":"pets"},"swagger":"2.0","x-a":[1.50, 1e2, -0, 12345678901234567890, 2.5E-7, {}, []],"definitions":{"Pet":{"type":"object","example":{"b":1,"a":2}}}}`)
+	expected := `{
+  "swagger": "2.0",
+  "info": {
+    "title": "pets",
+    "version": "1.0"
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "summary": "List pets\ttab",
+        "responses": {
+          "200": {
+            "description": "ok"
+          },
+          "default": {
+            "description": "error"
+          }
+        },
+        "unknown": true
+      },
+      "x-b": 1
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "example": {
+        "b": 1,
+        "a": 2
+      }
+    }
+  },
+  "x-a": [
+    1.5,
+    100,
+    0,
+    12345678901234567890,
+    2.5e-7,
+    {},
+    []
+  ]
+}
+`
+	got, err := Format(raw, FormatOptions{})
+	if err != nil {
+		t.Fatalf("Format() failed: %s", err)
+	}
+	if string(got) != expected {
+		t.Errorf("Format() =\n%s\nwant\n%s", got, expected)
+	}
+	again, err := Format(got, FormatOptions{})
+	if err != nil || string(again) != string(got) {
+		t.Errorf("Format() of a formatted spec should not change it but got:\n%s", again)
+	}
+	if got, _ = Format([]byte(`{"swagger":"2.0"}`), FormatOptions{Indent: "\t"}); string(got) != "{\n\t\"swagger\": \"2.0\"\n}\n" {
+		t.Errorf("Format() with tabs = %q", got)
+	}
+	if _, err = Format([]byte(`{"swagger":`), FormatOptions{}); err == nil {
+		t.Error("Format() of invalid JSON should fail")
+	}
+}
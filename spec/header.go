@@ -2,6 +2,7 @@ package spec
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/valyala/fastjson"
@@ -16,23 +17,30 @@ type Header struct {
 	Items            *Items
 	CollectionFormat string
 	Default          any
-	Maximum          int
+	Maximum          *float64
 	ExclusiveMaximum bool
-	Minimum          int
+	Minimum          *float64
 	ExclusiveMinimum bool
-	MaxLength        int
-	MinLength        int
+	MaxLength        *int
+	MinLength        *int
 	Pattern          string
-	MaxItems         int
-	MinItems         int
+	MaxItems         *int
+	MinItems         *int
 	UniqueItems      bool
-	MaxProperties    int
-	MinProperties    int
+	MaxProperties    *int
+	MinProperties    *int
 	Required         bool
 	Enum             []any
-	MultipleOf       int
+	MultipleOf       *float64
 }
 
+var (
+	// headerTypes are the primitive types allowed for a Header
+	headerTypes = []string{"string", "number", "integer", "boolean", "array"}
+	// headerCollectionFormats are the collection formats allowed for a Header, which unlike a Parameter excludes multi
+	headerCollectionFormats = []string{"csv", "ssv", "tsv", "pipes"}
+)
+
 // NewHeader returns a new Header object
 func NewHeader() *Header {
 	return &Header{
@@ -41,27 +49,30 @@ func NewHeader() *Header {
 }
 
 func parseHeader(val *fastjson.Value, parser *Parser) *Header {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid header value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid header value: %w", err))
 		return nil
 	}
 	result := NewHeader()
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "description"):
 			parser.parseString(v, "description", true, func(s string) {
 				result.Description = s
 			})
 		case matchString(key, "type"):
-			parser.parseString(v, "type", false, func(s string) {
+			parser.parseAndValidateString(v, "type", func(s string) error {
+				if !containsString(headerTypes, s) {
+					return fmt.Errorf("header type should be one of %v but got: '%s'", headerTypes, s)
+				}
 				result.Type = s
+				return nil
 			})
 		case matchString(key, "format"):
 			parser.parseString(v, "format", true, func(s string) {
@@ -70,22 +81,26 @@ func parseHeader(val *fastjson.Value, parser *Parser) *Header {
 		case matchString(key, "items"):
 			result.Items = parseItems(v, parser)
 		case matchString(key, "collectionFormat"):
-			parser.parseString(v, "collectionFormat", true, func(s string) {
+			parser.parseAndValidateString(v, "collectionFormat", func(s string) error {
+				if !containsString(headerCollectionFormats, s) {
+					return fmt.Errorf("header collectionFormat should be one of %v but got: '%s'", headerCollectionFormats, s)
+				}
 				result.CollectionFormat = s
+				return nil
 			})
 		case matchString(key, "default"):
-			result.Default = v
+			result.Default = detachValue(v)
 		case matchString(key, "maximum"):
-			parser.parseInt(v, "maximum", func(i int) {
-				result.Maximum = i
+			parser.parseFloat(v, "maximum", func(f float64) {
+				result.Maximum = &f
 			})
 		case matchString(key, "exclusiveMaximum"):
 			parser.parseBool(v, "exclusiveMaximum", func(b bool) {
 				result.ExclusiveMaximum = b
 			})
 		case matchString(key, "minimum"):
-			parser.parseInt(v, "minimum", func(i int) {
-				result.Minimum = i
+			parser.parseFloat(v, "minimum", func(f float64) {
+				result.Minimum = &f
 			})
 		case matchString(key, "exclusiveMinimum"):
 			parser.parseBool(v, "exclusiveMinimum", func(b bool) {
@@ -93,11 +108,11 @@ func parseHeader(val *fastjson.Value, parser *Parser) *Header {
 			})
 		case matchString(key, "maxLength"):
 			parser.parseInt(v, "maxLength", func(i int) {
-				result.MaxLength = i
+				result.MaxLength = &i
 			})
 		case matchString(key, "minLength"):
 			parser.parseInt(v, "minLength", func(i int) {
-				result.MinLength = i
+				result.MinLength = &i
 			})
 		case matchString(key, "pattern"):
 			parser.parseString(v, "pattern", true, func(s string) {
@@ -105,11 +120,11 @@ func parseHeader(val *fastjson.Value, parser *Parser) *Header {
 			})
 		case matchString(key, "maxItems"):
 			parser.parseInt(v, "maxItems", func(i int) {
-				result.MaxItems = i
+				result.MaxItems = &i
 			})
 		case matchString(key, "minItems"):
 			parser.parseInt(v, "minItems", func(i int) {
-				result.MinItems = i
+				result.MinItems = &i
 			})
 		case matchString(key, "uniqueItems"):
 			parser.parseBool(v, "uniqueItems", func(b bool) {
@@ -117,22 +132,55 @@ func parseHeader(val *fastjson.Value, parser *Parser) *Header {
 			})
 		case matchString(key, "enum"):
 			if vals, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid enum value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid enum value: %w", e))
 			} else {
 				result.Enum = make([]any, len(vals))
 				for i := range vals {
-					result.Enum[i] = vals[i]
+					result.Enum[i] = parser.retain(vals[i])
 				}
 			}
 		case matchString(key, "multipleOf"):
-			parser.parseInt(v, "multipleOf", func(i int) {
-				result.MultipleOf = i
+			parser.parseFloat(v, "multipleOf", func(f float64) {
+				result.MultipleOf = &f
 			})
 		case bytes.HasPrefix(key, []byte("x-")):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name '%s'", key))
 		}
 	})
+	parser.reset(fromLoc)
+	if result.Type == "array" && result.Items == nil {
+		parser.appendError(ErrorCodeInvalidValue, errors.New("header of type 'array' requires items"))
+	}
+	if result.CollectionFormat != "" && result.Type != "array" {
+		parser.appendError(ErrorCodeInvalidValue, fmt.Errorf("header collectionFormat requires type 'array' but got: '%s'", result.Type))
+	}
 	return result
 }
+
+func (h *Header) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setString(a, val, "description", h.Description)
+	setString(a, val, "type", h.Type)
+	setString(a, val, "format", h.Format)
+	if h.Items != nil {
+		val.Set("items", h.Items.marshal(a))
+	}
+	setString(a, val, "collectionFormat", h.CollectionFormat)
+	setAny(a, val, "default", h.Default)
+	setFloat(a, val, "maximum", h.Maximum)
+	setBool(a, val, "exclusiveMaximum", h.ExclusiveMaximum)
+	setFloat(a, val, "minimum", h.Minimum)
+	setBool(a, val, "exclusiveMinimum", h.ExclusiveMinimum)
+	setInt(a, val, "maxLength", h.MaxLength)
+	setInt(a, val, "minLength", h.MinLength)
+	setString(a, val, "pattern", h.Pattern)
+	setInt(a, val, "maxItems", h.MaxItems)
+	setInt(a, val, "minItems", h.MinItems)
+	setBool(a, val, "uniqueItems", h.UniqueItems)
+	setEnum(a, val, "enum", h.Enum)
+	setFloat(a, val, "multipleOf", h.MultipleOf)
+	h.marshalExtensions(val)
+	return val
+}
@@ -0,0 +1,44 @@
+package spec
+
+// OperationDefinitions returns the sorted names of the definitions the operation references from its parameters,
+// including those of its PathItem, and its responses, directly or through any other definition they reference
+func (s *Swagger) OperationDefinitions(op *Operation) []string {
+	if s == nil || op == nil {
+		return nil
+	}
+	pending := op.ResolvedReferencedDefinitions(s).Values()
+	for _, param := range op.EffectiveParameters(s.Paths.Items[op.Key.Path], s.Parameters) {
+		pending = append(pending, param.Schema.ReferencedDefinitions().Values()...)
+	}
+	return s.definitionClosure(pending)
+}
+
+// DefinitionUsage returns the sorted keys of the operations referencing each definition, directly or through any
+// other definition, where definitions not used by any operation are omitted
+func (s *Swagger) DefinitionUsage() map[string][]OperationKey {
+	if s == nil {
+		return nil
+	}
+	results := make(map[string][]OperationKey)
+	for _, op := range s.Operations() {
+		for _, name := range s.OperationDefinitions(op) {
+			results[name] = append(results[name], op.Key)
+		}
+	}
+	return results
+}
+
+// OperationsReferencing returns the sorted keys of the operations referencing the named definition, directly or
+// through any other definition, which are those affected by any change to it
+func (s *Swagger) OperationsReferencing(name string) []OperationKey {
+	if s == nil {
+		return nil
+	}
+	var results []OperationKey
+	for _, op := range s.Operations() {
+		if containsString(s.OperationDefinitions(op), name) {
+			results = append(results, op.Key)
+		}
+	}
+	return results
+}
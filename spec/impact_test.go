@@ -0,0 +1,58 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSwagger_DefinitionUsage(t *testing.T) {
+	swagger, err := NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/owners/{id}": {
+				"parameters": [{"name": "filter", "in": "body", "schema": {"$ref": "#/definitions/Filter"}}],
+				"get": {"responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Owner"}}}}
+			},
+			"/pets": {
+				"post": {
+					"parameters": [{"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}],
+					"responses": {"201": {"description": "created"}}
+				}
+			},
+			"/status": {
+				"get": {"responses": {"200": {"description": "ok"}}}
+			}
+		},
+		"definitions": {
+			"Owner": {"type": "object", "properties": {"pets": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}},
+			"Pet": {"type": "object", "properties": {"tag": {"$ref": "#/definitions/Tag"}, "parent": {"$ref": "#/definitions/Pet"}}},
+			"Tag": {"type": "string"},
+			"Filter": {"type": "object"},
+			"Unused": {"type": "object"}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	owners := OperationKey{Path: "/owners/{id}", Method: "GET"}
+	pets := OperationKey{Path: "/pets", Method: "POST"}
+	expected := map[string][]OperationKey{
+		"Filter": {owners},
+		"Owner":  {owners},
+		"Pet":    {owners, pets},
+		"Tag":    {owners, pets},
+	}
+	if got := swagger.DefinitionUsage(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("DefinitionUsage() = %v, want %v", got, expected)
+	}
+	if got := swagger.OperationsReferencing("Tag"); !reflect.DeepEqual(got, []OperationKey{owners, pets}) {
+		t.Errorf("OperationsReferencing(Tag) = %v", got)
+	}
+	if got := swagger.OperationsReferencing("Unused"); got != nil {
+		t.Errorf("OperationsReferencing(Unused) = %v, want none", got)
+	}
+	if got, want := swagger.OperationDefinitions(swagger.OperationMap()[pets]), []string{"Pet", "Tag"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OperationDefinitions() = %v, want %v", got, want)
+	}
+}
@@ -59,19 +59,18 @@ func NewLicense() *License {
 
 // parseInfo will attempt to parse an Info from the source swagger .info JSON value
 func parseInfo(infoVal *fastjson.Value, parser *Parser) *Info {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	infoObj, err := infoVal.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid result value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid result value: %w", err))
 		return nil
 	}
 	result := NewInfo()
 	infoObj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "title"):
 			parser.parseString(v, "title", false, func(s string) {
@@ -94,9 +93,9 @@ func parseInfo(infoVal *fastjson.Value, parser *Parser) *Info {
 		case bytes.Equal(key, []byte("license")):
 			result.License = parseLicense(v, parser)
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
@@ -104,19 +103,18 @@ func parseInfo(infoVal *fastjson.Value, parser *Parser) *Info {
 
 // parseContact will attempt to parse a Contact from the source swagger .info.contact JSON value
 func parseContact(contactVal *fastjson.Value, parser *Parser) *Contact {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	contactObj, err := contactVal.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid result value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid result value: %w", err))
 		return nil
 	}
 	result := NewContact()
 	contactObj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "name"):
 			parser.parseString(v, "name", true, func(s string) {
@@ -131,9 +129,9 @@ func parseContact(contactVal *fastjson.Value, parser *Parser) *Contact {
 				result.URL = s
 			})
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
@@ -141,19 +139,18 @@ func parseContact(contactVal *fastjson.Value, parser *Parser) *Contact {
 
 // parseLicense will attempt to parse a License from the source swagger .info.license JSON value
 func parseLicense(licenseVal *fastjson.Value, parser *Parser) *License {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	licenseObj, err := licenseVal.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid result value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid result value: %w", err))
 		return nil
 	}
 	result := NewLicense()
 	licenseObj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "name"):
 			parser.parseString(v, "name", false, func(s string) {
@@ -164,10 +161,43 @@ func parseLicense(licenseVal *fastjson.Value, parser *Parser) *License {
 				result.URL = s
 			})
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
+
+func (info *Info) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setString(a, val, "title", info.Title)
+	setString(a, val, "description", info.Description)
+	setString(a, val, "termsOfService", info.TermsOfService)
+	if info.Contact != nil {
+		val.Set("contact", info.Contact.marshal(a))
+	}
+	if info.License != nil {
+		val.Set("license", info.License.marshal(a))
+	}
+	setString(a, val, "version", info.Version)
+	info.marshalExtensions(val)
+	return val
+}
+
+func (c *Contact) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setString(a, val, "name", c.Name)
+	setString(a, val, "url", c.URL)
+	setString(a, val, "email", c.Email)
+	c.marshalExtensions(val)
+	return val
+}
+
+func (l *License) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setString(a, val, "name", l.Name)
+	setString(a, val, "url", l.URL)
+	l.marshalExtensions(val)
+	return val
+}
@@ -15,19 +15,19 @@ type Items struct {
 	Items            *Items
 	CollectionFormat string
 	Default          any
-	MultipleOf       int
-	Maximum          int
+	MultipleOf       *float64
+	Maximum          *float64
 	ExclusiveMaximum bool
-	Minimum          int
+	Minimum          *float64
 	ExclusiveMinimum bool
-	MaxLength        int
-	MinLength        int
+	MaxLength        *int
+	MinLength        *int
 	Pattern          string
-	MaxItems         int
-	MinItems         int
+	MaxItems         *int
+	MinItems         *int
 	UniqueItems      bool
-	MaxProperties    int
-	MinProperties    int
+	MaxProperties    *int
+	MinProperties    *int
 	Required         bool
 	Enum             []any
 }
@@ -40,19 +40,18 @@ func NewItems() *Items {
 }
 
 func parseItems(val *fastjson.Value, parser *Parser) *Items {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid result value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid result value: %w", err))
 		return nil
 	}
 	result := NewItems()
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "type"):
 			parser.parseString(v, "type", false, func(s string) {
@@ -69,22 +68,22 @@ func parseItems(val *fastjson.Value, parser *Parser) *Items {
 				result.CollectionFormat = s
 			})
 		case matchString(key, "default"):
-			result.Default = v
+			result.Default = detachValue(v)
 		case matchString(key, "multipleOf"):
-			parser.parseInt(v, "multipleOf", func(i int) {
-				result.MultipleOf = i
+			parser.parseFloat(v, "multipleOf", func(f float64) {
+				result.MultipleOf = &f
 			})
 		case matchString(key, "maximum"):
-			parser.parseInt(v, "maximum", func(i int) {
-				result.Maximum = i
+			parser.parseFloat(v, "maximum", func(f float64) {
+				result.Maximum = &f
 			})
 		case matchString(key, "exclusiveMaximum"):
 			parser.parseBool(v, "exclusiveMaximum", func(b bool) {
 				result.ExclusiveMaximum = b
 			})
 		case matchString(key, "minimum"):
-			parser.parseInt(v, "minimum", func(i int) {
-				result.Minimum = i
+			parser.parseFloat(v, "minimum", func(f float64) {
+				result.Minimum = &f
 			})
 		case matchString(key, "exclusiveMinimum"):
 			parser.parseBool(v, "exclusiveMinimum", func(b bool) {
@@ -92,11 +91,11 @@ func parseItems(val *fastjson.Value, parser *Parser) *Items {
 			})
 		case matchString(key, "maxLength"):
 			parser.parseInt(v, "maxLength", func(i int) {
-				result.MaxLength = i
+				result.MaxLength = &i
 			})
 		case matchString(key, "minLength"):
 			parser.parseInt(v, "minLength", func(i int) {
-				result.MinLength = i
+				result.MinLength = &i
 			})
 		case matchString(key, "pattern"):
 			parser.parseString(v, "pattern", true, func(s string) {
@@ -104,11 +103,11 @@ func parseItems(val *fastjson.Value, parser *Parser) *Items {
 			})
 		case matchString(key, "maxItems"):
 			parser.parseInt(v, "maxItems", func(i int) {
-				result.MaxItems = i
+				result.MaxItems = &i
 			})
 		case matchString(key, "minItems"):
 			parser.parseInt(v, "minItems", func(i int) {
-				result.MinItems = i
+				result.MinItems = &i
 			})
 		case matchString(key, "uniqueItems"):
 			parser.parseBool(v, "uniqueItems", func(b bool) {
@@ -116,11 +115,11 @@ func parseItems(val *fastjson.Value, parser *Parser) *Items {
 			})
 		case matchString(key, "maxProperties"):
 			parser.parseInt(v, "maxProperties", func(i int) {
-				result.MaxProperties = i
+				result.MaxProperties = &i
 			})
 		case matchString(key, "minProperties"):
 			parser.parseInt(v, "minProperties", func(i int) {
-				result.MinProperties = i
+				result.MinProperties = &i
 			})
 		case matchString(key, "required"):
 			parser.parseBool(v, "required", func(b bool) {
@@ -128,18 +127,46 @@ func parseItems(val *fastjson.Value, parser *Parser) *Items {
 			})
 		case matchString(key, "enum"):
 			if vals, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid enum value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid enum value: %w", e))
 			} else {
 				result.Enum = make([]any, len(vals))
 				for i := range vals {
-					result.Enum[i] = vals[i]
+					result.Enum[i] = parser.retain(vals[i])
 				}
 			}
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
+
+func (it *Items) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setString(a, val, "type", it.Type)
+	setString(a, val, "format", it.Format)
+	if it.Items != nil {
+		val.Set("items", it.Items.marshal(a))
+	}
+	setString(a, val, "collectionFormat", it.CollectionFormat)
+	setAny(a, val, "default", it.Default)
+	setFloat(a, val, "maximum", it.Maximum)
+	setBool(a, val, "exclusiveMaximum", it.ExclusiveMaximum)
+	setFloat(a, val, "minimum", it.Minimum)
+	setBool(a, val, "exclusiveMinimum", it.ExclusiveMinimum)
+	setInt(a, val, "maxLength", it.MaxLength)
+	setInt(a, val, "minLength", it.MinLength)
+	setString(a, val, "pattern", it.Pattern)
+	setInt(a, val, "maxItems", it.MaxItems)
+	setInt(a, val, "minItems", it.MinItems)
+	setBool(a, val, "uniqueItems", it.UniqueItems)
+	setInt(a, val, "maxProperties", it.MaxProperties)
+	setInt(a, val, "minProperties", it.MinProperties)
+	setBool(a, val, "required", it.Required)
+	setEnum(a, val, "enum", it.Enum)
+	setFloat(a, val, "multipleOf", it.MultipleOf)
+	it.marshalExtensions(val)
+	return val
+}
@@ -0,0 +1,47 @@
+package spec
+
+import "iter"
+
+// AllOperations returns an iterator over the operations of this spec by their keys in no particular order, which
+// avoids sorting them as Operations does
+func (s *Swagger) AllOperations() iter.Seq2[OperationKey, *Operation] {
+	return func(yield func(OperationKey, *Operation) bool) {
+		if s == nil {
+			return
+		}
+		for key, op := range s.operationMap {
+			if !yield(key, op) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the path items by their paths in no particular order
+func (p *Paths) All() iter.Seq2[string, *PathItem] {
+	return func(yield func(string, *PathItem) bool) {
+		if p == nil {
+			return
+		}
+		for path, pi := range p.Items {
+			if pi != nil && !yield(path, pi) {
+				return
+			}
+		}
+	}
+}
+
+// AllProperties returns an iterator over the properties of this schema by their names in no particular order, where
+// each is a copy so changing it does not change this schema
+func (s *Schema) AllProperties() iter.Seq2[string, *Schema] {
+	return func(yield func(string, *Schema) bool) {
+		if s == nil {
+			return
+		}
+		for name, prop := range s.Properties {
+			if !yield(name, &prop) {
+				return
+			}
+		}
+	}
+}
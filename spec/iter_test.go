@@ -0,0 +1,61 @@
+package spec
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestIterators(t *testing.T) {
+	swagger, err := NewParser(generateSpec(5)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	count := 0
+	for key, op := range swagger.AllOperations() {
+		if op.Key != key {
+			t.Errorf("AllOperations() yielded %v for the key %v", op.Key, key)
+		}
+		count++
+	}
+	if count != swagger.OperationCount() {
+		t.Errorf("AllOperations() yielded %d operations, want %d", count, swagger.OperationCount())
+	}
+	for range swagger.AllOperations() {
+		count--
+		break
+	}
+	if count != swagger.OperationCount()-1 {
+		t.Error("AllOperations() should stop when the loop breaks")
+	}
+
+	var paths []string
+	for path, pi := range swagger.Paths.All() {
+		if pi.Get == nil {
+			t.Errorf("Paths.All() yielded %s without its operations", path)
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if len(paths) != 5 || paths[0] != "/resources0/{id}" {
+		t.Errorf("Paths.All() = %v", paths)
+	}
+
+	def := swagger.Definitions["Resource0"]
+	names := map[string]bool{}
+	for name, prop := range def.AllProperties() {
+		names[name] = true
+		prop.Description = "changed"
+	}
+	if len(names) != len(def.Properties) {
+		t.Errorf("AllProperties() = %v, want %d properties", names, len(def.Properties))
+	}
+	for name, prop := range def.Properties {
+		if prop.Description == "changed" {
+			t.Errorf("AllProperties() should yield copies but %s was changed", name)
+		}
+	}
+	var none *Schema
+	for range none.AllProperties() {
+		t.Error("AllProperties() of a nil schema should yield nothing")
+	}
+}
@@ -0,0 +1,257 @@
+package spec
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fastjson"
+)
+
+// Literal is a default, enum or example value declared within the spec
+type Literal struct {
+	// Location is that of the value itself, such as .definitions.Pet.properties.age.default
+	Location string
+	// Field is the field declaring the value, which is one of default, enum or example
+	Field string
+	// Types are those declared for the value, where schemas may declare more than one or none
+	Types  []string
+	Format string
+	Value  *fastjson.Value
+	// Schema is that declaring the value or the equivalent of the parameter, header or items object declaring it
+	Schema *Schema
+}
+
+// Literals returns every default, enum and example value of the schemas, parameters, headers and items of the spec in
+// a deterministic order
+func (s *Swagger) Literals() []Literal {
+	if s == nil {
+		return nil
+	}
+	var (
+		a       fastjson.Arena
+		results []Literal
+	)
+	add := func(loc, field string, types []string, format string, schema *Schema, value any) {
+		if value == nil {
+			return
+		}
+		results = append(results, Literal{
+			Location: loc,
+			Field:    field,
+			Types:    types,
+			Format:   format,
+			Value:    marshalAny(&a, value),
+			Schema:   schema,
+		})
+	}
+	addEnum := func(loc string, types []string, format string, schema *Schema, enum []any) {
+		for i, value := range enum {
+			add(loc+".enum["+strconv.Itoa(i)+"]", "enum", types, format, schema, value)
+		}
+	}
+	var addItems func(loc string, items *Items)
+	addItems = func(loc string, items *Items) {
+		if items == nil {
+			return
+		}
+		types, schema := typeValues(items.Type), items.asSchema()
+		add(loc+".default", "default", types, items.Format, schema, items.Default)
+		addEnum(loc, types, items.Format, schema, items.Enum)
+		addItems(loc+".items", items.Items)
+	}
+	addParameter := func(loc string, p *Parameter) {
+		if p.In == InBody {
+			return
+		}
+		types, schema := typeValues(p.Type), p.asSchema()
+		add(loc+".default", "default", types, p.Format, schema, p.Default)
+		addEnum(loc, types, p.Format, schema, p.Enum)
+		addItems(loc+".items", p.Items)
+	}
+	addParameters := func(loc string, params []Parameter) {
+		for i := range params {
+			addParameter(loc+".parameters["+strconv.Itoa(i)+"]", &params[i])
+		}
+	}
+	addHeaders := func(loc string, r *Response) {
+		if r == nil {
+			return
+		}
+		for _, name := range sortedKeys(r.Headers) {
+			if h := r.Headers[name]; h != nil {
+				hLoc := loc + ".headers." + name
+				types, schema := typeValues(h.Type), h.asSchema()
+				add(hLoc+".default", "default", types, h.Format, schema, h.Default)
+				addEnum(hLoc, types, h.Format, schema, h.Enum)
+				addItems(hLoc+".items", h.Items)
+			}
+		}
+	}
+
+	s.walkSchemas(func(loc string, schema *Schema) {
+		types := schema.Type.Values()
+		add(loc+".default", "default", types, schema.Format, schema, schema.Default)
+		addEnum(loc, types, schema.Format, schema, schema.Enum)
+		add(loc+".example", "example", types, schema.Format, schema, schema.Example)
+	})
+	for _, name := range sortedKeys(s.Parameters) {
+		param := s.Parameters[name]
+		addParameter(".parameters."+name, &param)
+	}
+	for _, name := range sortedKeys(s.Responses) {
+		resp := s.Responses[name]
+		addHeaders(".responses."+name, &resp)
+	}
+	for _, path := range sortedKeys(s.Paths.Items) {
+		pi := s.Paths.Items[path]
+		if pi == nil {
+			continue
+		}
+		pathLoc := ".paths." + path
+		addParameters(pathLoc, pi.Parameters)
+		for _, method := range pathItemMethods {
+			op := pi.Operation(method)
+			if op == nil {
+				continue
+			}
+			opLoc := pathLoc + "." + strings.ToLower(method)
+			addParameters(opLoc, op.Parameters)
+			for _, code := range sortedStatusCodes(op.Responses.ByStatusCode) {
+				addHeaders(opLoc+".responses."+strconv.Itoa(code), op.Responses.ByStatusCode[code])
+			}
+			addHeaders(opLoc+".responses.default", op.Responses.Default)
+		}
+	}
+	return results
+}
+
+func typeValues(typ string) []string {
+	if typ == "" {
+		return nil
+	}
+	return []string{typ}
+}
+
+// asSchema returns the schema equivalent to the type and validations of the non-body parameter
+func (p *Parameter) asSchema() *Schema {
+	return &Schema{
+		Type:             NewStringOrStrings(typeValues(p.Type)...),
+		Format:           p.Format,
+		Items:            p.Items.asSchemaItems(),
+		Maximum:          p.Maximum,
+		ExclusiveMaximum: p.ExclusiveMaximum,
+		Minimum:          p.Minimum,
+		ExclusiveMinimum: p.ExclusiveMinimum,
+		MaxLength:        p.MaxLength,
+		MinLength:        p.MinLength,
+		Pattern:          p.Pattern,
+		MaxItems:         p.MaxItems,
+		MinItems:         p.MinItems,
+		UniqueItems:      p.UniqueItems,
+		Enum:             p.Enum,
+		MultipleOf:       p.MultipleOf,
+	}
+}
+
+// asSchema returns the schema equivalent to the type and validations of the header
+func (h *Header) asSchema() *Schema {
+	return &Schema{
+		Type:             NewStringOrStrings(typeValues(h.Type)...),
+		Format:           h.Format,
+		Items:            h.Items.asSchemaItems(),
+		Maximum:          h.Maximum,
+		ExclusiveMaximum: h.ExclusiveMaximum,
+		Minimum:          h.Minimum,
+		ExclusiveMinimum: h.ExclusiveMinimum,
+		MaxLength:        h.MaxLength,
+		MinLength:        h.MinLength,
+		Pattern:          h.Pattern,
+		MaxItems:         h.MaxItems,
+		MinItems:         h.MinItems,
+		UniqueItems:      h.UniqueItems,
+		Enum:             h.Enum,
+		MultipleOf:       h.MultipleOf,
+	}
+}
+
+// asSchema returns the schema equivalent to the type and validations of the items
+func (i *Items) asSchema() *Schema {
+	return &Schema{
+		Type:             NewStringOrStrings(typeValues(i.Type)...),
+		Format:           i.Format,
+		Items:            i.Items.asSchemaItems(),
+		Maximum:          i.Maximum,
+		ExclusiveMaximum: i.ExclusiveMaximum,
+		Minimum:          i.Minimum,
+		ExclusiveMinimum: i.ExclusiveMinimum,
+		MaxLength:        i.MaxLength,
+		MinLength:        i.MinLength,
+		Pattern:          i.Pattern,
+		MaxItems:         i.MaxItems,
+		MinItems:         i.MinItems,
+		UniqueItems:      i.UniqueItems,
+		Enum:             i.Enum,
+		MultipleOf:       i.MultipleOf,
+	}
+}
+
+func (i *Items) asSchemaItems() *SchemaOrSchemas {
+	if i == nil {
+		return nil
+	}
+	return NewSchemaOrSchemas(*i.asSchema())
+}
+
+// DecodeValue decodes a default, enum or example value of the spec, which is either a parsed JSON value or a Go value
+// set by the caller, into the value pointed to by target as encoding/json would
+func DecodeValue(value any, target any) error {
+	var a fastjson.Arena
+	return json.Unmarshal(marshalAny(&a, value).MarshalTo(nil), target)
+}
+
+// StringValue returns a default, enum or example value of the spec as a string and true if it is a JSON string
+func StringValue(value any) (string, bool) {
+	var a fastjson.Arena
+	v := marshalAny(&a, value)
+	if v.Type() != fastjson.TypeString {
+		return "", false
+	}
+	return string(v.GetStringBytes()), true
+}
+
+// NumberValue returns a default, enum or example value of the spec as a float64 and true if it is a JSON number
+func NumberValue(value any) (float64, bool) {
+	var a fastjson.Arena
+	v := marshalAny(&a, value)
+	if v.Type() != fastjson.TypeNumber {
+		return 0, false
+	}
+	return v.GetFloat64(), true
+}
+
+// IntValue returns a default, enum or example value of the spec as an int64 and true if it is a JSON number without
+// any fraction
+func IntValue(value any) (int64, bool) {
+	var a fastjson.Arena
+	i, err := marshalAny(&a, value).Int64()
+	return i, err == nil
+}
+
+// BoolValue returns a default, enum or example value of the spec as a bool and true if it is a JSON boolean
+func BoolValue(value any) (bool, bool) {
+	var a fastjson.Arena
+	b, err := marshalAny(&a, value).Bool()
+	return b, err == nil
+}
+
+// detachValue returns a copy of the parsed JSON value that does not share any memory with the input or the JSON
+// parser, so that it remains valid after either is reused
+func detachValue(v *fastjson.Value) *fastjson.Value {
+	copied, err := fastjson.ParseBytes(v.MarshalTo(nil))
+	if err != nil {
+		// a parsed value always marshals to valid JSON
+		return v
+	}
+	return copied
+}
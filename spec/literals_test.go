@@ -0,0 +1,123 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse_detachedLiterals(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"parameters": [{"name": "limit", "in": "query", "type": "integer", "default": 20}],
+					"responses": {"200": {"description": "ok", "headers": {"X-Rate": {"type": "boolean", "default": true}}}}
+				}
+			}
+		},
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"default": {"name": "Tom", "tags": ["cat"]},
+				"example": {"name": "Rex", "tags": ["dog"]}
+			}
+		}
+	}`
+	parser := NewParser([]byte(raw))
+	swagger, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	// reusing the released JSON parser for another spec of the same shape must not change the parsed values
+	parser.Release()
+	other := strings.NewReplacer("Tom", "Max", "Rex", "Zed", `"default": 20`, `"default": 99`, "true", "null").Replace(raw)
+	if _, err = NewParser([]byte(other)).Parse(); err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+
+	type pet struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	var got pet
+	if err = DecodeValue(swagger.Definitions["Pet"].Default, &got); err != nil {
+		t.Fatalf("DecodeValue() unexpected error: %s", err)
+	}
+	if expected := (pet{Name: "Tom", Tags: []string{"cat"}}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("DecodeValue(default) = %+v, want %+v", got, expected)
+	}
+	if err = DecodeValue(swagger.Definitions["Pet"].Example, &got); err != nil || got.Name != "Rex" {
+		t.Errorf("DecodeValue(example) = %+v, %v", got, err)
+	}
+	op := swagger.Paths.Items["/pets"].Get
+	if limit, ok := IntValue(op.Parameters[0].Default); !ok || limit != 20 {
+		t.Errorf("IntValue() = %d, %t, want 20", limit, ok)
+	}
+	if rate, ok := BoolValue(op.Responses.ByStatusCode[200].Headers["X-Rate"].Default); !ok || !rate {
+		t.Errorf("BoolValue() = %t, %t, want true", rate, ok)
+	}
+}
+
+func TestLiteralValues(t *testing.T) {
+	if s, ok := StringValue("cat"); !ok || s != "cat" {
+		t.Errorf("StringValue() = %s, %t", s, ok)
+	}
+	if _, ok := StringValue(3); ok {
+		t.Error("StringValue() of a number should not be ok")
+	}
+	if n, ok := NumberValue(2.5); !ok || n != 2.5 {
+		t.Errorf("NumberValue() = %v, %t", n, ok)
+	}
+	if _, ok := IntValue(2.5); ok {
+		t.Error("IntValue() of a fraction should not be ok")
+	}
+	if _, ok := BoolValue(nil); ok {
+		t.Error("BoolValue() of nil should not be ok")
+	}
+}
+
+func TestParse_WithDetachedValues(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0", "x-owner": {"team": "pets"}},
+		"paths": {
+			"/pets": {
+				"get": {
+					"x-rate-limit": 100,
+					"parameters": [{"name": "kind", "in": "query", "type": "string", "enum": ["cat", "dog"]}],
+					"responses": {"200": {"description": "ok"}}
+				}
+			}
+		},
+		"definitions": {
+			"Size": {"type": "string", "enum": ["small", "large"], "x-go-type": "Size"}
+		}
+	}`
+	parser := NewParser([]byte(raw), WithDetachedValues())
+	swagger, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	parser.Release()
+	other := strings.NewReplacer("pets", "toys", "100", "999", "cat", "owl", "small", "short", "Size", "Fits").Replace(raw)
+	if _, err = NewParser([]byte(other)).Parse(); err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+
+	op := swagger.Paths.Items["/pets"].Get
+	got := []string{
+		swagger.Info.Extensions["x-owner"].String(),
+		op.Extensions["x-rate-limit"].String(),
+		op.Parameters[0].Enum[0].(fmt.Stringer).String(),
+		swagger.Definitions["Size"].Enum[0].(fmt.Stringer).String(),
+		swagger.Definitions["Size"].Extensions["x-go-type"].String(),
+	}
+	expected := []string{`{"team":"pets"}`, `100`, `"cat"`, `"small"`, `"Size"`}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("detached values = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,133 @@
+package spec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LoadOptions configures LoadURL
+type LoadOptions struct {
+	// Client is used for the request or http.DefaultClient if nil
+	Client *http.Client
+	// Timeout limits the whole request when greater than zero
+	Timeout time.Duration
+	// Header is added to the request, such as for authorization
+	Header http.Header
+	// MaxSize limits the size in bytes of the response body when greater than zero
+	MaxSize int64
+	// Cache enables conditional requests using the ETag and Last-Modified of the previous response for the URL
+	Cache *URLCache
+	// ParserOptions configure the Parser of the response body
+	ParserOptions []ParserOption
+}
+
+// URLCache holds the last spec loaded from each URL by LoadURL, and is safe for concurrent use
+type URLCache struct {
+	mu      sync.Mutex
+	entries map[string]urlCacheEntry
+}
+
+type urlCacheEntry struct {
+	etag         string
+	lastModified string
+	swagger      *Swagger
+}
+
+// NewURLCache returns a new empty URLCache
+func NewURLCache() *URLCache {
+	return &URLCache{
+		entries: make(map[string]urlCacheEntry),
+	}
+}
+
+func (c *URLCache) get(url string) (urlCacheEntry, bool) {
+	if c == nil {
+		return urlCacheEntry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, exists := c.entries[url]
+	return entry, exists
+}
+
+func (c *URLCache) put(url string, entry urlCacheEntry) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[url] = entry
+}
+
+// LoadURL fetches and parses the swagger spec at the URL. When the cache holds a spec for the URL and the server
+// responds that it has not been modified, that same *Swagger is returned so callers may compare them to detect change.
+func LoadURL(ctx context.Context, url string, opts LoadOptions) (*Swagger, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, vals := range opts.Header {
+		for _, v := range vals {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	cached, isCached := opts.Cache.get(url)
+	if isCached {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && isCached:
+		return cached.swagger, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to load %s: unexpected status: %s", url, resp.Status)
+	}
+	body := io.Reader(resp.Body)
+	if opts.MaxSize > 0 {
+		body = io.LimitReader(resp.Body, opts.MaxSize+1)
+	}
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", url, err)
+	}
+	if opts.MaxSize > 0 && int64(len(raw)) > opts.MaxSize {
+		return nil, fmt.Errorf("%w: response from %s exceeds the max size of %d bytes", ErrLimitExceeded, url, opts.MaxSize)
+	}
+	swagger, err := NewParser(raw, opts.ParserOptions...).ParseContext(ctx)
+	if err != nil {
+		return swagger, err
+	}
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag != "" || lastModified != "" {
+		opts.Cache.put(url, urlCacheEntry{
+			etag:         etag,
+			lastModified: lastModified,
+			swagger:      swagger,
+		})
+	}
+	return swagger, nil
+}
@@ -0,0 +1,55 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoadURL(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"swagger": "2.0", "host": "example.com"}`))
+	}))
+	defer server.Close()
+
+	opts := LoadOptions{
+		Header: http.Header{"Authorization": {"Bearer token"}},
+		Cache:  NewURLCache(),
+	}
+	first, err := LoadURL(context.Background(), server.URL, opts)
+	if err != nil {
+		t.Fatalf("LoadURL() failed: %s", err)
+	}
+	if first.Host != "example.com" {
+		t.Errorf("Host = %q", first.Host)
+	}
+	second, err := LoadURL(context.Background(), server.URL, opts)
+	if err != nil {
+		t.Fatalf("LoadURL() failed: %s", err)
+	}
+	if second != first || requests != 2 {
+		t.Errorf("an unmodified spec should return the cached one after a conditional request")
+	}
+
+	opts.MaxSize = 10
+	opts.Cache = nil
+	if _, err = LoadURL(context.Background(), server.URL, opts); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("expected ErrLimitExceeded but got: %v", err)
+	}
+	if _, err = LoadURL(context.Background(), server.URL, LoadOptions{}); err == nil {
+		t.Error("LoadURL() without authorization should fail")
+	}
+}
@@ -0,0 +1,210 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/valyala/fastjson"
+)
+
+// setString sets the key to the string only if it is not empty
+func setString(a *fastjson.Arena, val *fastjson.Value, key, s string) {
+	if s != "" {
+		val.Set(key, a.NewString(s))
+	}
+}
+
+// setInt sets the key to the int only if it is set
+func setInt(a *fastjson.Arena, val *fastjson.Value, key string, i *int) {
+	if i != nil {
+		val.Set(key, a.NewNumberInt(*i))
+	}
+}
+
+// setFloat sets the key to the number only if it is set
+func setFloat(a *fastjson.Arena, val *fastjson.Value, key string, f *float64) {
+	if f != nil {
+		val.Set(key, a.NewNumberFloat64(*f))
+	}
+}
+
+// setBool sets the key to true only if it is true
+func setBool(a *fastjson.Arena, val *fastjson.Value, key string, b bool) {
+	if b {
+		val.Set(key, a.NewTrue())
+	}
+}
+
+// setStrings sets the key to an array of the strings only if there are any
+func setStrings(a *fastjson.Arena, val *fastjson.Value, key string, ss []string) {
+	if len(ss) > 0 {
+		val.Set(key, marshalStrings(a, ss))
+	}
+}
+
+// setAny sets the key to the value only if it is not nil
+func setAny(a *fastjson.Arena, val *fastjson.Value, key string, v any) {
+	if v != nil {
+		val.Set(key, marshalAny(a, v))
+	}
+}
+
+// setEnum sets the key to an array of the enum values only if there are any
+func setEnum(a *fastjson.Arena, val *fastjson.Value, key string, enum []any) {
+	if len(enum) == 0 {
+		return
+	}
+	arr := a.NewArray()
+	for i, v := range enum {
+		arr.SetArrayItem(i, marshalAny(a, v))
+	}
+	val.Set(key, arr)
+}
+
+func marshalStrings(a *fastjson.Arena, ss []string) *fastjson.Value {
+	arr := a.NewArray()
+	for i, s := range ss {
+		arr.SetArrayItem(i, a.NewString(s))
+	}
+	return arr
+}
+
+// marshalAny returns the JSON value of either a parsed *fastjson.Value or a Go value
+func marshalAny(a *fastjson.Arena, v any) *fastjson.Value {
+	switch t := v.(type) {
+	case nil:
+		return a.NewNull()
+	case *fastjson.Value:
+		if t == nil {
+			return a.NewNull()
+		}
+		return t
+	case string:
+		return a.NewString(t)
+	case bool:
+		if t {
+			return a.NewTrue()
+		}
+		return a.NewFalse()
+	case int:
+		return a.NewNumberInt(t)
+	case int64:
+		return a.NewNumberString(strconv.FormatInt(t, 10))
+	case float64:
+		return a.NewNumberFloat64(t)
+	case []any:
+		arr := a.NewArray()
+		for i := range t {
+			arr.SetArrayItem(i, marshalAny(a, t[i]))
+		}
+		return arr
+	case map[string]any:
+		obj := a.NewObject()
+		for _, k := range sortedKeys(t) {
+			obj.Set(k, marshalAny(a, t[k]))
+		}
+		return obj
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return a.NewString(fmt.Sprint(v))
+	}
+	parsed, err := fastjson.ParseBytes(raw)
+	if err != nil {
+		return a.NewString(fmt.Sprint(v))
+	}
+	return parsed
+}
+
+// sortedKeys returns the keys of the map in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// keyOrder maps the location of every JSON object in a parsed document to its keys in their authored order
+type keyOrder map[string][]string
+
+// recordKeyOrder returns the keyOrder of all objects within the value
+func recordKeyOrder(root *fastjson.Value) keyOrder {
+	order := make(keyOrder)
+	order.record(root, "")
+	return order
+}
+
+func (ko keyOrder) record(v *fastjson.Value, loc string) {
+	switch v.Type() {
+	case fastjson.TypeObject:
+		obj := v.GetObject()
+		keys := make([]string, 0, obj.Len())
+		obj.Visit(func(key []byte, child *fastjson.Value) {
+			k := string(key)
+			keys = append(keys, k)
+			ko.record(child, loc+"."+k)
+		})
+		ko[rootLoc(loc)] = keys
+	case fastjson.TypeArray:
+		for i, child := range v.GetArray() {
+			ko.record(child, loc+"["+strconv.Itoa(i)+"]")
+		}
+	}
+}
+
+// apply returns the value with the keys of each object reordered to match their authored order, where any keys not
+// authored follow in their existing order
+func (ko keyOrder) apply(a *fastjson.Arena, v *fastjson.Value, loc string) *fastjson.Value {
+	switch v.Type() {
+	case fastjson.TypeObject:
+		obj := v.GetObject()
+		keys := make([]string, 0, obj.Len())
+		children := make(map[string]*fastjson.Value, obj.Len())
+		obj.Visit(func(key []byte, child *fastjson.Value) {
+			k := string(key)
+			keys = append(keys, k)
+			children[k] = ko.apply(a, child, loc+"."+k)
+		})
+		if authored := ko[rootLoc(loc)]; len(authored) > 0 {
+			index := make(map[string]int, len(authored))
+			for i, k := range authored {
+				index[k] = i
+			}
+			sort.SliceStable(keys, func(i, j int) bool {
+				ii, iok := index[keys[i]]
+				ji, jok := index[keys[j]]
+				switch {
+				case iok && jok:
+					return ii < ji
+				case iok != jok:
+					return iok
+				}
+				return false
+			})
+		}
+		result := a.NewObject()
+		for _, k := range keys {
+			result.Set(k, children[k])
+		}
+		return result
+	case fastjson.TypeArray:
+		result := a.NewArray()
+		for i, child := range v.GetArray() {
+			result.SetArrayItem(i, ko.apply(a, child, loc+"["+strconv.Itoa(i)+"]"))
+		}
+		return result
+	}
+	return v
+}
+
+// rootLoc returns the location as used by the Parser where the root is "."
+func rootLoc(loc string) string {
+	if loc == "" {
+		return "."
+	}
+	return loc
+}
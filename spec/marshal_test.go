@@ -0,0 +1,173 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/valyala/fastjson"
+)
+
+func TestSwagger_MarshalJSON(t *testing.T) {
+	raw := `{
+		"x-owner": "pets-team",
+		"paths": {
+			"/pets/{id}": {
+				"parameters": [{"in": "path", "name": "id", "type": "string", "required": true}],
+				"get": {
+					"responses": {
+						"default": {"description": "error"},
+						"200": {"schema": {"$ref": "#/definitions/Pet"}, "description": "ok", "headers": {"X-Rate": {"type": "integer"}}}
+					},
+					"operationId": "getPet"
+				}
+			},
+			"/pets": {
+				"post": {
+					"parameters": [{"schema": {"$ref": "#/definitions/Pet"}, "name": "body", "in": "body"}],
+					"responses": {"201": {"description": "created"}},
+					"security": [{"oauth": ["write"]}]
+				}
+			}
+		},
+		"swagger": "2.0",
+		"info": {"version": "1.0", "title": "Pets"},
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string", "minLength": 1},
+					"kind": {"enum": ["cat", "dog"], "type": "string", "default": "cat"},
+					"age": {"type": "integer", "x-unit": {"b": 1, "a": [2, 3]}}
+				}
+			}
+		},
+		"securityDefinitions": {
+			"oauth": {"type": "oauth2", "flow": "implicit", "authorizationUrl": "https://example.com/auth", "scopes": {"write": "write pets", "read": "read pets"}}
+		}
+	}`
+	t.Run("should emit fields in authored order", func(t *testing.T) {
+		swagger, err := NewParser([]byte(raw), WithKeyOrder()).Parse()
+		if err != nil {
+			t.Fatalf("failed to parse: %s", err)
+		}
+		got, err := swagger.MarshalJSON()
+		if err != nil {
+			t.Fatalf("failed to marshal: %s", err)
+		}
+		var expected bytes.Buffer
+		if err = json.Compact(&expected, []byte(raw)); err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != expected.String() {
+			t.Errorf("MarshalJSON() =\n%s\nwant\n%s", got, expected.String())
+		}
+	})
+	t.Run("should emit fields in specification order", func(t *testing.T) {
+		swagger, err := NewParser([]byte(raw)).Parse()
+		if err != nil {
+			t.Fatalf("failed to parse: %s", err)
+		}
+		got, err := swagger.MarshalJSON()
+		if err != nil {
+			t.Fatalf("failed to marshal: %s", err)
+		}
+		expected := `{"swagger":"2.0","info":{"title":"Pets","version":"1.0"},"paths":{` +
+			`"/pets":{"post":{"parameters":[{"name":"body","in":"body","schema":{"$ref":"#/definitions/Pet"}}],"responses":{"201":{"description":"created"}},"security":[{"oauth":["write"]}]}},` +
+			`"/pets/{id}":{"get":{"operationId":"getPet","responses":{"200":{"description":"ok","schema":{"$ref":"#/definitions/Pet"},"headers":{"X-Rate":{"type":"integer"}}},"default":{"description":"error"}}},"parameters":[{"name":"id","in":"path","required":true,"type":"string"}]}},` +
+			`"definitions":{"Pet":{"type":"object","required":["name"],"properties":{"age":{"type":"integer","x-unit":{"b":1,"a":[2,3]}},"kind":{"type":"string","default":"cat","enum":["cat","dog"]},"name":{"type":"string","minLength":1}}}},` +
+			`"securityDefinitions":{"oauth":{"type":"oauth2","flow":"implicit","authorizationUrl":"https://example.com/auth","scopes":{"read":"read pets","write":"write pets"}}},` +
+			`"x-owner":"pets-team"}`
+		if string(got) != expected {
+			t.Errorf("MarshalJSON() =\n%s\nwant\n%s", got, expected)
+		}
+		reparsed, err := NewParser(got).Parse()
+		if err != nil {
+			t.Fatalf("failed to parse marshalled spec: %s", err)
+		}
+		if again, _ := reparsed.MarshalJSON(); string(again) != expected {
+			t.Errorf("MarshalJSON() of reparsed spec =\n%s\nwant\n%s", again, expected)
+		}
+	})
+}
+
+func TestSwagger_fractionalConstraints(t *testing.T) {
+	raw := `{"swagger":"2.0","info":{"title":"test","version":"1.0"},"paths":{"/weights":{"get":{"parameters":[{"name":"limit","in":"query","type":"number","maximum":2.5,"minimum":-0.5,"exclusiveMinimum":true}],"responses":{"200":{"description":"ok"}}}}},"definitions":{"Ratio":{"type":"number","multipleOf":0.1,"maximum":1,"minimum":0.25}}}`
+	swagger, err := NewParser([]byte(raw), WithKeyOrder()).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	ratio := swagger.Definitions["Ratio"]
+	if *ratio.MultipleOf != 0.1 || *ratio.Maximum != 1 || *ratio.Minimum != 0.25 {
+		t.Errorf("Ratio = multipleOf %v, maximum %v, minimum %v", *ratio.MultipleOf, *ratio.Maximum, *ratio.Minimum)
+	}
+	if param := swagger.Paths.Items["/weights"].Get.Parameters[0]; *param.Maximum != 2.5 || *param.Minimum != -0.5 {
+		t.Errorf("limit = maximum %v, minimum %v", *param.Maximum, *param.Minimum)
+	}
+	tests := map[string][]string{
+		"0.3":  nil,
+		"1":    nil,
+		"0.35": {"value: 0.35 is not a multiple of 0.1"},
+		"0.2":  {"value: 0.2 is below the minimum of 0.25"},
+		"1.1":  {"value: 1.1 exceeds the maximum of 1"},
+	}
+	for value, expected := range tests {
+		var got []string
+		for _, e := range swagger.ValidateValue(&ratio, fastjson.MustParse(value)) {
+			got = append(got, e.Error())
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("ValidateValue(%s) = %v, want %v", value, got, expected)
+		}
+	}
+	got, err := swagger.MarshalJSON()
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	if string(got) != raw {
+		t.Errorf("MarshalJSON() =\n%s\nwant\n%s", got, raw)
+	}
+}
+
+func TestSwagger_zeroConstraints(t *testing.T) {
+	raw := `{"swagger":"2.0","info":{"title":"test","version":"1.0"},"paths":{},"definitions":{"Count":{"type":"integer","maximum":10,"minimum":0},"Empty":{"type":"string","maxLength":0},"Any":{"type":"integer"}}}`
+	swagger, err := NewParser([]byte(raw), WithKeyOrder()).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	count, anyInt := swagger.Definitions["Count"], swagger.Definitions["Any"]
+	if count.Minimum == nil || *count.Minimum != 0 {
+		t.Errorf("Count.Minimum = %v, want 0", count.Minimum)
+	}
+	if anyInt.Minimum != nil || anyInt.Maximum != nil {
+		t.Errorf("Any = minimum %v, maximum %v, want unset", anyInt.Minimum, anyInt.Maximum)
+	}
+	tests := []struct {
+		definition string
+		value      string
+		expected   []string
+	}{
+		{"Count", "-1", []string{"value: -1 is below the minimum of 0"}},
+		{"Any", "-1", nil},
+		{"Empty", `"a"`, []string{"value: length of 1 exceeds the maxLength of 0"}},
+	}
+	for _, tt := range tests {
+		def := swagger.Definitions[tt.definition]
+		var got []string
+		for _, e := range swagger.ValidateValue(&def, fastjson.MustParse(tt.value)) {
+			got = append(got, e.Error())
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ValidateValue(%s, %s) = %v, want %v", tt.definition, tt.value, got, tt.expected)
+		}
+	}
+	got, err := swagger.MarshalJSON()
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	if string(got) != raw {
+		t.Errorf("MarshalJSON() =\n%s\nwant\n%s", got, raw)
+	}
+}
@@ -0,0 +1,110 @@
+package spec
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Matches returns true if the method and concrete path, such as GET /pets/123, match the method and templated path of
+// this key, such as GET /pets/{petId}
+func (k OperationKey) Matches(method, concretePath string) bool {
+	if !strings.EqualFold(k.Method, method) {
+		return false
+	}
+	_, ok := matchTemplate(k.Path, concretePath, nil)
+	return ok
+}
+
+// PathParams returns the values of the path parameters of the templated path of this key from the concrete path and
+// true only if it matches
+func (k OperationKey) PathParams(concretePath string) (map[string]string, bool) {
+	params := make(map[string]string)
+	if _, ok := matchTemplate(k.Path, concretePath, params); !ok {
+		return nil, false
+	}
+	return params, true
+}
+
+// FindOperation returns the operation matching the method and concrete request path, which includes any base path of
+// this spec, along with the values of its path parameters. When more than one operation matches, the one with the most
+// literal path segments is preferred so that /pets/mine matches before /pets/{petId}. The operations are indexed by
+// a route tree built on first use, so any changes to their keys other than by AddOperation or RemoveOperation require
+// calling InvalidateRoutes.
+func (s *Swagger) FindOperation(method, requestPath string) (*Operation, map[string]string, bool) {
+	if s == nil {
+		return nil, nil, false
+	}
+	if i := strings.IndexAny(requestPath, "?#"); i >= 0 {
+		requestPath = requestPath[:i]
+	}
+	if basePath := strings.TrimSuffix(s.BasePath, "/"); basePath != "" {
+		if requestPath != basePath && !strings.HasPrefix(requestPath, basePath+"/") {
+			return nil, nil, false
+		}
+		requestPath = requestPath[len(basePath):]
+	}
+	best, _ := s.routeTree().find(strings.ToUpper(method), splitPath(requestPath))
+	if best == nil {
+		return nil, nil, false
+	}
+	params, _ := best.Key.PathParams(requestPath)
+	return best, params, true
+}
+
+// matchTemplate returns the count of literal segments and true if the concrete path matches the templated path, adding
+// the unescaped values of the path parameters to params when not nil
+func matchTemplate(template, path string, params map[string]string) (int, bool) {
+	tSegs := splitPath(template)
+	pSegs := splitPath(path)
+	if len(tSegs) != len(pSegs) {
+		return 0, false
+	}
+	var literals int
+	for i, tSeg := range tSegs {
+		prefix, name, suffix, templated := templateSegment(tSeg)
+		if !templated {
+			if tSeg != pSegs[i] {
+				return 0, false
+			}
+			literals++
+			continue
+		}
+		value, ok := matchSegment(prefix, suffix, pSegs[i])
+		if !ok {
+			return 0, false
+		}
+		if params != nil {
+			params[name] = value
+		}
+	}
+	return literals, true
+}
+
+// splitPath returns the segments of the path ignoring any leading or trailing slash
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// templateSegment returns the literal prefix and suffix around the path parameter of the segment along with its name,
+// as a template may only be part of the segment such as {name}.json, and false if the segment is not templated
+func templateSegment(seg string) (prefix, name, suffix string, templated bool) {
+	start := strings.IndexByte(seg, '{')
+	end := strings.LastIndexByte(seg, '}')
+	if start < 0 || end < start {
+		return "", "", "", false
+	}
+	return seg[:start], seg[start+1 : end], seg[end+1:], true
+}
+
+// matchSegment returns the unescaped non-empty value between the prefix and suffix of the concrete segment and true
+// only if it has both
+func matchSegment(prefix, suffix, seg string) (string, bool) {
+	if len(seg) <= len(prefix)+len(suffix) || !strings.HasPrefix(seg, prefix) || !strings.HasSuffix(seg, suffix) {
+		return "", false
+	}
+	value := seg[len(prefix) : len(seg)-len(suffix)]
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		value = unescaped
+	}
+	return value, true
+}
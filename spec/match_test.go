@@ -0,0 +1,149 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestOperationKey_Matches(t *testing.T) {
+	key := OperationKey{Path: "/pets/{petId}/files/{name}.json", Method: "GET"}
+	tests := map[string]struct {
+		method   string
+		path     string
+		expected map[string]string
+	}{
+		"concrete path should match": {
+			method:   "get",
+			path:     "/pets/123/files/photo%20one.json",
+			expected: map[string]string{"petId": "123", "name": "photo one"},
+		},
+		"different method should not match": {
+			method: "POST",
+			path:   "/pets/123/files/photo.json",
+		},
+		"missing suffix should not match": {
+			method: "GET",
+			path:   "/pets/123/files/photo.xml",
+		},
+		"empty parameter should not match": {
+			method: "GET",
+			path:   "/pets//files/photo.json",
+		},
+		"extra segments should not match": {
+			method: "GET",
+			path:   "/pets/123/files/photo.json/raw",
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			if got := key.Matches(tt.method, tt.path); got != (tt.expected != nil) {
+				t.Errorf("Matches() = %t", got)
+			}
+			if tt.expected != nil {
+				if params, _ := key.PathParams(tt.path); !reflect.DeepEqual(params, tt.expected) {
+					t.Errorf("PathParams() = %v, want %v", params, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestSwagger_FindOperation(t *testing.T) {
+	swagger, err := NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"basePath": "/v1",
+		"paths": {
+			"/pets/{petId}": {"get": {"responses": {"200": {"description": "ok"}}}},
+			"/pets/mine": {"get": {"responses": {"200": {"description": "ok"}}}},
+			"/pets": {"post": {"responses": {"201": {"description": "created"}}}}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	tests := map[string]struct {
+		method   string
+		path     string
+		expected string
+		params   map[string]string
+	}{
+		"templated path should match": {
+			method:   "GET",
+			path:     "/v1/pets/42?verbose=true",
+			expected: "/pets/{petId}",
+			params:   map[string]string{"petId": "42"},
+		},
+		"literal path should be preferred": {
+			method:   "get",
+			path:     "/v1/pets/mine",
+			expected: "/pets/mine",
+			params:   map[string]string{},
+		},
+		"path without the base path should not match": {
+			method: "GET",
+			path:   "/pets/42",
+		},
+		"path with a longer base path should not match": {
+			method: "POST",
+			path:   "/v10/pets",
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			op, params, found := swagger.FindOperation(tt.method, tt.path)
+			if !found {
+				if tt.expected != "" {
+					t.Errorf("FindOperation() found nothing, want %s", tt.expected)
+				}
+				return
+			}
+			if op.Key.Path != tt.expected {
+				t.Errorf("FindOperation() = %s, want %s", op.Key.Path, tt.expected)
+			}
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("FindOperation() params = %v, want %v", params, tt.params)
+			}
+		})
+	}
+}
+
+func TestSwagger_FindOperation_Routes(t *testing.T) {
+	swagger := NewSwagger()
+	swagger.AddOperation(NewOperation("/pets/{petId}", "GET"))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, found := swagger.FindOperation("GET", "/pets/1"); !found {
+				t.Error("FindOperation() found nothing")
+			}
+		}()
+	}
+	wg.Wait()
+
+	swagger.AddOperation(NewOperation("/pets/mine", "GET"))
+	if op, _, _ := swagger.FindOperation("GET", "/pets/mine"); op == nil || op.Key.Path != "/pets/mine" {
+		t.Errorf("FindOperation() should find an added operation but got %v", op)
+	}
+	swagger.RemoveOperation(OperationKey{Path: "/pets/mine", Method: "GET"})
+	if op, _, _ := swagger.FindOperation("GET", "/pets/mine"); op == nil || op.Key.Path != "/pets/{petId}" {
+		t.Errorf("FindOperation() should not find a removed operation but got %v", op)
+	}
+}
+
+func BenchmarkSwagger_FindOperation(b *testing.B) {
+	swagger := NewSwagger()
+	for i := 0; i < 1000; i++ {
+		swagger.AddOperation(NewOperation(fmt.Sprintf("/resource%d/{id}/items/{itemId}", i), "GET"))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, found := swagger.FindOperation("GET", "/resource999/42/items/7"); !found {
+			b.Fatal("FindOperation() found nothing")
+		}
+	}
+}
@@ -3,6 +3,7 @@ package spec
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
 
@@ -39,19 +40,235 @@ func NewOperation(path string, method string) *Operation {
 	}
 }
 
+// Clone returns a copy of this Operation with its own slices and maps, though any nested objects such as schemas
+// are shared with the original
+func (o *Operation) Clone() *Operation {
+	if o == nil {
+		return nil
+	}
+	result := *o
+	result.Extensions = make(Extensions, len(o.Extensions))
+	for k, v := range o.Extensions {
+		result.Extensions[k] = v
+	}
+	result.Tags = append([]string(nil), o.Tags...)
+	result.Consumes = append([]string(nil), o.Consumes...)
+	result.Produces = append([]string(nil), o.Produces...)
+	result.Schemes = append([]string(nil), o.Schemes...)
+	result.Parameters = append([]Parameter(nil), o.Parameters...)
+	if o.Security != nil {
+		// an empty slice is kept as it explicitly declares no security
+		result.Security = append(make([]SecurityRequirements, 0, len(o.Security)), o.Security...)
+	}
+	if o.Responses.ByStatusCode != nil {
+		result.Responses.ByStatusCode = make(map[int]*Response, len(o.Responses.ByStatusCode))
+		for code, r := range o.Responses.ByStatusCode {
+			result.Responses.ByStatusCode[code] = r
+		}
+	}
+	return &result
+}
+
+// ReferencedDefinitions returns the definitions directly referenced by the schemas of the parameters and responses of
+// this operation
 func (o *Operation) ReferencedDefinitions() *UniqueDefinitionRefs {
+	return o.referencedDefinitions(nil)
+}
+
+// ResolvedReferencedDefinitions returns the definitions like ReferencedDefinitions, though any parameter referring to
+// one of the parameters of the swagger spec is replaced by the parameter it refers to
+func (o *Operation) ResolvedReferencedDefinitions(swagger *Swagger) *UniqueDefinitionRefs {
+	return o.referencedDefinitions(globalParameters(swagger))
+}
+
+func (o *Operation) referencedDefinitions(globalParams map[string]Parameter) *UniqueDefinitionRefs {
 	if o == nil {
 		return nil
 	}
 
 	result := NewUniqueDefinitionRefs(len(o.Parameters) + len(o.Responses.ByStatusCode))
 	for _, param := range o.Parameters {
+		param = resolveParameter(param, globalParams)
 		result = result.Merge(param.Schema.ReferencedDefinitions())
 	}
+	codes := make([]int, 0, len(o.Responses.ByStatusCode))
+	for code := range o.Responses.ByStatusCode {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		if r := o.Responses.ByStatusCode[code]; r != nil {
+			result = result.Merge(r.Schema.ReferencedDefinitions())
+		}
+	}
+	if r := o.Responses.Default; r != nil {
+		result = result.Merge(r.Schema.ReferencedDefinitions())
+	}
 
 	return result
 }
 
+// ParametersIn returns the parameters of this operation in the location, not including those of its PathItem
+func (o *Operation) ParametersIn(in In) []Parameter {
+	return o.parametersIn(in, nil)
+}
+
+// ResolvedParametersIn returns the parameters like ParametersIn, though any parameter referring to one of the
+// parameters of the swagger spec is replaced by the parameter it refers to
+func (o *Operation) ResolvedParametersIn(in In, swagger *Swagger) []Parameter {
+	return o.parametersIn(in, globalParameters(swagger))
+}
+
+func (o *Operation) parametersIn(in In, globalParams map[string]Parameter) []Parameter {
+	if o == nil {
+		return nil
+	}
+	var results []Parameter
+	for _, p := range o.Parameters {
+		if p = resolveParameter(p, globalParams); p.In == in {
+			results = append(results, p)
+		}
+	}
+	return results
+}
+
+// EffectiveParameters returns the parameters that apply to this operation, which are its own followed by those of the
+// PathItem it does not override. A parameter overrides another with the same name and location, and any reference to
+// one of the globalParams is replaced by the parameter it refers to before comparing them. References that cannot be
+// resolved are kept as they are.
+func (o *Operation) EffectiveParameters(pathItem *PathItem, globalParams map[string]Parameter) []Parameter {
+	if o == nil {
+		return nil
+	}
+	type paramKey struct {
+		name string
+		in   In
+	}
+	var results []Parameter
+	seen := make(map[paramKey]struct{}, len(o.Parameters))
+	for _, p := range o.Parameters {
+		p = resolveParameter(p, globalParams)
+		if p.Ref == nil {
+			seen[paramKey{p.Name, p.In}] = struct{}{}
+		}
+		results = append(results, p)
+	}
+	if pathItem == nil {
+		return results
+	}
+	for _, p := range pathItem.Parameters {
+		p = resolveParameter(p, globalParams)
+		if p.Ref == nil {
+			if _, overridden := seen[paramKey{p.Name, p.In}]; overridden {
+				continue
+			}
+		}
+		results = append(results, p)
+	}
+	return results
+}
+
+// EffectiveSecurity returns the security requirements that apply to this operation. Any requirements declared by the
+// operation, including an empty list declaring that no security applies, override those of the swagger spec.
+func (o *Operation) EffectiveSecurity(swagger *Swagger) []SecurityRequirements {
+	if o == nil {
+		return nil
+	}
+	if o.Security != nil || swagger == nil {
+		return o.Security
+	}
+	return swagger.Security
+}
+
+// BodyParameter returns the body parameter of this operation or nil if there is none
+func (o *Operation) BodyParameter() *Parameter {
+	return o.bodyParameter(nil)
+}
+
+// ResolvedBodyParameter returns the body parameter like BodyParameter, though a parameter referring to one of the
+// parameters of the swagger spec is replaced by a copy of the parameter it refers to
+func (o *Operation) ResolvedBodyParameter(swagger *Swagger) *Parameter {
+	return o.bodyParameter(globalParameters(swagger))
+}
+
+func (o *Operation) bodyParameter(globalParams map[string]Parameter) *Parameter {
+	if o == nil {
+		return nil
+	}
+	for i := range o.Parameters {
+		if o.Parameters[i].In == InBody {
+			return &o.Parameters[i]
+		}
+		if p := resolveParameter(o.Parameters[i], globalParams); p.In == InBody {
+			return &p
+		}
+	}
+	return nil
+}
+
+// PathParameters returns the path parameters of this operation
+func (o *Operation) PathParameters() []Parameter {
+	return o.ParametersIn(InPath)
+}
+
+// ResolvedPathParameters returns the path parameters of this operation, resolving references to the parameters of
+// the swagger spec
+func (o *Operation) ResolvedPathParameters(swagger *Swagger) []Parameter {
+	return o.ResolvedParametersIn(InPath, swagger)
+}
+
+// QueryParameters returns the query parameters of this operation
+func (o *Operation) QueryParameters() []Parameter {
+	return o.ParametersIn(InQuery)
+}
+
+// ResolvedQueryParameters returns the query parameters of this operation, resolving references to the parameters of
+// the swagger spec
+func (o *Operation) ResolvedQueryParameters(swagger *Swagger) []Parameter {
+	return o.ResolvedParametersIn(InQuery, swagger)
+}
+
+// HeaderParameters returns the header parameters of this operation
+func (o *Operation) HeaderParameters() []Parameter {
+	return o.ParametersIn(InHeader)
+}
+
+// ResolvedHeaderParameters returns the header parameters of this operation, resolving references to the parameters
+// of the swagger spec
+func (o *Operation) ResolvedHeaderParameters(swagger *Swagger) []Parameter {
+	return o.ResolvedParametersIn(InHeader, swagger)
+}
+
+// FormDataParameters returns the form parameters of this operation
+func (o *Operation) FormDataParameters() []Parameter {
+	return o.ParametersIn(InFormData)
+}
+
+// ResolvedFormDataParameters returns the form parameters of this operation, resolving references to the parameters of
+// the swagger spec
+func (o *Operation) ResolvedFormDataParameters(swagger *Swagger) []Parameter {
+	return o.ResolvedParametersIn(InFormData, swagger)
+}
+
+// globalParameters returns the parameters of the swagger spec, which is nil for a nil spec
+func globalParameters(swagger *Swagger) map[string]Parameter {
+	if swagger == nil {
+		return nil
+	}
+	return swagger.Parameters
+}
+
+// resolveParameter returns the parameter of the globalParams the parameter refers to, or else the parameter itself
+// when it is not a reference or its reference cannot be resolved
+func resolveParameter(p Parameter, globalParams map[string]Parameter) Parameter {
+	if name, ok := p.Ref.ParameterName(); ok {
+		if global, exists := globalParams[name]; exists {
+			return global
+		}
+	}
+	return p
+}
+
 // OperationKey defines the natural key for any swagger Operation
 type OperationKey struct {
 	Path   string
@@ -66,30 +283,116 @@ func (k OperationKey) Canonicalize() OperationKey {
 	}
 }
 
+// Location returns the location of the operation within the swagger spec in the same form used by ParseError
+func (k OperationKey) Location() string {
+	return fmt.Sprintf(".paths.%s.%s", k.Path, strings.ToLower(k.Method))
+}
+
 // Operations defines a slice of Operation objects
 type Operations []*Operation
 
-// Sorted returns a sorted slice of Operation objects
+// Sorted returns the operations sorted by path then method
 func (ops Operations) Sorted() Operations {
 	if len(ops) == 0 {
 		return ops
 	}
 	sort.Slice(ops, func(i, j int) bool {
 		oi, oj := ops[i], ops[j]
-		if oi.Key.Path < oj.Key.Path {
-			return true
-		}
-		if oi.Key.Path > oj.Key.Path {
-			return false
+		if oi.Key.Path != oj.Key.Path {
+			return oi.Key.Path < oj.Key.Path
 		}
-		if oi.Key.Method < oj.Key.Method {
-			return true
+		return oi.Key.Method < oj.Key.Method
+	})
+	return ops
+}
+
+// SortKey defines a key by which operations are sorted
+type SortKey int
+
+const (
+	// SortByPath sorts by the path of each operation
+	SortByPath SortKey = iota
+	// SortByMethod sorts by the method of each operation in the order GET, POST, PUT, PATCH, DELETE, HEAD then
+	// OPTIONS, followed by any other methods in alphabetical order
+	SortByMethod
+	// SortByOperationID sorts by the operationId of each operation, where those without one are last
+	SortByOperationID
+	// SortByTag sorts by the first tag of each operation, where those without any tags are last
+	SortByTag
+)
+
+// methodOrder is the rank of each method when sorting by SortByMethod
+var methodOrder = map[string]int{
+	http.MethodGet:     0,
+	http.MethodPost:    1,
+	http.MethodPut:     2,
+	http.MethodPatch:   3,
+	http.MethodDelete:  4,
+	http.MethodHead:    5,
+	http.MethodOptions: 6,
+}
+
+// SortBy stably sorts the operations by the keys in order of precedence, so operations equal by every key keep their
+// current order, and returns them
+func (ops Operations) SortBy(keys ...SortKey) Operations {
+	sort.SliceStable(ops, func(i, j int) bool {
+		for _, key := range keys {
+			if c := compareOperations(ops[i], ops[j], key); c != 0 {
+				return c < 0
+			}
 		}
-		return oi.Key.Method > oj.Key.Method
+		return false
 	})
 	return ops
 }
 
+// compareOperations returns a negative number when a is before b by the key, a positive number when it is after and
+// zero when they are equal
+func compareOperations(a, b *Operation, key SortKey) int {
+	switch key {
+	case SortByPath:
+		return strings.Compare(a.Key.Path, b.Key.Path)
+	case SortByMethod:
+		ma, mb := strings.ToUpper(a.Key.Method), strings.ToUpper(b.Key.Method)
+		ra, knownA := methodOrder[ma]
+		rb, knownB := methodOrder[mb]
+		switch {
+		case knownA && knownB:
+			return ra - rb
+		case knownA:
+			return -1
+		case knownB:
+			return 1
+		}
+		return strings.Compare(ma, mb)
+	case SortByOperationID:
+		return compareLastIfEmpty(a.ID, b.ID)
+	case SortByTag:
+		var ta, tb string
+		if len(a.Tags) > 0 {
+			ta = a.Tags[0]
+		}
+		if len(b.Tags) > 0 {
+			tb = b.Tags[0]
+		}
+		return compareLastIfEmpty(ta, tb)
+	}
+	return 0
+}
+
+// compareLastIfEmpty compares the strings where an empty string is after any other
+func compareLastIfEmpty(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
 // OperationMap defines a mapping of Operation objects by each natural OperationKey
 type OperationMap map[OperationKey]*Operation
 
@@ -107,27 +410,26 @@ func (om OperationMap) Sorted() Operations {
 }
 
 func parseOperation(val *fastjson.Value, parser *Parser, path string, method string) *Operation {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid operation value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid operation value: %w", err))
 		return nil
 	}
 	result := NewOperation(path, method)
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "operationId"):
 			parser.parseAndValidateString(v, "operationId", func(id string) error {
 				if id == "" {
-					return errors.New("empty operationId")
+					return newValidationError(ErrorCodeEmptyValue, errors.New("empty operationId"))
 				}
 				if other, unique := parser.locationForOperation(id); !unique {
-					return fmt.Errorf("duplicated operationID[%s]: also in: %s", id, other)
+					return newValidationError(ErrorCodeDuplicateOperationID, fmt.Errorf("duplicated operationID[%s]: also in: %s", id, other))
 				}
 				result.ID = id
 				return nil
@@ -146,11 +448,11 @@ func parseOperation(val *fastjson.Value, parser *Parser, path string, method str
 			})
 		case matchString(key, "tags"):
 			if tags, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid tags value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid tags value: %w", e))
 			} else {
-				tagsLoc := parser.currentLoc
+				tagsLoc := parser.mark()
 				for i, tVal := range tags {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", tagsLoc, i)
+					parser.atIndex(tagsLoc, i)
 					parser.parseString(tVal, "tags item", true, func(s string) {
 						result.Tags = append(result.Tags, s)
 					})
@@ -158,11 +460,11 @@ func parseOperation(val *fastjson.Value, parser *Parser, path string, method str
 			}
 		case matchString(key, "consumes"):
 			if consumes, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid consumes value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid consumes value: %w", e))
 			} else {
-				consumesLoc := parser.currentLoc
+				consumesLoc := parser.mark()
 				for i, cVal := range consumes {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", consumesLoc, i)
+					parser.atIndex(consumesLoc, i)
 					parser.parseString(cVal, "consumes item", true, func(s string) {
 						result.Consumes = append(result.Consumes, s)
 					})
@@ -170,11 +472,11 @@ func parseOperation(val *fastjson.Value, parser *Parser, path string, method str
 			}
 		case matchString(key, "produces"):
 			if produces, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid produces value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid produces value: %w", e))
 			} else {
-				producesLoc := parser.currentLoc
+				producesLoc := parser.mark()
 				for i, pVal := range produces {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", producesLoc, i)
+					parser.atIndex(producesLoc, i)
 					parser.parseString(pVal, "produces item", true, func(s string) {
 						result.Produces = append(result.Produces, s)
 					})
@@ -182,11 +484,11 @@ func parseOperation(val *fastjson.Value, parser *Parser, path string, method str
 			}
 		case matchString(key, "schemes"):
 			if schemes, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid schemes value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid schemes value: %w", e))
 			} else {
-				schemesLoc := parser.currentLoc
+				schemesLoc := parser.mark()
 				for i, sVal := range schemes {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", schemesLoc, i)
+					parser.atIndex(schemesLoc, i)
 					parser.parseString(sVal, "schemes item", true, func(s string) {
 						result.Schemes = append(result.Schemes, s)
 					})
@@ -194,11 +496,11 @@ func parseOperation(val *fastjson.Value, parser *Parser, path string, method str
 			}
 		case matchString(key, "parameters"):
 			if vals, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid parameters value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid parameters value: %w", e))
 			} else {
-				paramsLoc := parser.currentLoc
+				paramsLoc := parser.mark()
 				for i, paramVal := range vals {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", paramsLoc, i)
+					parser.atIndex(paramsLoc, i)
 					if p := parseParameter(paramVal, parser); p != nil {
 						result.Parameters = append(result.Parameters, *p)
 					}
@@ -210,11 +512,13 @@ func parseOperation(val *fastjson.Value, parser *Parser, path string, method str
 			}
 		case matchString(key, "security"):
 			if vals, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid security value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid security value: %w", e))
 			} else {
-				secLoc := parser.currentLoc
+				// an empty array is kept as it explicitly declares no security
+				result.Security = make([]SecurityRequirements, 0, len(vals))
+				secLoc := parser.mark()
 				for i, secVal := range vals {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", secLoc, i)
+					parser.atIndex(secLoc, i)
 					if sec := parseSecurityRequirements(secVal, parser); sec != nil {
 						result.Security = append(result.Security, sec)
 					}
@@ -223,13 +527,38 @@ func parseOperation(val *fastjson.Value, parser *Parser, path string, method str
 		case matchString(key, "externalDocs"):
 			result.ExternalDocumentation = parseExternalDocumentation(v, parser)
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
-	// store this in our swagger's operations map
-	parser.swagger.addOperation(result)
+	// store this in our swagger's operations map from the location of the operation itself
+	parser.reset(fromLoc)
+	parser.addOperation(result)
 
 	return result
 }
+
+func (o *Operation) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setStrings(a, val, "tags", o.Tags)
+	setString(a, val, "summary", o.Summary)
+	setString(a, val, "description", o.Description)
+	if o.ExternalDocumentation != nil {
+		val.Set("externalDocs", o.ExternalDocumentation.marshal(a))
+	}
+	setString(a, val, "operationId", o.ID)
+	setStrings(a, val, "consumes", o.Consumes)
+	setStrings(a, val, "produces", o.Produces)
+	if len(o.Parameters) > 0 {
+		val.Set("parameters", marshalParameters(a, o.Parameters))
+	}
+	val.Set("responses", o.Responses.marshal(a))
+	setStrings(a, val, "schemes", o.Schemes)
+	setBool(a, val, "deprecated", o.Deprecated)
+	if o.Security != nil {
+		val.Set("security", marshalSecurity(a, o.Security))
+	}
+	o.marshalExtensions(val)
+	return val
+}
@@ -0,0 +1,364 @@
+package spec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOperation_Parameters(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets/{petId}": {
+				"put": {
+					"parameters": [
+						{"name": "petId", "in": "path", "required": true, "type": "string"},
+						{"name": "dryRun", "in": "query", "type": "boolean"},
+						{"name": "pet", "in": "body", "schema": {"type": "object"}},
+						{"name": "X-Trace", "in": "header", "type": "string"}
+					],
+					"responses": {"200": {"description": "ok"}}
+				}
+			}
+		}
+	}`
+	swagger, err := NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	op := swagger.Paths.Items["/pets/{petId}"].Put
+	if body := op.BodyParameter(); body == nil || body.Name != "pet" {
+		t.Errorf("BodyParameter() = %v", body)
+	}
+	if params := op.PathParameters(); len(params) != 1 || params[0].Name != "petId" {
+		t.Errorf("PathParameters() = %v", params)
+	}
+	if params := op.QueryParameters(); len(params) != 1 || params[0].Name != "dryRun" {
+		t.Errorf("QueryParameters() = %v", params)
+	}
+	if params := op.HeaderParameters(); len(params) != 1 || params[0].Name != "X-Trace" {
+		t.Errorf("HeaderParameters() = %v", params)
+	}
+	if params := op.FormDataParameters(); len(params) != 0 {
+		t.Errorf("FormDataParameters() = %v", params)
+	}
+	if body := (*Operation)(nil).BodyParameter(); body != nil {
+		t.Errorf("BodyParameter() of a nil operation = %v", body)
+	}
+
+	_, err = NewParser([]byte(`{"swagger": "2.0", "parameters": {"p": {"name": "p", "in": "cookie", "type": "string"}}}`)).Parse()
+	var pe *ParseError
+	if !errors.As(err, &pe) || len(pe.At(".parameters.p.in")) != 1 {
+		t.Errorf("Parse() of an invalid parameter location = %v", err)
+	}
+}
+
+func TestOperations_SortBy(t *testing.T) {
+	newOp := func(path, method, id string, tags ...string) *Operation {
+		op := NewOperation(path, method)
+		op.ID = id
+		op.Tags = tags
+		return op
+	}
+	keys := func(ops Operations) []string {
+		var results []string
+		for _, op := range ops {
+			results = append(results, op.Key.Method+" "+op.Key.Path)
+		}
+		return results
+	}
+	ops := Operations{
+		newOp("/pets", "POST", "createPet", "pets"),
+		newOp("/owners", "GET", "", "owners"),
+		newOp("/pets", "GET", "listPets", "pets"),
+		newOp("/pets", "DELETE", "deletePets"),
+		newOp("/pets", "PATCH", "patchPets", "pets"),
+		newOp("/owners", "PUT", "putOwners"),
+	}
+	tests := map[string]struct {
+		sort     func(Operations) Operations
+		expected []string
+	}{
+		"Sorted should order by path then method": {
+			sort:     Operations.Sorted,
+			expected: []string{"GET /owners", "PUT /owners", "DELETE /pets", "GET /pets", "PATCH /pets", "POST /pets"},
+		},
+		"SortBy should order methods conventionally": {
+			sort:     func(ops Operations) Operations { return ops.SortBy(SortByPath, SortByMethod) },
+			expected: []string{"GET /owners", "PUT /owners", "GET /pets", "POST /pets", "PATCH /pets", "DELETE /pets"},
+		},
+		"SortBy should place operations without ids last": {
+			sort:     func(ops Operations) Operations { return ops.SortBy(SortByOperationID) },
+			expected: []string{"POST /pets", "DELETE /pets", "GET /pets", "PATCH /pets", "PUT /owners", "GET /owners"},
+		},
+		"SortBy should be stable for ties": {
+			sort:     func(ops Operations) Operations { return ops.SortBy(SortByTag) },
+			expected: []string{"GET /owners", "POST /pets", "GET /pets", "PATCH /pets", "DELETE /pets", "PUT /owners"},
+		},
+		"SortBy should apply later keys to ties": {
+			sort:     func(ops Operations) Operations { return ops.SortBy(SortByTag, SortByMethod) },
+			expected: []string{"GET /owners", "GET /pets", "POST /pets", "PATCH /pets", "PUT /owners", "DELETE /pets"},
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			got := keys(tt.sort(append(Operations(nil), ops...)))
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOperation_EffectiveParameters(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"parameters": {
+			"limit": {"name": "limit", "in": "query", "type": "integer"},
+			"trace": {"name": "X-Trace", "in": "header", "type": "string"}
+		},
+		"paths": {
+			"/pets/{petId}": {
+				"parameters": [
+					{"name": "petId", "in": "path", "required": true, "type": "string"},
+					{"$ref": "#/parameters/limit"},
+					{"name": "X-Trace", "in": "header", "type": "string", "description": "path level"}
+				],
+				"get": {
+					"parameters": [
+						{"name": "limit", "in": "query", "type": "integer", "description": "overridden"},
+						{"name": "petId", "in": "header", "type": "string"}
+					],
+					"responses": {"200": {"description": "ok"}}
+				},
+				"put": {
+					"parameters": [
+						{"$ref": "#/parameters/trace"},
+						{"$ref": "#/parameters/missing"}
+					],
+					"responses": {"200": {"description": "ok"}}
+				},
+				"delete": {
+					"responses": {"204": {"description": "gone"}}
+				}
+			}
+		}
+	}`
+	swagger, err := NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	pi := swagger.Paths.Items["/pets/{petId}"]
+	describe := func(params []Parameter) []string {
+		var results []string
+		for _, p := range params {
+			if p.Ref != nil {
+				results = append(results, p.Ref.URI())
+				continue
+			}
+			results = append(results, string(p.In)+":"+p.Name+":"+p.Description)
+		}
+		return results
+	}
+	tests := map[string]struct {
+		op       *Operation
+		pathItem *PathItem
+		want     []string
+	}{
+		"override by name and location": {
+			op:       pi.Get,
+			pathItem: pi,
+			want:     []string{"query:limit:overridden", "header:petId:", "path:petId:", "header:X-Trace:path level"},
+		},
+		"resolve global references": {
+			op:       pi.Put,
+			pathItem: pi,
+			want:     []string{"header:X-Trace:", "#/parameters/missing", "path:petId:", "query:limit:"},
+		},
+		"inherit all from path item": {
+			op:       pi.Delete,
+			pathItem: pi,
+			want:     []string{"path:petId:", "query:limit:", "header:X-Trace:path level"},
+		},
+		"without path item": {
+			op:   pi.Get,
+			want: []string{"query:limit:overridden", "header:petId:"},
+		},
+		"nil operation": {
+			pathItem: pi,
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			got := describe(tt.op.EffectiveParameters(tt.pathItem, swagger.Parameters))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EffectiveParameters() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperation_EffectiveSecurity(t *testing.T) {
+	global := []SecurityRequirements{{"apiKey": {}}}
+	tests := map[string]struct {
+		op      *Operation
+		swagger *Swagger
+		want    []SecurityRequirements
+	}{
+		"inherit global security": {
+			op:      &Operation{},
+			swagger: &Swagger{Security: global},
+			want:    global,
+		},
+		"override global security": {
+			op:      &Operation{Security: []SecurityRequirements{{"oauth": {"read"}}}},
+			swagger: &Swagger{Security: global},
+			want:    []SecurityRequirements{{"oauth": {"read"}}},
+		},
+		"empty list disables security": {
+			op:      &Operation{Security: []SecurityRequirements{}},
+			swagger: &Swagger{Security: global},
+			want:    []SecurityRequirements{},
+		},
+		"no security declared": {
+			op:      &Operation{},
+			swagger: &Swagger{},
+		},
+		"nil swagger": {
+			op: &Operation{},
+		},
+		"nil operation": {
+			swagger: &Swagger{Security: global},
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			got := tt.op.EffectiveSecurity(tt.swagger)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EffectiveSecurity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperation_parameterRefs(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"parameters": {
+			"petBody": {"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}},
+			"limit": {"name": "limit", "in": "query", "type": "integer"}
+		},
+		"paths": {
+			"/pets": {
+				"post": {
+					"parameters": [{"$ref": "#/parameters/petBody"}, {"$ref": "#/parameters/limit"}],
+					"responses": {"201": {"description": "created"}}
+				}
+			}
+		},
+		"definitions": {
+			"Pet": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Owner"}}},
+			"Owner": {"type": "object"}
+		}
+	}`
+	swagger, err := NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	op := swagger.Paths.Items["/pets"].Post
+	if body := op.ResolvedBodyParameter(swagger); body == nil || body.Name != "pet" || body.Schema.Ref.URI() != "#/definitions/Pet" {
+		t.Errorf("ResolvedBodyParameter() = %v", body)
+	}
+	if body := op.ResolvedBodyParameter(nil); body != nil {
+		t.Errorf("ResolvedBodyParameter() without a swagger spec = %v", body)
+	}
+	if body := op.BodyParameter(); body != nil {
+		t.Errorf("BodyParameter() should not resolve references but got %v", body)
+	}
+	if params := op.ResolvedQueryParameters(swagger); len(params) != 1 || params[0].Name != "limit" {
+		t.Errorf("ResolvedQueryParameters() = %v", params)
+	}
+	if params := op.ResolvedParametersIn(InBody, swagger); len(params) != 1 || params[0].Name != "pet" {
+		t.Errorf("ResolvedParametersIn() = %v", params)
+	}
+	if got := op.ResolvedReferencedDefinitions(swagger).Values(); !reflect.DeepEqual(got, []string{"Pet"}) {
+		t.Errorf("ResolvedReferencedDefinitions() = %v", got)
+	}
+	if got := op.ReferencedDefinitions().Values(); len(got) != 0 {
+		t.Errorf("ReferencedDefinitions() should not resolve references but got %v", got)
+	}
+	if got := swagger.OperationDefinitions(op); !reflect.DeepEqual(got, []string{"Owner", "Pet"}) {
+		t.Errorf("OperationDefinitions() = %v", got)
+	}
+	if got, want := swagger.OperationsReferencing("Owner"), []OperationKey{op.Key}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OperationsReferencing() = %v, want %v", got, want)
+	}
+}
+
+func TestOperation_ReferencedDefinitions(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"post": {
+					"parameters": [{"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}],
+					"responses": {
+						"201": {"description": "created", "schema": {"$ref": "#/definitions/Pet"}},
+						"400": {"description": "invalid", "schema": {"type": "array", "items": {"$ref": "#/definitions/Problem"}}},
+						"default": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+					}
+				},
+				"get": {
+					"responses": {"200": {"description": "ok", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}}
+				},
+				"delete": {
+					"responses": {"204": {"description": "deleted"}}
+				}
+			}
+		},
+		"definitions": {
+			"Pet": {"type": "object"},
+			"Problem": {"type": "object"},
+			"Error": {"type": "object"}
+		}
+	}`
+	swagger, err := NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	pi := swagger.Paths.Items["/pets"]
+	type testCase struct {
+		op       *Operation
+		expected []string
+	}
+	tests := map[string]testCase{
+		"include the schemas of both the parameters and responses": {
+			op:       pi.Post,
+			expected: []string{"Pet", "Problem", "Error"},
+		},
+		"include the schemas of responses without any parameters": {
+			op:       pi.Get,
+			expected: []string{"Pet"},
+		},
+		"be empty without any schemas": {
+			op:       pi.Delete,
+			expected: []string{},
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			if got := tt.op.ReferencedDefinitions().Values(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ReferencedDefinitions() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+	expected := []OperationKey{pi.Post.Key}
+	if got := swagger.OperationsReferencing("Error"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("OperationsReferencing(Error) = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,152 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/valyala/fastjson"
+)
+
+// ErrLimitExceeded is wrapped by the error returned from Parse when the document exceeds a configured limit
+var ErrLimitExceeded = errors.New("parser limit exceeded")
+
+// ExtensionValidator validates the value of an extension found at the location, returning a non-nil error if invalid
+type ExtensionValidator func(loc string, key string, value *fastjson.Value) error
+
+// ParserOption configures a Parser
+type ParserOption func(p *Parser)
+
+// WithMaxDepth limits the nesting depth of JSON objects and arrays within the document, where the root object is at
+// a depth of 1. A depth of zero or less is unlimited.
+func WithMaxDepth(depth int) ParserOption {
+	return func(p *Parser) {
+		p.maxDepth = depth
+	}
+}
+
+// WithMaxDocumentSize limits the size in bytes of the raw document. A size of zero or less is unlimited.
+func WithMaxDocumentSize(size int) ParserOption {
+	return func(p *Parser) {
+		p.maxDocumentSize = size
+	}
+}
+
+// WithMaxErrors will stop parsing once the specified count of errors have been found, in which case the returned
+// *ParseError is marked as truncated. A count of zero or less is unlimited.
+func WithMaxErrors(count int) ParserOption {
+	return func(p *Parser) {
+		p.maxErrors = count
+	}
+}
+
+// WithAllowUnknownFields will ignore any fields not defined by the swagger specification rather than failing
+func WithAllowUnknownFields() ParserOption {
+	return func(p *Parser) {
+		p.allowUnknownFields = true
+	}
+}
+
+// WithExtensionValidator adds the validator to be called for every extension found in the document
+func WithExtensionValidator(validator ExtensionValidator) ParserOption {
+	return func(p *Parser) {
+		if validator != nil {
+			p.extensionValidators = append(p.extensionValidators, validator)
+		}
+	}
+}
+
+// WithExtensionValidatorFor adds the validator to be called only for the extension with the key wherever it is found
+func WithExtensionValidatorFor(key string, validator ExtensionValidator) ParserOption {
+	if validator == nil {
+		return func(*Parser) {}
+	}
+	return WithExtensionValidator(func(loc string, k string, value *fastjson.Value) error {
+		if k != key {
+			return nil
+		}
+		return validator(loc, k, value)
+	})
+}
+
+// WithExtensionSchema validates the value of the extension with the key against the schema wherever it is found, such
+// as requiring an object with integer properties. References within the schema are not resolved as the definitions of
+// the document may not be parsed yet.
+func WithExtensionSchema(key string, schema *Schema) ParserOption {
+	if schema == nil {
+		return func(*Parser) {}
+	}
+	return WithExtensionValidatorFor(key, func(_ string, k string, value *fastjson.Value) error {
+		if errs := (*Swagger)(nil).ValidateValue(schema, value); len(errs) > 0 {
+			return fmt.Errorf("invalid %s: %w", k, errors.Join(errs...))
+		}
+		return nil
+	})
+}
+
+// WithKeyOrder records the authored order of the keys of every object in the document, so that marshalling the parsed
+// Swagger emits them in that same order to minimize churn when the spec is written back to its source
+func WithKeyOrder() ParserOption {
+	return func(p *Parser) {
+		p.preserveKeyOrder = true
+	}
+}
+
+// WithSourceMap records the span of bytes of every value in the document, available from Swagger.SourceRange, so that
+// the parsed objects can be mapped back to their source
+func WithSourceMap() ParserOption {
+	return func(p *Parser) {
+		p.recordSources = true
+	}
+}
+
+// WithKeepDuplicateOperations keeps any operations that have the same path and method as an earlier operation,
+// available from Swagger.DuplicateOperations, rather than dropping them. They are still reported as errors.
+func WithKeepDuplicateOperations() ParserOption {
+	return func(p *Parser) {
+		p.keepDuplicateOps = true
+	}
+}
+
+// WithDetachedValues copies every JSON value kept within the parsed Swagger, such as extensions and enums, so that
+// none share memory with the JSON parser and the Swagger remains valid after calling Release. Defaults and examples
+// are always copied.
+func WithDetachedValues() ParserOption {
+	return func(p *Parser) {
+		p.detachValues = true
+	}
+}
+
+// errorLimitReached is panicked by the Parser when the max errors have been reached and recovered by Parse
+type errorLimitReached struct{}
+
+// contextDone is panicked by the Parser when its context is done and recovered by Parse
+type contextDone struct {
+	err error
+}
+
+// exceedsDepth returns true if the nesting of the value is deeper than the max depth
+func exceedsDepth(v *fastjson.Value, maxDepth int) bool {
+	switch v.Type() {
+	case fastjson.TypeObject:
+		if maxDepth < 1 {
+			return true
+		}
+		var exceeded bool
+		v.GetObject().Visit(func(_ []byte, child *fastjson.Value) {
+			if !exceeded {
+				exceeded = exceedsDepth(child, maxDepth-1)
+			}
+		})
+		return exceeded
+	case fastjson.TypeArray:
+		if maxDepth < 1 {
+			return true
+		}
+		for _, child := range v.GetArray() {
+			if exceedsDepth(child, maxDepth-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -10,8 +10,11 @@ import (
 // https://swagger.io/specification/v2/#parameter-object
 type Parameter struct {
 	Extensions
+	// Ref refers to a parameter declared within the parameters of the swagger spec, when set the other fields are
+	// not used
+	Ref              *Reference
 	Name             string
-	In               string
+	In               In
 	Description      string
 	Required         bool
 	Schema           *Schema
@@ -21,20 +24,49 @@ type Parameter struct {
 	Items            *Items
 	CollectionFormat string
 	Default          any
-	Maximum          int
+	Maximum          *float64
 	ExclusiveMaximum bool
-	Minimum          int
+	Minimum          *float64
 	ExclusiveMinimum bool
-	MaxLength        int
-	MinLength        int
+	MaxLength        *int
+	MinLength        *int
 	Pattern          string
-	MaxItems         int
-	MinItems         int
+	MaxItems         *int
+	MinItems         *int
 	UniqueItems      bool
-	MaxProperties    int
-	MinProperties    int
+	MaxProperties    *int
+	MinProperties    *int
 	Enum             []any
-	MultipleOf       int
+	MultipleOf       *float64
+}
+
+// In defines the location of a Parameter
+type In string
+
+const (
+	// InQuery is used for parameters appended to the URL
+	InQuery In = "query"
+	// InHeader is used for custom request headers
+	InHeader In = "header"
+	// InPath is used for parameters within the templated path of an operation
+	InPath In = "path"
+	// InFormData is used for form parameters of application/x-www-form-urlencoded or multipart/form-data requests
+	InFormData In = "formData"
+	// InBody is used for the payload of the request
+	InBody In = "body"
+)
+
+// parameterLocations are all the locations defined by the swagger specification
+var parameterLocations = []In{InQuery, InHeader, InPath, InFormData, InBody}
+
+// Valid returns true if this is one of the locations defined by the swagger specification
+func (in In) Valid() bool {
+	for _, loc := range parameterLocations {
+		if in == loc {
+			return true
+		}
+	}
+	return false
 }
 
 // NewParameter returns a new Parameter object
@@ -44,19 +76,49 @@ func NewParameter() *Parameter {
 	}
 }
 
+// ReplaceParameterRefs rewrites every reference to a parameter named by a key of the replacements to instead refer to
+// the parameter named by its value, returning the count of references rewritten. The parameters themselves are neither
+// added nor removed.
+func (s *Swagger) ReplaceParameterRefs(replacements map[string]string) int {
+	if s == nil || len(replacements) == 0 {
+		return 0
+	}
+	// references are shared by every copy of a parameter so are rewritten in place, though only once as cloned
+	// operations may share them too
+	rewritten := make(map[*Reference]bool)
+	replace := func(params []Parameter) {
+		for _, p := range params {
+			if name, ok := p.Ref.ParameterName(); ok && !rewritten[p.Ref] {
+				if replacement, replaced := replacements[name]; replaced {
+					p.Ref.uri = "#/parameters/" + replacement
+					rewritten[p.Ref] = true
+				}
+			}
+		}
+	}
+	for _, pi := range s.Paths.Items {
+		if pi != nil {
+			replace(pi.Parameters)
+		}
+	}
+	for _, op := range s.Operations() {
+		replace(op.Parameters)
+	}
+	return len(rewritten)
+}
+
 func parseParameterDefinitions(val *fastjson.Value, parser *Parser) map[string]Parameter {
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	parser.checkContext()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid parameters value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid parameters value: %w", err))
 		return nil
 	}
 	result := make(map[string]Parameter, obj.Len())
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		if param := parseParameter(v, parser); param != nil {
 			result[string(key)] = *param
 		}
@@ -65,26 +127,33 @@ func parseParameterDefinitions(val *fastjson.Value, parser *Parser) map[string]P
 }
 
 func parseParameter(val *fastjson.Value, parser *Parser) *Parameter {
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	parser.checkContext()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid parameter value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid parameter value: %w", err))
 		return nil
 	}
 	result := NewParameter()
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
+		case matchString(key, "$ref"):
+			parser.parseString(v, "$ref", false, func(s string) {
+				result.Ref = NewRef(s)
+			})
 		case matchString(key, "name"):
 			parser.parseString(v, "name", false, func(s string) {
 				result.Name = s
 			})
 		case matchString(key, "in"):
-			parser.parseString(v, "in", false, func(s string) {
-				result.In = s
+			parser.parseAndValidateString(v, "in", func(s string) error {
+				if in := In(s); !in.Valid() {
+					return fmt.Errorf("parameter in should be one of %v but got: '%s'", parameterLocations, s)
+				}
+				result.In = In(s)
+				return nil
 			})
 		case matchString(key, "description"):
 			parser.parseString(v, "description", true, func(s string) {
@@ -111,16 +180,16 @@ func parseParameter(val *fastjson.Value, parser *Parser) *Parameter {
 				result.AllowEmptyValue = b
 			})
 		case matchString(key, "maximum"):
-			parser.parseInt(v, "maximum", func(i int) {
-				result.Maximum = i
+			parser.parseFloat(v, "maximum", func(f float64) {
+				result.Maximum = &f
 			})
 		case matchString(key, "exclusiveMaximum"):
 			parser.parseBool(v, "exclusiveMaximum", func(b bool) {
 				result.ExclusiveMaximum = b
 			})
 		case matchString(key, "minimum"):
-			parser.parseInt(v, "minimum", func(i int) {
-				result.Minimum = i
+			parser.parseFloat(v, "minimum", func(f float64) {
+				result.Minimum = &f
 			})
 		case matchString(key, "exclusiveMinimum"):
 			parser.parseBool(v, "exclusiveMinimum", func(b bool) {
@@ -128,11 +197,11 @@ func parseParameter(val *fastjson.Value, parser *Parser) *Parameter {
 			})
 		case matchString(key, "maxLength"):
 			parser.parseInt(v, "maxLength", func(i int) {
-				result.MaxLength = i
+				result.MaxLength = &i
 			})
 		case matchString(key, "minLength"):
 			parser.parseInt(v, "minLength", func(i int) {
-				result.MinLength = i
+				result.MinLength = &i
 			})
 		case matchString(key, "pattern"):
 			parser.parseString(v, "pattern", true, func(s string) {
@@ -140,40 +209,82 @@ func parseParameter(val *fastjson.Value, parser *Parser) *Parameter {
 			})
 		case matchString(key, "maxItems"):
 			parser.parseInt(v, "maxItems", func(i int) {
-				result.MaxItems = i
+				result.MaxItems = &i
 			})
 		case matchString(key, "minItems"):
 			parser.parseInt(v, "minItems", func(i int) {
-				result.MinItems = i
+				result.MinItems = &i
 			})
 		case matchString(key, "uniqueItems"):
 			parser.parseBool(v, "uniqueItems", func(b bool) {
 				result.UniqueItems = b
 			})
 		case matchString(key, "multipleOf"):
-			parser.parseInt(v, "multipleOf", func(i int) {
-				result.MultipleOf = i
+			parser.parseFloat(v, "multipleOf", func(f float64) {
+				result.MultipleOf = &f
 			})
 		case matchString(key, "enum"):
 			if vals, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid enum value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid enum value: %w", e))
 			} else {
 				result.Enum = make([]any, len(vals))
 				for i := range vals {
-					result.Enum[i] = vals[i]
+					result.Enum[i] = parser.retain(vals[i])
 				}
 			}
 		case matchString(key, "items"):
 			result.Items = parseItems(v, parser)
 		case matchString(key, "default"):
-			result.Default = v
+			result.Default = detachValue(v)
 		case matchString(key, "schema"):
 			result.Schema = parseSchema(v, parser)
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
+
+func (p *Parameter) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setString(a, val, "$ref", p.Ref.URI())
+	setString(a, val, "name", p.Name)
+	setString(a, val, "in", string(p.In))
+	setString(a, val, "description", p.Description)
+	setBool(a, val, "required", p.Required)
+	if p.Schema != nil {
+		val.Set("schema", p.Schema.marshal(a))
+	}
+	setString(a, val, "type", p.Type)
+	setString(a, val, "format", p.Format)
+	setBool(a, val, "allowEmptyValue", p.AllowEmptyValue)
+	if p.Items != nil {
+		val.Set("items", p.Items.marshal(a))
+	}
+	setString(a, val, "collectionFormat", p.CollectionFormat)
+	setAny(a, val, "default", p.Default)
+	setFloat(a, val, "maximum", p.Maximum)
+	setBool(a, val, "exclusiveMaximum", p.ExclusiveMaximum)
+	setFloat(a, val, "minimum", p.Minimum)
+	setBool(a, val, "exclusiveMinimum", p.ExclusiveMinimum)
+	setInt(a, val, "maxLength", p.MaxLength)
+	setInt(a, val, "minLength", p.MinLength)
+	setString(a, val, "pattern", p.Pattern)
+	setInt(a, val, "maxItems", p.MaxItems)
+	setInt(a, val, "minItems", p.MinItems)
+	setBool(a, val, "uniqueItems", p.UniqueItems)
+	setEnum(a, val, "enum", p.Enum)
+	setFloat(a, val, "multipleOf", p.MultipleOf)
+	p.marshalExtensions(val)
+	return val
+}
+
+func marshalParameters(a *fastjson.Arena, params []Parameter) *fastjson.Value {
+	arr := a.NewArray()
+	for i := range params {
+		arr.SetArrayItem(i, params[i].marshal(a))
+	}
+	return arr
+}
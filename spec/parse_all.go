@@ -0,0 +1,53 @@
+package spec
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// ParseResult is the result of parsing one of the specs given to ParseAll
+type ParseResult struct {
+	Swagger *Swagger
+	Err     error
+}
+
+// ParseAll parses each of the raw specs by name concurrently using a pool of up to GOMAXPROCS workers and returns
+// the results by the same names. Each spec is parsed with ParseContext so any remaining specs fail quickly once the
+// context is done. The options are shared by every Parser so any ExtensionValidator must be safe for concurrent use.
+func ParseAll(ctx context.Context, raws map[string][]byte, opts ...ParserOption) map[string]ParseResult {
+	results := make(map[string]ParseResult, len(raws))
+	if len(raws) == 0 {
+		return results
+	}
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(raws) {
+		workers = len(raws)
+	}
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		names = make(chan string)
+	)
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				swagger, err := NewParser(raws[name], opts...).ParseContext(ctx)
+				mu.Lock()
+				results[name] = ParseResult{
+					Swagger: swagger,
+					Err:     err,
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for name := range raws {
+		names <- name
+	}
+	close(names)
+	wg.Wait()
+	return results
+}
@@ -2,6 +2,7 @@ package spec
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"sort"
@@ -11,27 +12,48 @@ import (
 	"github.com/valyala/fastjson"
 )
 
-var arenaPool fastjson.ArenaPool
-
 // Parser handles the parsing and validation of a swagger spec
 type Parser struct {
-	raw                []byte
-	rootVal            *fastjson.Value
-	swagger            *Swagger
-	errorsByLocation   map[string][]error
-	uniqueOperationIDs map[string]string
-	currentLoc         string
+	raw                 []byte
+	jp                  *fastjson.Parser
+	ctx                 context.Context
+	rootVal             *fastjson.Value
+	swagger             *Swagger
+	errorsByLocation    map[string][]error
+	errorCount          int
+	truncated           bool
+	uniqueOperationIDs  map[string]string
+	operationLocations  map[OperationKey]string
+	keepDuplicateOps    bool
+	baseLoc             string
+	segments            []locSegment
+	maxDepth            int
+	maxDocumentSize     int
+	maxErrors           int
+	allowUnknownFields  bool
+	preserveKeyOrder    bool
+	recordSources       bool
+	detachValues        bool
+	parsed              bool
+	extensionValidators []ExtensionValidator
+	variables           VariableLookup
 }
 
-// NewParser returns a new parser for the specified raw swagger JSON bytes
-func NewParser(raw []byte) *Parser {
-	return &Parser{
+// NewParser returns a new parser for the specified raw swagger JSON bytes configured with any options
+func NewParser(raw []byte, opts ...ParserOption) *Parser {
+	p := &Parser{
 		raw:                raw,
 		errorsByLocation:   make(map[string][]error),
 		uniqueOperationIDs: make(map[string]string),
+		operationLocations: make(map[OperationKey]string),
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
 }
 
+// HasError returns true if any errors have been found while parsing
 func (p *Parser) HasError() bool {
 	if p == nil {
 		return false
@@ -39,25 +61,76 @@ func (p *Parser) HasError() bool {
 	return len(p.errorsByLocation) > 0
 }
 
-func (p *Parser) Parse() (*Swagger, error) {
+// ParseContext will parse the swagger spec like Parse but stops once the context is done, checking it at each object
+func (p *Parser) ParseContext(ctx context.Context) (*Swagger, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("parsing canceled: %w", err)
+	}
+	p.ctx = ctx
+	defer func() {
+		p.ctx = nil
+	}()
+	return p.Parse()
+}
+
+// Parse will parse and validate the swagger spec, returning a *ParseError of every error found. A Parser can only parse
+// once since the parsed Swagger retains values owned by it, so calling Parse again returns an error.
+func (p *Parser) Parse() (swagger *Swagger, err error) {
 	if p == nil {
 		return nil, nil
 	}
+	if p.parsed {
+		return nil, errors.New("cannot parse again with the same parser, use a new parser for each parse")
+	}
+	p.parsed = true
 	if len(p.raw) == 0 {
 		return nil, errors.New("cannot parse empty raw swagger JSON bytes")
 	}
-	var (
-		jp  fastjson.Parser
-		err error
-	)
-	p.currentLoc = "."
-	if p.rootVal, err = jp.ParseBytes(p.raw); err != nil {
+	if p.maxDocumentSize > 0 && len(p.raw) > p.maxDocumentSize {
+		return nil, fmt.Errorf("%w: document size of %d bytes exceeds the max of %d", ErrLimitExceeded, len(p.raw), p.maxDocumentSize)
+	}
+	if p.variables != nil {
+		expanded, e := ExpandVariables(p.raw, p.variables)
+		if e != nil {
+			return nil, fmt.Errorf("failed to expand variables: %w", e)
+		}
+		// only expand once as the values of the variables may contain placeholders themselves
+		p.raw, p.variables = expanded, nil
+	}
+	p.jp = parserPool.Get()
+	p.segments = p.segments[:0]
+	if p.rootVal, err = p.jp.ParseBytes(p.raw); err != nil {
 		err = fmt.Errorf("failed to parse raw swagger bytes as JSON: %w", err)
-		p.appendError(err)
+		p.appendError(ErrorCodeInvalidJSON, err)
 		return nil, err
 	}
+	if p.maxDepth > 0 && exceedsDepth(p.rootVal, p.maxDepth) {
+		return nil, fmt.Errorf("%w: document nesting exceeds the max depth of %d", ErrLimitExceeded, p.maxDepth)
+	}
 
+	defer func() {
+		if r := recover(); r != nil {
+			switch halted := r.(type) {
+			case errorLimitReached:
+				swagger, err = p.swagger, p.Err()
+			case contextDone:
+				swagger, err = nil, fmt.Errorf("parsing canceled: %w", halted.err)
+			default:
+				panic(r)
+			}
+		}
+	}()
 	parseSwagger(p.rootVal, p)
+	if p.preserveKeyOrder && p.swagger != nil {
+		p.swagger.keyOrder = recordKeyOrder(p.rootVal)
+	}
+	if p.recordSources && p.swagger != nil {
+		p.swagger.sourceMap = mapSources(p.raw)
+		p.swagger.parseErrors = p.errorsByLocation
+	}
 	return p.swagger, p.Err()
 }
 
@@ -66,69 +139,298 @@ func (p *Parser) Err() error {
 	if p == nil || len(p.errorsByLocation) == 0 {
 		return nil
 	}
-	return &ParseError{ByLocation: p.errorsByLocation}
+	return &ParseError{
+		ByLocation: p.errorsByLocation,
+		Truncated:  p.truncated,
+	}
 }
 
+// locationForOperation returns the location of the operation using the id and true if it is unique, otherwise it
+// returns the location of the other operation already using it and false
 func (p *Parser) locationForOperation(id string) (string, bool) {
-	loc, preExisting := p.uniqueOperationIDs[id]
-	if preExisting {
-		return loc, preExisting
+	if loc, preExisting := p.uniqueOperationIDs[id]; preExisting {
+		return loc, false
 	}
-	p.uniqueOperationIDs[id] = p.currentLoc
-	return p.currentLoc, true
+	loc := p.location()
+	p.uniqueOperationIDs[id] = loc
+	return loc, true
 }
 
-func (p *Parser) parseString(v *fastjson.Value, fieldName string, allowEmpty bool, accept func(s string)) {
-	var validator func(string) error
-	if allowEmpty {
-		validator = func(s string) error {
-			accept(s)
-			return nil
+// addOperation adds the operation found at the current location to the swagger, reporting it as a duplicate if
+// there already is one with the same key
+func (p *Parser) addOperation(op *Operation) {
+	loc := p.location()
+	if p.swagger.addOperation(op) {
+		p.operationLocations[op.Key] = loc
+		return
+	}
+	if p.keepDuplicateOps {
+		p.swagger.duplicateOperations = append(p.swagger.duplicateOperations, op)
+	}
+	p.appendError(ErrorCodeDuplicateOperation, &DuplicateOperationError{
+		Key:              op.Key,
+		Location:         loc,
+		PreviousLocation: p.operationLocations[op.Key],
+	})
+}
+
+// locSegment is a single object key or array index of a location
+type locSegment struct {
+	key     []byte
+	index   int
+	isIndex bool
+}
+
+// mark returns the mark of the current location to later reset to or build upon
+func (p *Parser) mark() int {
+	return len(p.segments)
+}
+
+// reset will return the current location to the mark
+func (p *Parser) reset(mark int) {
+	p.segments = p.segments[:mark]
+}
+
+// atKey sets the current location to the object key under the mark
+func (p *Parser) atKey(mark int, key []byte) {
+	p.segments = append(p.segments[:mark], locSegment{key: key})
+}
+
+// atIndex sets the current location to the array index under the mark
+func (p *Parser) atIndex(mark int, i int) {
+	p.segments = append(p.segments[:mark], locSegment{index: i, isIndex: true})
+}
+
+// location materializes the current location, which is only done when it must be retained
+func (p *Parser) location() string {
+	if len(p.segments) == 0 {
+		if p.baseLoc == "" {
+			return "."
 		}
-	} else {
-		validator = func(s string) error {
-			if s == "" {
-				return fmt.Errorf("empty '%s' value", fieldName)
-			}
-			accept(s)
-			return nil
+		return p.baseLoc
+	}
+	var b strings.Builder
+	b.WriteString(p.baseLoc)
+	for _, seg := range p.segments {
+		if seg.isIndex {
+			b.WriteByte('[')
+			b.WriteString(strconv.Itoa(seg.index))
+			b.WriteByte(']')
+		} else {
+			b.WriteByte('.')
+			b.Write(seg.key)
 		}
 	}
-	p.parseAndValidateString(v, fieldName, validator)
+	return b.String()
+}
+
+func (p *Parser) parseString(v *fastjson.Value, fieldName string, allowEmpty bool, accept func(s string)) {
+	s, e := v.StringBytes()
+	switch {
+	case e != nil:
+		p.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid '%s' value: %w", fieldName, e))
+	case len(s) == 0 && !allowEmpty:
+		p.appendError(ErrorCodeEmptyValue, fmt.Errorf("empty '%s' value", fieldName))
+	default:
+		accept(string(s))
+	}
 }
 
 func (p *Parser) parseAndValidateString(v *fastjson.Value, fieldName string, validate func(s string) error) {
 	if s, e := v.StringBytes(); e != nil {
-		p.appendError(fmt.Errorf("invalid '%s' value: %w", fieldName, e))
+		p.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid '%s' value: %w", fieldName, e))
 	} else if e = validate(string(s)); e != nil {
-		p.appendError(e)
+		p.appendError(ErrorCodeInvalidValue, e)
 	}
 }
 
 func (p *Parser) parseInt(v *fastjson.Value, fieldName string, accept func(i int)) {
 	if i, e := v.Int(); e != nil {
-		p.appendError(fmt.Errorf("invalid '%s' value: %w", fieldName, e))
+		p.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid '%s' value: %w", fieldName, e))
 	} else if accept != nil {
 		accept(i)
 	}
 }
 
+func (p *Parser) parseFloat(v *fastjson.Value, fieldName string, accept func(f float64)) {
+	if f, e := v.Float64(); e != nil {
+		p.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid '%s' value: %w", fieldName, e))
+	} else if accept != nil {
+		accept(f)
+	}
+}
+
 func (p *Parser) parseBool(v *fastjson.Value, fieldName string, accept func(b bool)) {
 	if b, e := v.Bool(); e != nil {
-		p.appendError(fmt.Errorf("invalid '%s' value: %w", fieldName, e))
+		p.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid '%s' value: %w", fieldName, e))
 	} else if accept != nil {
 		accept(b)
 	}
 }
 
-func (p *Parser) appendError(err error) {
-	if err != nil {
-		p.errorsByLocation[p.currentLoc] = append(p.errorsByLocation[p.currentLoc], err)
+// appendError will record the error at the current location using the code unless the error is a *ValidationError
+// declaring its own code
+func (p *Parser) appendError(code ErrorCode, err error) {
+	if err == nil {
+		return
+	}
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		ve = newValidationError(code, err)
+	}
+	if ve.Code == ErrorCodeUnknownField && p.allowUnknownFields {
+		return
+	}
+	ve.Location = p.location()
+	p.errorsByLocation[ve.Location] = append(p.errorsByLocation[ve.Location], ve)
+	p.errorCount++
+	if p.maxErrors > 0 && p.errorCount >= p.maxErrors {
+		p.truncated = true
+		panic(errorLimitReached{})
+	}
+}
+
+// checkContext will halt parsing if the parser's context is done
+func (p *Parser) checkContext() {
+	if p.ctx == nil {
+		return
+	}
+	if err := p.ctx.Err(); err != nil {
+		panic(contextDone{err: err})
 	}
 }
 
+// acceptExtension will store the extension value after validating it with any of the parser's extension validators
+func (p *Parser) acceptExtension(exts Extensions, key []byte, v *fastjson.Value) {
+	keyStr := string(key)
+	for _, validate := range p.extensionValidators {
+		if err := validate(p.location(), keyStr, v); err != nil {
+			p.appendError(ErrorCodeInvalidExtension, err)
+		}
+	}
+	exts[keyStr] = p.retain(v)
+}
+
+// retain returns the value to be kept within the parsed Swagger, which is a detached copy WithDetachedValues
+func (p *Parser) retain(v *fastjson.Value) *fastjson.Value {
+	if p.detachValues {
+		return detachValue(v)
+	}
+	return v
+}
+
+// ErrorCode classifies a ValidationError so callers may filter them without inspecting messages
+type ErrorCode string
+
+const (
+	// ErrorCodeInvalidJSON is used when the raw bytes are not valid JSON
+	ErrorCodeInvalidJSON ErrorCode = "invalid-json"
+	// ErrorCodeUnknownField is used when an object contains a field not defined by the swagger specification
+	ErrorCodeUnknownField ErrorCode = "unknown-field"
+	// ErrorCodeInvalidType is used when a value is not of the JSON type required by the swagger specification
+	ErrorCodeInvalidType ErrorCode = "invalid-type"
+	// ErrorCodeEmptyValue is used when a value is required to be non-empty
+	ErrorCodeEmptyValue ErrorCode = "empty-value"
+	// ErrorCodeInvalidValue is used when a value is of the correct type but is not one of the allowed values
+	ErrorCodeInvalidValue ErrorCode = "invalid-value"
+	// ErrorCodeDuplicateOperationID is used when an operationId is used by more than one operation
+	ErrorCodeDuplicateOperationID ErrorCode = "duplicate-operation-id"
+	// ErrorCodeInvalidExtension is used when an extension validator rejects the value of an extension
+	ErrorCodeInvalidExtension ErrorCode = "invalid-extension"
+	// ErrorCodeDuplicateOperation is used when more than one operation has the same path and method
+	ErrorCodeDuplicateOperation ErrorCode = "duplicate-operation"
+)
+
+// DuplicateOperationError is the underlying error of an ErrorCodeDuplicateOperation ValidationError
+type DuplicateOperationError struct {
+	Key OperationKey
+	// Location is where the duplicate operation was found
+	Location string
+	// PreviousLocation is where the operation that was kept was found
+	PreviousLocation string
+}
+
+func (e *DuplicateOperationError) Error() string {
+	return fmt.Sprintf("duplicate operation %s %s also found at %s: merge them into one operation or remove one",
+		e.Key.Method, e.Key.Path, e.PreviousLocation)
+}
+
+// ValidationError is a single error found at a location within the swagger spec
+type ValidationError struct {
+	Code     ErrorCode
+	Location string
+	Err      error
+}
+
+func newValidationError(code ErrorCode, err error) *ValidationError {
+	return &ValidationError{
+		Code: code,
+		Err:  err,
+	}
+}
+
+func (e *ValidationError) Error() string {
+	if e == nil || e.Err == nil {
+		return ""
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ValidationError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// ParseError is the aggregate of all errors found while parsing a swagger spec, each being a *ValidationError
 type ParseError struct {
 	ByLocation map[string][]error
+	// Truncated is true when parsing stopped early because the max errors were reached
+	Truncated bool
+}
+
+// Locations returns the sorted locations that have errors
+func (e *ParseError) Locations() []string {
+	if e == nil || len(e.ByLocation) == 0 {
+		return nil
+	}
+	locs := make([]string, 0, len(e.ByLocation))
+	for loc := range e.ByLocation {
+		locs = append(locs, loc)
+	}
+	sort.Strings(locs)
+	return locs
+}
+
+// At returns the errors found at the specified location
+func (e *ParseError) At(loc string) []error {
+	if e == nil {
+		return nil
+	}
+	return e.ByLocation[loc]
+}
+
+// Unwrap returns all the errors ordered by their locations so they may be inspected with errors.Is and errors.As
+func (e *ParseError) Unwrap() []error {
+	var results []error
+	for _, loc := range e.Locations() {
+		results = append(results, e.ByLocation[loc]...)
+	}
+	return results
+}
+
+// WithCode returns all the errors of the specified code ordered by their locations
+func (e *ParseError) WithCode(code ErrorCode) []*ValidationError {
+	var results []*ValidationError
+	for _, err := range e.Unwrap() {
+		var ve *ValidationError
+		if errors.As(err, &ve) && ve.Code == code {
+			results = append(results, ve)
+		}
+	}
+	return results
 }
 
 func (e *ParseError) Error() string {
@@ -136,19 +438,12 @@ func (e *ParseError) Error() string {
 		return ""
 	}
 	var (
-		b       strings.Builder
-		numLocs = len(e.ByLocation)
-		locs    = make([]string, numLocs)
-		i       int
+		b    strings.Builder
+		locs = e.Locations()
 	)
 	b.WriteString("invalid swagger: found validation errors from ")
-	b.WriteString(strconv.Itoa(numLocs))
+	b.WriteString(strconv.Itoa(len(locs)))
 	b.WriteString(" locations: {")
-	for loc := range e.ByLocation {
-		locs[i] = loc
-		i++
-	}
-	sort.Strings(locs)
 	for y, loc := range locs {
 		if y > 0 {
 			b.WriteString(", ")
@@ -193,3 +488,12 @@ func bytesToInt(b []byte) int {
 	i, _ := strconv.Atoi(string(b))
 	return i
 }
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// generateSpec returns a valid swagger spec with the specified count of paths each with two operations
+func generateSpec(paths int) []byte {
+	var b strings.Builder
+	b.WriteString(`{"swagger": "2.0", "info": {"title": "generated", "version": "1.0"}, "produces": ["application/json"], "paths": {`)
+	for i := 0; i < paths; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `"/resources%d/{id}": {
+			"parameters": [{"name": "id", "in": "path", "required": true, "type": "string", "x-internal": false}],
+			"get": {
+				"operationId": "getResource%d",
+				"tags": ["resources"],
+				"parameters": [{"name": "fields", "in": "query", "type": "array", "items": {"type": "string"}}],
+				"responses": {
+					"200": {"description": "ok", "schema": {"$ref": "#/definitions/Resource%d"}, "headers": {"X-Request-Id": {"type": "string"}}},
+					"default": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+				}
+			},
+			"put": {
+				"operationId": "putResource%d",
+				"parameters": [{"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Resource%d"}}],
+				"responses": {"204": {"description": "updated"}}
+			}
+		}`, i, i, i, i, i)
+	}
+	b.WriteString(`}, "definitions": {"Error": {"type": "object", "properties": {"message": {"type": "string"}}}`)
+	for i := 0; i < paths; i++ {
+		fmt.Fprintf(&b, `, "Resource%d": {
+			"type": "object",
+			"required": ["id", "name"],
+			"properties": {
+				"id": {"type": "string", "readOnly": true},
+				"name": {"type": "string", "minLength": 1, "maxLength": 64},
+				"count": {"type": "integer", "format": "int32", "minimum": 0},
+				"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+			}
+		}`, i)
+	}
+	b.WriteString(`}}`)
+	return []byte(b.String())
+}
+
+func benchmarkParse(b *testing.B, paths int) {
+	raw := generateSpec(paths)
+	b.SetBytes(int64(len(raw)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewParser(raw).Parse(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParser_Parse_10(b *testing.B) {
+	benchmarkParse(b, 10)
+}
+
+func BenchmarkParser_Parse_1000(b *testing.B) {
+	benchmarkParse(b, 1000)
+}
+
+func BenchmarkParser_Parse_10000(b *testing.B) {
+	benchmarkParse(b, 10000)
+}
+
+func BenchmarkParser_Parse_1000_Release(b *testing.B) {
+	raw := generateSpec(1000)
+	WarmPools(1)
+	b.SetBytes(int64(len(raw)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := NewParser(raw)
+		if _, err := p.Parse(); err != nil {
+			b.Fatal(err)
+		}
+		p.Release()
+	}
+}
+
+func BenchmarkSwagger_FindOperation_Parallel(b *testing.B) {
+	swagger, err := NewParser(generateSpec(1000)).Parse()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			if _, _, found := swagger.FindOperation("GET", fmt.Sprintf("/resources%d/abc", i%1000)); !found {
+				b.Fatal("operation not found")
+			}
+		}
+	})
+}
+
+func BenchmarkSwagger_OperationMap_Parallel(b *testing.B) {
+	swagger, err := NewParser(generateSpec(1000)).Parse()
+	if err != nil {
+		b.Fatal(err)
+	}
+	key := OperationKey{Path: "/resources500/{id}", Method: "GET"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if swagger.OperationMap()[key] == nil {
+				b.Fatal("operation not found")
+			}
+		}
+	})
+}
+
+func BenchmarkSwagger_DescribeOperation_Parallel(b *testing.B) {
+	swagger, err := NewParser(generateSpec(1000)).Parse()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			key := OperationKey{Path: fmt.Sprintf("/resources%d/{id}", i%1000), Method: "GET"}
+			if _, found := swagger.DescribeOperation(key); !found {
+				b.Fatal("operation not found")
+			}
+		}
+	})
+}
@@ -0,0 +1,368 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/valyala/fastjson"
+)
+
+func TestParseError(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "", "version": "1.0"},
+		"bogus": true,
+		"paths": {
+			"/pets": {
+				"get": {"operationId": "listPets", "deprecated": "yes"},
+				"post": {"operationId": "listPets"}
+			}
+		}
+	}`
+	_, err := NewParser([]byte(raw)).Parse()
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Parse() error = %v, want a *ParseError", err)
+	}
+	expectedLocs := []string{".bogus", ".info.title", ".paths./pets.get.deprecated", ".paths./pets.post.operationId"}
+	if got := pe.Locations(); !reflect.DeepEqual(got, expectedLocs) {
+		t.Errorf("Locations() = %v, want %v", got, expectedLocs)
+	}
+	expectedCodes := map[string]ErrorCode{
+		".bogus":                        ErrorCodeUnknownField,
+		".info.title":                   ErrorCodeEmptyValue,
+		".paths./pets.get.deprecated":   ErrorCodeInvalidType,
+		".paths./pets.post.operationId": ErrorCodeDuplicateOperationID,
+	}
+	for loc, code := range expectedCodes {
+		errs := pe.At(loc)
+		if len(errs) != 1 {
+			t.Errorf("At(%s) = %v, want exactly 1 error", loc, errs)
+			continue
+		}
+		var ve *ValidationError
+		if !errors.As(errs[0], &ve) || ve.Code != code || ve.Location != loc {
+			t.Errorf("At(%s) = %#v, want code %s", loc, errs[0], code)
+		}
+	}
+	if got := pe.Unwrap(); len(got) != len(expectedCodes) {
+		t.Errorf("Unwrap() returned %d errors, want %d", len(got), len(expectedCodes))
+	}
+	if got := pe.WithCode(ErrorCodeDuplicateOperationID); len(got) != 1 || got[0].Location != ".paths./pets.post.operationId" {
+		t.Errorf("WithCode(duplicate-operation-id) = %v", got)
+	}
+	var target *ValidationError
+	if !errors.As(err, &target) {
+		t.Error("errors.As() should find a *ValidationError within the *ParseError")
+	}
+}
+
+func TestParser_Options(t *testing.T) {
+	raw := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0", "x-owner": 42},
+		"unknownA": true,
+		"unknownB": true,
+		"paths": {"/a": {"get": {"responses": {"200": {"description": "ok", "schema": {"properties": {"nested": {"type": "object"}}}}}}}}
+	}`)
+	type testCase struct {
+		opts          []ParserOption
+		expectedLimit bool
+		expectedLocs  []string
+		truncated     bool
+	}
+	tests := map[string]testCase{
+		"no options should report all unknown fields": {
+			expectedLocs: []string{".unknownA", ".unknownB"},
+		},
+		"allowing unknown fields should report nothing": {
+			opts: []ParserOption{WithAllowUnknownFields()},
+		},
+		"max errors should stop after the first error": {
+			opts:         []ParserOption{WithMaxErrors(1)},
+			expectedLocs: []string{".unknownA"},
+			truncated:    true,
+		},
+		"max document size should fail for larger documents": {
+			opts:          []ParserOption{WithMaxDocumentSize(10)},
+			expectedLimit: true,
+		},
+		"max depth should fail for deeper documents": {
+			opts:          []ParserOption{WithMaxDepth(5)},
+			expectedLimit: true,
+		},
+		"max depth should allow the exact depth": {
+			opts: []ParserOption{WithMaxDepth(9), WithAllowUnknownFields()},
+		},
+		"extension validators should report invalid extensions": {
+			opts: []ParserOption{
+				WithAllowUnknownFields(),
+				WithExtensionValidator(func(loc string, key string, value *fastjson.Value) error {
+					if key == "x-owner" && value.Type() != fastjson.TypeString {
+						return errors.New("x-owner must be a string")
+					}
+					return nil
+				}),
+			},
+			expectedLocs: []string{".info.x-owner"},
+		},
+		"keyed extension validators should only be called for their key": {
+			opts: []ParserOption{
+				WithAllowUnknownFields(),
+				WithExtensionValidatorFor("x-team", func(string, string, *fastjson.Value) error {
+					return errors.New("always invalid")
+				}),
+			},
+		},
+		"extension schemas should report invalid extensions": {
+			opts: []ParserOption{
+				WithAllowUnknownFields(),
+				WithExtensionSchema("x-owner", &Schema{Type: NewStringOrStrings("string")}),
+			},
+			expectedLocs: []string{".info.x-owner"},
+		},
+		"extension schemas should accept valid extensions": {
+			opts: []ParserOption{
+				WithAllowUnknownFields(),
+				WithExtensionSchema("x-owner", &Schema{Type: NewStringOrStrings("integer"), Minimum: ptr(1.0)}),
+			},
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			_, err := NewParser(raw, tt.opts...).Parse()
+			if tt.expectedLimit {
+				if !errors.Is(err, ErrLimitExceeded) {
+					t.Errorf("Parse() error = %v, want ErrLimitExceeded", err)
+				}
+				return
+			}
+			if len(tt.expectedLocs) == 0 {
+				if err != nil {
+					t.Errorf("Parse() unexpected error = %v", err)
+				}
+				return
+			}
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("Parse() error = %v, want a *ParseError", err)
+			}
+			if got := pe.Locations(); !reflect.DeepEqual(got, tt.expectedLocs) {
+				t.Errorf("Locations() = %v, want %v", got, tt.expectedLocs)
+			}
+			if pe.Truncated != tt.truncated {
+				t.Errorf("Truncated = %t, want %t", pe.Truncated, tt.truncated)
+			}
+		})
+	}
+}
+
+func TestParser_ParseContext(t *testing.T) {
+	raw := []byte(`{
+		"swagger": "2.0",
+		"x-first": true,
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {"/a": {"get": {"responses": {"200": {"description": "ok"}}}}}
+	}`)
+	t.Run("an uncanceled context should parse", func(t *testing.T) {
+		swagger, err := NewParser(raw).ParseContext(context.Background())
+		if err != nil || swagger.OperationCount() != 1 {
+			t.Errorf("ParseContext() = %v, %v", swagger, err)
+		}
+	})
+	t.Run("an already canceled context should not parse", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := NewParser(raw).ParseContext(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("ParseContext() error = %v, want context.Canceled", err)
+		}
+	})
+	t.Run("a context canceled while parsing should stop at the next object", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		parser := NewParser(raw, WithExtensionValidator(func(string, string, *fastjson.Value) error {
+			cancel()
+			return nil
+		}))
+		swagger, err := parser.ParseContext(ctx)
+		if !errors.Is(err, context.Canceled) || swagger != nil {
+			t.Errorf("ParseContext() = %v, %v, want nil and context.Canceled", swagger, err)
+		}
+	})
+}
+
+func TestParseAll(t *testing.T) {
+	raws := map[string][]byte{
+		"valid":   []byte(`{"swagger": "2.0", "info": {"title": "valid", "version": "1"}}`),
+		"invalid": []byte(`{"swagger": "3.0"}`),
+		"empty":   nil,
+	}
+	for i := 0; i < 20; i++ {
+		raws[fmt.Sprintf("spec%d", i)] = []byte(fmt.Sprintf(`{"swagger": "2.0", "info": {"title": "spec%d", "version": "1"}}`, i))
+	}
+	results := ParseAll(context.Background(), raws)
+	if len(results) != len(raws) {
+		t.Fatalf("ParseAll() returned %d results, want %d", len(results), len(raws))
+	}
+	for name, result := range results {
+		switch name {
+		case "invalid", "empty":
+			if result.Err == nil {
+				t.Errorf("ParseAll()[%s] should have failed", name)
+			}
+		default:
+			if result.Err != nil || result.Swagger.Info.Title != name {
+				t.Errorf("ParseAll()[%s] = %v, %v", name, result.Swagger, result.Err)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for name, result := range ParseAll(ctx, raws) {
+		if result.Err == nil {
+			t.Errorf("ParseAll()[%s] with a canceled context should have failed", name)
+		}
+	}
+}
+
+func TestParser_Release(t *testing.T) {
+	WarmPools(2)
+	for i, host := range []string{"one.example.com", "two.example.com"} {
+		p := NewParser([]byte(fmt.Sprintf(`{"swagger": "2.0", "host": %q, "x-id": %d}`, host, i)))
+		swagger, err := p.Parse()
+		if err != nil {
+			t.Fatalf("failed to parse: %s", err)
+		}
+		if swagger.Host != host || swagger.Extensions["x-id"].GetInt() != i {
+			t.Errorf("parse %d got host %q and x-id %s", i, swagger.Host, swagger.Extensions["x-id"])
+		}
+		p.Release()
+		p.Release()
+	}
+}
+
+func TestParser_ParseTwice(t *testing.T) {
+	p := NewParser([]byte(`{"swagger": "2.0", "info": {"title": "first", "version": "1.0"}, "x-id": "first"}`))
+	swagger, err := p.Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	if again, err := p.Parse(); err == nil || again != nil {
+		t.Errorf("Parse() again = %v, %v, want an error", again, err)
+	}
+	// parsing another spec must not reuse the JSON parser still owning the values of the first
+	if _, err = NewParser([]byte(`{"swagger": "2.0", "info": {"title": "second", "version": "2.0"}, "x-id": "second"}`)).Parse(); err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	if id, _ := swagger.Extensions.GetString("x-id"); id != "first" || swagger.Info.Title != "first" {
+		t.Errorf("the first spec changed to x-id %q and title %q", id, swagger.Info.Title)
+	}
+}
+
+func TestParser_DuplicateOperations(t *testing.T) {
+	raw := []byte(`{"paths": {
+		"/pets": {
+			"get": {"summary": "first", "responses": {"200": {"description": "ok"}}},
+			"get": {"summary": "second", "responses": {"200": {"description": "ok"}}}
+		},
+		"/pets": {
+			"get": {"summary": "third", "responses": {"200": {"description": "ok"}}},
+			"post": {"summary": "added", "responses": {"201": {"description": "ok"}}}
+		}
+	}}`)
+	for _, keep := range []bool{false, true} {
+		var opts []ParserOption
+		if keep {
+			opts = append(opts, WithKeepDuplicateOperations())
+		}
+		swagger, err := NewParser(raw, opts...).Parse()
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("expected a *ParseError but got: %v", err)
+		}
+		dups := pe.WithCode(ErrorCodeDuplicateOperation)
+		if len(dups) != 2 {
+			t.Fatalf("got %d duplicate operation errors, want 2: %s", len(dups), err)
+		}
+		var de *DuplicateOperationError
+		if !errors.As(dups[0], &de) || de.Key != (OperationKey{Path: "/pets", Method: "GET"}) ||
+			de.Location != ".paths./pets.get" || de.PreviousLocation != ".paths./pets.get" {
+			t.Errorf("unexpected duplicate operation error: %#v", de)
+		}
+		pi := swagger.Paths.Items["/pets"]
+		if pi.Get.Summary != "first" || swagger.OperationMap()[pi.Get.Key] != pi.Get {
+			t.Errorf("the first GET should be kept in both the path item and operations")
+		}
+		if pi.Post == nil || swagger.OperationCount() != 2 {
+			t.Errorf("the POST from the repeated path should be added")
+		}
+		var summaries []string
+		for _, op := range swagger.DuplicateOperations() {
+			summaries = append(summaries, op.Summary)
+		}
+		if keep && !reflect.DeepEqual(summaries, []string{"second", "third"}) || !keep && summaries != nil {
+			t.Errorf("DuplicateOperations() summaries = %v when keeping is %t", summaries, keep)
+		}
+	}
+}
+
+func TestParser_HeaderValidation(t *testing.T) {
+	tests := map[string]struct {
+		header   string
+		expected []string
+	}{
+		"primitive types should be valid": {
+			header: `{"type": "integer"}`,
+		},
+		"arrays with items and a collection format should be valid": {
+			header: `{"type": "array", "items": {"type": "string"}, "collectionFormat": "pipes"}`,
+		},
+		"object types should be invalid": {
+			header:   `{"type": "object"}`,
+			expected: []string{"header type should be one of [string number integer boolean array] but got: 'object'"},
+		},
+		"arrays without items should be invalid": {
+			header:   `{"type": "array"}`,
+			expected: []string{"header of type 'array' requires items"},
+		},
+		"multi collection format should be invalid": {
+			header:   `{"type": "array", "items": {"type": "string"}, "collectionFormat": "multi"}`,
+			expected: []string{"header collectionFormat should be one of [csv ssv tsv pipes] but got: 'multi'"},
+		},
+		"collection format without an array type should be invalid": {
+			header:   `{"type": "string", "collectionFormat": "csv"}`,
+			expected: []string{"header collectionFormat requires type 'array' but got: 'string'"},
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			raw := fmt.Sprintf(`{
+				"swagger": "2.0",
+				"info": {"title": "test", "version": "1.0"},
+				"paths": {"/a": {"get": {"responses": {"200": {"description": "ok", "headers": {"X-Test": %s}}}}}}
+			}`, tt.header)
+			_, err := NewParser([]byte(raw)).Parse()
+			var got []string
+			var pe *ParseError
+			if errors.As(err, &pe) {
+				for _, e := range pe.WithCode(ErrorCodeInvalidValue) {
+					got = append(got, e.Err.Error())
+				}
+			} else if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("errors = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// ptr returns a pointer to the value for setting optional constraints
+func ptr[T any](v T) *T {
+	return &v
+}
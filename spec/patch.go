@@ -0,0 +1,359 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation is a single RFC 6902 JSON Patch operation
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// JSONPointer returns the RFC 6901 JSON Pointer of the reference tokens, escaping each of them
+func JSONPointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(token))
+	}
+	return b.String()
+}
+
+// ErrInvalidPatch is returned by ApplyPatch when the patch is malformed or cannot be applied to the spec
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// ApplyPatch returns a new spec from applying either a RFC 6902 JSON Patch, when the patch is an array, or a RFC 7386
+// JSON Merge Patch, when it is an object, to the JSON encoding of the spec. The patched document is then parsed with
+// the options, returning any ParseError so that the result is validated the same as any other spec.
+func ApplyPatch(swagger *Swagger, patch []byte, opts ...ParserOption) (*Swagger, error) {
+	raw, err := swagger.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	patch = bytes.TrimSpace(patch)
+	switch {
+	case len(patch) > 0 && patch[0] == '[':
+		var ops []rawPatchOperation
+		if err = json.Unmarshal(patch, &ops); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+		}
+		for i, op := range ops {
+			if doc, err = op.apply(doc); err != nil {
+				return nil, fmt.Errorf("%w: operation %d: %s", ErrInvalidPatch, i, err)
+			}
+		}
+	case len(patch) > 0 && patch[0] == '{':
+		mp, e := decodeJSON(patch)
+		if e != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, e)
+		}
+		doc = mergePatch(doc, mp)
+	default:
+		return nil, fmt.Errorf("%w: must be a JSON Patch array or a JSON Merge Patch object", ErrInvalidPatch)
+	}
+	if raw, err = json.Marshal(doc); err != nil {
+		return nil, err
+	}
+	return NewParser(raw, opts...).Parse()
+}
+
+// rawPatchOperation keeps the value undecoded so that an explicit null can be told apart from a missing value
+type rawPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+func (op rawPatchOperation) apply(doc any) (any, error) {
+	if op.Path == nil {
+		return nil, errors.New("missing path")
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+	var from []string
+	switch op.Op {
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("missing from for %s", op.Op)
+		}
+		if from, err = parsePointer(*op.From); err != nil {
+			return nil, err
+		}
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("missing value for %s", op.Op)
+		}
+	}
+	var value any
+	if op.Value != nil {
+		if value, err = decodeJSON(op.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return pointerAdd(doc, path, value)
+	case "remove":
+		return pointerRemove(doc, path)
+	case "replace":
+		if doc, err = pointerRemove(doc, path); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value)
+	case "move":
+		if len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+			return nil, fmt.Errorf("cannot move %s into its own child %s", *op.From, *op.Path)
+		}
+		if value, err = pointerGet(doc, from); err != nil {
+			return nil, err
+		}
+		if doc, err = pointerRemove(doc, from); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value)
+	case "copy":
+		if value, err = pointerGet(doc, from); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, copyJSON(value))
+	case "test":
+		actual, e := pointerGet(doc, path)
+		if e != nil {
+			return nil, e
+		}
+		if !equalJSON(actual, value) {
+			return nil, fmt.Errorf("test failed at %s", *op.Path)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unsupported op: '%s'", op.Op)
+}
+
+// parsePointer returns the unescaped reference tokens of the RFC 6901 JSON Pointer
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer: '%s'", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+	return tokens, nil
+}
+
+// arrayIndex returns the index of the token within an array of the length, allowing the length itself when appending
+func arrayIndex(token string, length int, appending bool) (int, error) {
+	if appending && token == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index: '%s'", token)
+	}
+	if i > length || (i == length && !appending) {
+		return 0, fmt.Errorf("array index out of bounds: %d", i)
+	}
+	return i, nil
+}
+
+func pointerGet(doc any, tokens []string) (any, error) {
+	for _, token := range tokens {
+		switch v := doc.(type) {
+		case map[string]any:
+			child, exists := v[token]
+			if !exists {
+				return nil, fmt.Errorf("missing member: '%s'", token)
+			}
+			doc = child
+		case []any:
+			i, err := arrayIndex(token, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = v[i]
+		default:
+			return nil, fmt.Errorf("cannot reference '%s' within a scalar value", token)
+		}
+	}
+	return doc, nil
+}
+
+// pointerUpdate replaces the parent container of the last token with the result of the update, returning the
+// updated document as arrays may need to be reallocated
+func pointerUpdate(doc any, tokens []string, update func(parent any, token string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return update(doc, tokens[0])
+	}
+	child, err := pointerGet(doc, tokens[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = pointerUpdate(child, tokens[1:], update); err != nil {
+		return nil, err
+	}
+	switch v := doc.(type) {
+	case map[string]any:
+		v[tokens[0]] = child
+	case []any:
+		i, _ := arrayIndex(tokens[0], len(v), false)
+		v[i] = child
+	}
+	return doc, nil
+}
+
+func pointerAdd(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return pointerUpdate(doc, tokens, func(parent any, token string) (any, error) {
+		switch v := parent.(type) {
+		case map[string]any:
+			v[token] = value
+			return v, nil
+		case []any:
+			i, err := arrayIndex(token, len(v), true)
+			if err != nil {
+				return nil, err
+			}
+			v = append(v, nil)
+			copy(v[i+1:], v[i:])
+			v[i] = value
+			return v, nil
+		}
+		return nil, fmt.Errorf("cannot add '%s' to a scalar value", token)
+	})
+}
+
+func pointerRemove(doc any, tokens []string) (any, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	return pointerUpdate(doc, tokens, func(parent any, token string) (any, error) {
+		switch v := parent.(type) {
+		case map[string]any:
+			if _, exists := v[token]; !exists {
+				return nil, fmt.Errorf("missing member: '%s'", token)
+			}
+			delete(v, token)
+			return v, nil
+		case []any:
+			i, err := arrayIndex(token, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(v[:i], v[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove '%s' from a scalar value", token)
+	})
+}
+
+// mergePatch applies the RFC 7386 JSON Merge Patch to the target
+func mergePatch(target, patch any) any {
+	p, isObject := patch.(map[string]any)
+	if !isObject {
+		return patch
+	}
+	t, isObject := target.(map[string]any)
+	if !isObject {
+		t = make(map[string]any, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
+
+// decodeJSON decodes the raw JSON keeping numbers as json.Number so that they are re-encoded as authored
+func decodeJSON(raw []byte) (any, error) {
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func copyJSON(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(t))
+		for k, e := range t {
+			result[k] = copyJSON(e)
+		}
+		return result
+	case []any:
+		result := make([]any, len(t))
+		for i, e := range t {
+			result[i] = copyJSON(e)
+		}
+		return result
+	}
+	return v
+}
+
+// equalJSON compares decoded JSON values, treating numbers as equal by value rather than by their encoding
+func equalJSON(a, b any) bool {
+	switch ta := a.(type) {
+	case json.Number:
+		tb, isNumber := b.(json.Number)
+		if !isNumber {
+			return false
+		}
+		fa, errA := ta.Float64()
+		fb, errB := tb.Float64()
+		return errA == nil && errB == nil && fa == fb
+	case map[string]any:
+		tb, isObject := b.(map[string]any)
+		if !isObject || len(ta) != len(tb) {
+			return false
+		}
+		for k, v := range ta {
+			if w, exists := tb[k]; !exists || !equalJSON(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		tb, isArray := b.([]any)
+		if !isArray || len(ta) != len(tb) {
+			return false
+		}
+		for i := range ta {
+			if !equalJSON(ta[i], tb[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
@@ -0,0 +1,102 @@
+package spec
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	swagger, err := NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "pets", "version": "1.0"},
+		"tags": [{"name": "pets"}, {"name": "internal"}],
+		"paths": {
+			"/pets": {"get": {"operationId": "listPets", "tags": ["pets"], "responses": {"200": {"description": "ok"}}}},
+			"/admin": {"get": {"operationId": "admin", "x-internal": true, "responses": {"200": {"description": "ok"}}}}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	tests := map[string]struct {
+		patch    string
+		expected string
+		errIs    error
+		parseErr bool
+	}{
+		"add host and remove path with json patch": {
+			patch: `[
+				{"op": "test", "path": "/paths/~1admin/get/x-internal", "value": true},
+				{"op": "remove", "path": "/paths/~1admin"},
+				{"op": "add", "path": "/host", "value": "api.example.com"},
+				{"op": "remove", "path": "/tags/1"},
+				{"op": "add", "path": "/tags/-", "value": {"name": "public"}}
+			]`,
+			expected: `{"swagger":"2.0","info":{"title":"pets","version":"1.0"},"host":"api.example.com","paths":{"/pets":{"get":{"tags":["pets"],"operationId":"listPets","responses":{"200":{"description":"ok"}}}}},"tags":[{"name":"pets","description":""},{"name":"public","description":""}]}`,
+		},
+		"move and copy": {
+			patch: `[
+				{"op": "copy", "from": "/info/title", "path": "/info/description"},
+				{"op": "move", "from": "/paths/~1admin", "path": "/paths/~1admin~1v2"},
+				{"op": "replace", "path": "/info/version", "value": "2.0"}
+			]`,
+			expected: `{"swagger":"2.0","info":{"title":"pets","description":"pets","version":"2.0"},"paths":{"/admin/v2":{"get":{"operationId":"admin","responses":{"200":{"description":"ok"}},"x-internal":true}},"/pets":{"get":{"tags":["pets"],"operationId":"listPets","responses":{"200":{"description":"ok"}}}}},"tags":[{"name":"pets","description":""},{"name":"internal","description":""}]}`,
+		},
+		"merge patch": {
+			patch:    `{"host": "api.example.com", "paths": {"/admin": null}, "tags": [{"name": "pets"}]}`,
+			expected: `{"swagger":"2.0","info":{"title":"pets","version":"1.0"},"host":"api.example.com","paths":{"/pets":{"get":{"tags":["pets"],"operationId":"listPets","responses":{"200":{"description":"ok"}}}}},"tags":[{"name":"pets","description":""}]}`,
+		},
+		"failed test": {
+			patch: `[{"op": "test", "path": "/info/version", "value": "2.0"}]`,
+			errIs: ErrInvalidPatch,
+		},
+		"missing member": {
+			patch: `[{"op": "remove", "path": "/host"}]`,
+			errIs: ErrInvalidPatch,
+		},
+		"index out of bounds": {
+			patch: `[{"op": "add", "path": "/tags/3", "value": {"name": "x"}}]`,
+			errIs: ErrInvalidPatch,
+		},
+		"unsupported op": {
+			patch: `[{"op": "upsert", "path": "/host", "value": "x"}]`,
+			errIs: ErrInvalidPatch,
+		},
+		"not a patch": {
+			patch: `"host"`,
+			errIs: ErrInvalidPatch,
+		},
+		"invalid result": {
+			patch:    `{"paths": {"/pets": {"get": {"operationId": ""}}}}`,
+			parseErr: true,
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			result, err := ApplyPatch(swagger, []byte(tt.patch))
+			if tt.errIs != nil {
+				if !errors.Is(err, tt.errIs) {
+					t.Fatalf("expected error %v but got %v", tt.errIs, err)
+				}
+				return
+			}
+			if tt.parseErr {
+				var pe *ParseError
+				if !errors.As(err, &pe) {
+					t.Fatalf("expected a ParseError but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, _ := result.MarshalJSON()
+			if string(got) != tt.expected {
+				t.Errorf("got:\n%s\nexpected:\n%s", got, tt.expected)
+			}
+		})
+	}
+	if _, exists := swagger.Paths.Items["/admin"]; !exists {
+		t.Error("the original spec was modified")
+	}
+}
@@ -3,6 +3,7 @@ package spec
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/valyala/fastjson"
 )
@@ -29,6 +30,75 @@ func NewPathItem() *PathItem {
 	}
 }
 
+// Operation returns the Operation for the HTTP method or nil if there is none
+func (pi *PathItem) Operation(method string) *Operation {
+	if pi == nil {
+		return nil
+	}
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		return pi.Get
+	case http.MethodPut:
+		return pi.Put
+	case http.MethodPost:
+		return pi.Post
+	case http.MethodDelete:
+		return pi.Delete
+	case http.MethodOptions:
+		return pi.Options
+	case http.MethodHead:
+		return pi.Head
+	case http.MethodPatch:
+		return pi.Patch
+	}
+	return nil
+}
+
+// pathItemMethods are the HTTP methods of the operations of a PathItem
+var pathItemMethods = []string{
+	http.MethodGet,
+	http.MethodPut,
+	http.MethodPost,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodHead,
+	http.MethodPatch,
+}
+
+// setFirstOperation sets the Operation for the HTTP method only if there is none, so that it matches the operation
+// kept by the swagger when duplicated
+func (pi *PathItem) setFirstOperation(method string, op *Operation) {
+	if op != nil && pi.Operation(method) == nil {
+		pi.SetOperation(method, op)
+	}
+}
+
+// SetOperation sets the Operation for the HTTP method and returns false if the method is not supported by swagger
+func (pi *PathItem) SetOperation(method string, op *Operation) bool {
+	if pi == nil {
+		return false
+	}
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		pi.Get = op
+	case http.MethodPut:
+		pi.Put = op
+	case http.MethodPost:
+		pi.Post = op
+	case http.MethodDelete:
+		pi.Delete = op
+	case http.MethodOptions:
+		pi.Options = op
+	case http.MethodHead:
+		pi.Head = op
+	case http.MethodPatch:
+		pi.Patch = op
+	default:
+		return false
+	}
+	return true
+}
+
 // Paths defines the Paths swagger object
 // https://swagger.io/specification/v2/#paths-object
 type Paths struct {
@@ -45,78 +115,120 @@ func NewPaths() *Paths {
 }
 
 func parsePathItem(val *fastjson.Value, parser *Parser, path string) *PathItem {
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	parser.checkContext()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid path item value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid path item value: %w", err))
 		return nil
 	}
 	result := NewPathItem()
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "get"):
-			result.Get = parseOperation(v, parser, path, http.MethodGet)
+			result.setFirstOperation(http.MethodGet, parseOperation(v, parser, path, http.MethodGet))
 		case matchString(key, "put"):
-			result.Put = parseOperation(v, parser, path, http.MethodPut)
+			result.setFirstOperation(http.MethodPut, parseOperation(v, parser, path, http.MethodPut))
 		case matchString(key, "post"):
-			result.Post = parseOperation(v, parser, path, http.MethodPost)
+			result.setFirstOperation(http.MethodPost, parseOperation(v, parser, path, http.MethodPost))
 		case matchString(key, "delete"):
-			result.Delete = parseOperation(v, parser, path, http.MethodDelete)
+			result.setFirstOperation(http.MethodDelete, parseOperation(v, parser, path, http.MethodDelete))
 		case matchString(key, "options"):
-			result.Options = parseOperation(v, parser, path, http.MethodOptions)
+			result.setFirstOperation(http.MethodOptions, parseOperation(v, parser, path, http.MethodOptions))
 		case matchString(key, "head"):
-			result.Head = parseOperation(v, parser, path, http.MethodHead)
+			result.setFirstOperation(http.MethodHead, parseOperation(v, parser, path, http.MethodHead))
 		case matchString(key, "patch"):
-			result.Patch = parseOperation(v, parser, path, http.MethodPatch)
+			result.setFirstOperation(http.MethodPatch, parseOperation(v, parser, path, http.MethodPatch))
 		case matchString(key, "parameters"):
 			if vals, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid parameters value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid parameters value: %w", e))
 			} else {
-				paramsLoc := parser.currentLoc
+				paramsLoc := parser.mark()
 				for i, paramVal := range vals {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", paramsLoc, i)
+					parser.atIndex(paramsLoc, i)
 					if p := parseParameter(paramVal, parser); p != nil {
 						result.Parameters = append(result.Parameters, *p)
 					}
 				}
 			}
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
 
 func parsePaths(val *fastjson.Value, parser *Parser) *Paths {
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	parser.checkContext()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid result value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid result value: %w", err))
 		return nil
 	}
 	result := NewPaths()
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		keyStr := string(key)
 		switch {
 		case matchPath(key):
 			if pi := parsePathItem(v, parser, keyStr); pi != nil {
-				result.Items[keyStr] = pi
+				if existing := result.Items[keyStr]; existing != nil {
+					// a repeated path only contributes the operations not already defined
+					for _, method := range pathItemMethods {
+						existing.setFirstOperation(method, pi.Operation(method))
+					}
+				} else {
+					result.Items[keyStr] = pi
+				}
 			}
 		case matchExtension(key):
-			result.Extensions[keyStr] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
+
+func (pi *PathItem) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setString(a, val, "$ref", pi.Ref.URI())
+	for _, m := range []struct {
+		key string
+		op  *Operation
+	}{
+		{"get", pi.Get},
+		{"put", pi.Put},
+		{"post", pi.Post},
+		{"delete", pi.Delete},
+		{"options", pi.Options},
+		{"head", pi.Head},
+		{"patch", pi.Patch},
+	} {
+		if m.op != nil {
+			val.Set(m.key, m.op.marshal(a))
+		}
+	}
+	if len(pi.Parameters) > 0 {
+		val.Set("parameters", marshalParameters(a, pi.Parameters))
+	}
+	pi.marshalExtensions(val)
+	return val
+}
+
+func (p *Paths) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	for _, path := range sortedKeys(p.Items) {
+		if pi := p.Items[path]; pi != nil {
+			val.Set(path, pi.marshal(a))
+		}
+	}
+	p.marshalExtensions(val)
+	return val
+}
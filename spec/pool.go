@@ -0,0 +1,31 @@
+package spec
+
+import "github.com/valyala/fastjson"
+
+var (
+	arenaPool  fastjson.ArenaPool
+	parserPool fastjson.ParserPool
+)
+
+// WarmPools pre-allocates count JSON parsers and arenas into the pools shared by all parsers. Pooled items not in use
+// may still be freed by the garbage collector, so this only reduces allocations for an initial burst of parsing.
+func WarmPools(count int) {
+	for i := 0; i < count; i++ {
+		parserPool.Put(new(fastjson.Parser))
+		arenaPool.Put(new(fastjson.Arena))
+	}
+}
+
+// Release returns the underlying JSON parser to the shared pool for reuse by later parsers. The parsed values are
+// owned by that JSON parser, so the *Swagger returned by Parse and any values within it must not be used after
+// calling Release unless parsed WithDetachedValues. Calling Release is optional, unreleased parsers are simply garbage
+// collected.
+func (p *Parser) Release() {
+	if p == nil || p.jp == nil {
+		return
+	}
+	parserPool.Put(p.jp)
+	p.jp = nil
+	p.rootVal = nil
+	p.swagger = nil
+}
@@ -0,0 +1,335 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mainFileNames are the names of the main file of a project in the order they are looked for
+var mainFileNames = []string{"swagger.json", "api.json"}
+
+// ProjectSource is where part of the spec of a Project was defined
+type ProjectSource struct {
+	// File is the path of the source file relative to the root of the project
+	File string
+	// Location is within the source file in the same form used by ParseError
+	Location string
+}
+
+// Project is a swagger spec merged from a main file and the sibling files it references
+type Project struct {
+	Swagger *Swagger
+	Root    string
+	// Main is the path of the main file relative to the root
+	Main string
+	// Files are the sorted paths of every file merged into the spec relative to the root, including the main file
+	Files []string
+	// sources are where each object merged from another file was defined by its location within the spec
+	sources map[string]ProjectSource
+}
+
+// Source returns where the object at the location within the merged spec, such as the location of a ParseError, was
+// defined
+func (p *Project) Source(loc string) ProjectSource {
+	var (
+		best    ProjectSource
+		bestLen = -1
+	)
+	for prefix, src := range p.sources {
+		if len(prefix) <= bestLen || !strings.HasPrefix(loc, prefix) {
+			continue
+		}
+		if rest := loc[len(prefix):]; rest == "" || rest[0] == '.' || rest[0] == '[' {
+			best = ProjectSource{File: src.File, Location: src.Location + rest}
+			bestLen = len(prefix)
+		}
+	}
+	if bestLen < 0 {
+		return ProjectSource{File: p.Main, Location: loc}
+	}
+	if best.Location == "" {
+		best.Location = "."
+	}
+	return best
+}
+
+// LoadProject loads the swagger spec of the project in the root directory, being the first of swagger.json or api.json
+// found, or otherwise the only JSON file declaring a swagger version, and merges in every file it references. The
+// schemas referenced from other files are added to the definitions, renamed if their names are already defined, while
+// any other objects referenced are inlined. Any *ParseError is returned along with the project so that the Source of
+// each error location can be found.
+func LoadProject(rootDir string, opts ...ParserOption) (*Project, error) {
+	root, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	main, err := findMainFile(root)
+	if err != nil {
+		return nil, err
+	}
+	b := &projectBundler{
+		root:     root,
+		main:     main,
+		docs:     make(map[string]any),
+		hoisted:  make(map[string]string),
+		inlining: make(map[string]bool),
+		sources:  make(map[string]ProjectSource),
+	}
+	doc, err := b.load(main)
+	if err != nil {
+		return nil, err
+	}
+	obj, isObject := doc.(map[string]any)
+	if !isObject {
+		return nil, fmt.Errorf("main file %s is not a JSON object", b.rel(main))
+	}
+	b.definitions, _ = obj["definitions"].(map[string]any)
+	if b.definitions == nil {
+		b.definitions = make(map[string]any)
+	}
+	if _, err = b.resolve(obj, main, "", "", false); err != nil {
+		return nil, err
+	}
+	if len(b.definitions) > 0 {
+		obj["definitions"] = b.definitions
+	}
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &Project{
+		Root:    root,
+		Main:    b.rel(main),
+		sources: b.sources,
+	}
+	for file := range b.docs {
+		project.Files = append(project.Files, b.rel(file))
+	}
+	sort.Strings(project.Files)
+	project.Swagger, err = NewParser(raw, opts...).Parse()
+	return project, err
+}
+
+// findMainFile returns the absolute path of the main file of the project in the root directory
+func findMainFile(root string) (string, error) {
+	for _, name := range mainFileNames {
+		path := filepath.Join(root, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	matches, err := filepath.Glob(filepath.Join(root, "*.json"))
+	if err != nil {
+		return "", err
+	}
+	var candidates []string
+	for _, path := range matches {
+		raw, e := os.ReadFile(path)
+		if e != nil {
+			return "", e
+		}
+		var header struct {
+			Swagger string `json:"swagger"`
+		}
+		if json.Unmarshal(raw, &header) == nil && header.Swagger != "" {
+			candidates = append(candidates, path)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no swagger file found in %s", root)
+	case 1:
+		return candidates[0], nil
+	}
+	return "", fmt.Errorf("more than one swagger file found in %s: %s", root, strings.Join(candidates, ", "))
+}
+
+// projectBundler merges the files referenced by the main file of a project into its document
+type projectBundler struct {
+	root string
+	main string
+	// docs are the decoded documents by their absolute paths
+	docs map[string]any
+	// definitions of the main document which any referenced schemas are added to
+	definitions map[string]any
+	// hoisted are the names of the definitions added for each referenced schema by its file and fragment
+	hoisted map[string]string
+	// inlining guards against circular references between inlined objects
+	inlining map[string]bool
+	sources  map[string]ProjectSource
+}
+
+func (b *projectBundler) rel(path string) string {
+	if rel, err := filepath.Rel(b.root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+func (b *projectBundler) load(path string) (any, error) {
+	if doc, loaded := b.docs[path]; loaded {
+		return doc, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", b.rel(path), err)
+	}
+	b.docs[path] = doc
+	return doc, nil
+}
+
+// lookup returns the value at the fragment of the file along with its location within that file
+func (b *projectBundler) lookup(file, fragment string) (any, string, error) {
+	doc, err := b.load(file)
+	if err != nil {
+		return nil, "", err
+	}
+	tokens, err := parsePointer(fragment)
+	if err != nil {
+		return nil, "", err
+	}
+	var loc strings.Builder
+	for _, token := range tokens {
+		if arr, isArray := doc.([]any); isArray {
+			i, e := arrayIndex(token, len(arr), false)
+			if e != nil {
+				return nil, "", fmt.Errorf("%s#%s: %w", b.rel(file), fragment, e)
+			}
+			doc = arr[i]
+			loc.WriteString("[" + token + "]")
+			continue
+		}
+		if doc, err = pointerGet(doc, []string{token}); err != nil {
+			return nil, "", fmt.Errorf("%s#%s: %w", b.rel(file), fragment, err)
+		}
+		loc.WriteString("." + token)
+	}
+	return doc, loc.String(), nil
+}
+
+// resolve replaces the references to other files within the value from the file at the source location, which is
+// found at the location within the merged document, where within a schema references are to definitions
+func (b *projectBundler) resolve(v any, file, srcLoc, loc string, inSchema bool) (any, error) {
+	switch t := v.(type) {
+	case map[string]any:
+		if ref, isRef := t["$ref"].(string); isRef {
+			return b.resolveRef(t, ref, file, loc, inSchema)
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if strings.HasPrefix(k, "x-") {
+				continue
+			}
+			// every schema is either beneath a schema field or the definitions
+			childSchema := inSchema || k == "schema" || (loc == "" && k == "definitions")
+			resolved, err := b.resolve(t[k], file, srcLoc+"."+k, loc+"."+k, childSchema)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = resolved
+		}
+	case []any:
+		for i := range t {
+			idx := "[" + strconv.Itoa(i) + "]"
+			resolved, err := b.resolve(t[i], file, srcLoc+idx, loc+idx, inSchema)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = resolved
+		}
+	}
+	return v, nil
+}
+
+func (b *projectBundler) resolveRef(obj map[string]any, ref, file, loc string, inSchema bool) (any, error) {
+	target, fragment, err := b.refTarget(file, ref)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.rel(file), err)
+	}
+	if target == b.main {
+		obj["$ref"] = "#" + fragment
+		return obj, nil
+	}
+	if inSchema {
+		name, e := b.hoist(target, fragment)
+		if e != nil {
+			return nil, e
+		}
+		obj["$ref"] = "#/definitions/" + pointerEscaper.Replace(name)
+		return obj, nil
+	}
+	key := target + "#" + fragment
+	if b.inlining[key] {
+		return nil, fmt.Errorf("circular reference to %s#%s", b.rel(target), fragment)
+	}
+	b.inlining[key] = true
+	defer delete(b.inlining, key)
+	value, srcLoc, err := b.lookup(target, fragment)
+	if err != nil {
+		return nil, err
+	}
+	b.sources[loc] = ProjectSource{File: b.rel(target), Location: srcLoc}
+	return b.resolve(copyJSON(value), target, srcLoc, loc, inSchema)
+}
+
+// hoist adds the schema at the fragment of the file to the definitions, returning its name
+func (b *projectBundler) hoist(file, fragment string) (string, error) {
+	key := file + "#" + fragment
+	if name, exists := b.hoisted[key]; exists {
+		return name, nil
+	}
+	value, srcLoc, err := b.lookup(file, fragment)
+	if err != nil {
+		return "", err
+	}
+	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	if tokens, _ := parsePointer(fragment); len(tokens) > 0 {
+		base = tokens[len(tokens)-1]
+	}
+	name := base
+	for i := 2; b.definitions[name] != nil; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	// the name is reserved before resolving the schema so that it may reference itself
+	b.hoisted[key] = name
+	b.definitions[name] = struct{}{}
+	loc := ".definitions." + name
+	b.sources[loc] = ProjectSource{File: b.rel(file), Location: srcLoc}
+	resolved, err := b.resolve(copyJSON(value), file, srcLoc, loc, true)
+	if err != nil {
+		return "", err
+	}
+	b.definitions[name] = resolved
+	return name, nil
+}
+
+// refTarget returns the absolute path of the file and the fragment referenced from the file
+func (b *projectBundler) refTarget(file, ref string) (string, string, error) {
+	path, fragment, _ := strings.Cut(ref, "#")
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	if path == "" {
+		return file, fragment, nil
+	}
+	if u, err := url.Parse(path); err == nil && u.Scheme != "" {
+		return "", "", errors.New("remote reference is not supported: " + ref)
+	}
+	return filepath.Join(filepath.Dir(file), filepath.FromSlash(path)), fragment, nil
+}
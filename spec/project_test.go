@@ -0,0 +1,142 @@
+package spec
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadProject(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"petstore.json": `{
+			"swagger": "2.0",
+			"info": {"title": "pets", "version": "1.0"},
+			"paths": {
+				"/pets": {"$ref": "paths/pets.json"}
+			},
+			"definitions": {
+				"Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+			}
+		}`,
+		"paths/pets.json": `{
+			"get": {
+				"parameters": [{"$ref": "../common.json#/parameters/limit"}],
+				"responses": {
+					"200": {"description": "ok", "schema": {"type": "array", "items": {"$ref": "../models/pet.json"}}},
+					"default": {"description": "error", "schema": {"$ref": "../common.json#/definitions/Error"}}
+				}
+			}
+		}`,
+		"models/pet.json": `{
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"parent": {"$ref": "#"},
+				"owner": {"$ref": "owner.json#/Owner"}
+			}
+		}`,
+		"models/owner.json": `{"Owner": {"type": "object", "properties": {"name": {"type": "number", "minLength": "x"}}}}`,
+		"common.json": `{
+			"parameters": {"limit": {"name": "limit", "in": "query", "type": "integer"}},
+			"definitions": {"Error": {"type": "object", "properties": {"code": {"type": "integer"}}}}
+		}`,
+		"notes.json": `{"not": "a swagger file"}`,
+	})
+	project, err := LoadProject(dir)
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("LoadProject() error = %v, want a *ParseError", err)
+	}
+	if project.Main != "petstore.json" {
+		t.Errorf("Main = %s", project.Main)
+	}
+	expectedFiles := []string{"common.json", "models/owner.json", "models/pet.json", "paths/pets.json", "petstore.json"}
+	if !reflect.DeepEqual(project.Files, expectedFiles) {
+		t.Errorf("Files = %v, want %v", project.Files, expectedFiles)
+	}
+
+	swagger := project.Swagger
+	op := swagger.Paths.Items["/pets"].Get
+	if op == nil || len(op.Parameters) != 1 || op.Parameters[0].Name != "limit" {
+		t.Fatalf("expected the path item and parameter to be inlined but got %+v", op)
+	}
+	if ref := op.Responses.ByStatusCode[200].Schema.Items.Values()[0].Ref.URI(); ref != "#/definitions/pet" {
+		t.Errorf("items ref = %s", ref)
+	}
+	if ref := op.Responses.Default.Schema.Ref.URI(); ref != "#/definitions/Error2" {
+		t.Errorf("default schema ref = %s", ref)
+	}
+	pet := swagger.Definitions["pet"]
+	if ref := pet.Properties["parent"].Ref.URI(); ref != "#/definitions/pet" {
+		t.Errorf("self reference = %s", ref)
+	}
+	if ref := pet.Properties["owner"].Ref.URI(); ref != "#/definitions/Owner" {
+		t.Errorf("owner reference = %s", ref)
+	}
+	if _, exists := swagger.Definitions["Error"].Properties["message"]; !exists {
+		t.Error("the main file's Error definition should be kept")
+	}
+
+	for _, loc := range pe.Locations() {
+		if src := project.Source(loc); src.File != "models/owner.json" || src.Location != ".Owner.properties.name.minLength" {
+			t.Errorf("Source(%s) = %+v", loc, src)
+		}
+	}
+	tests := map[string]ProjectSource{
+		".paths./pets.get.parameters[0].name": {File: "common.json", Location: ".parameters.limit.name"},
+		".paths./pets.get":                    {File: "paths/pets.json", Location: ".get"},
+		".definitions.Error2":                 {File: "common.json", Location: ".definitions.Error"},
+		".definitions.Error":                  {File: "petstore.json", Location: ".definitions.Error"},
+		".info":                               {File: "petstore.json", Location: ".info"},
+	}
+	for loc, expected := range tests {
+		if got := project.Source(loc); got != expected {
+			t.Errorf("Source(%s) = %+v, want %+v", loc, got, expected)
+		}
+	}
+}
+
+func TestLoadProject_Errors(t *testing.T) {
+	tests := map[string]map[string]string{
+		"no swagger file": {"notes.json": `{}`},
+		"more than one swagger file": {
+			"a.json": `{"swagger": "2.0"}`,
+			"b.json": `{"swagger": "2.0"}`,
+		},
+		"missing referenced file": {
+			"swagger.json": `{"swagger": "2.0", "paths": {"/a": {"$ref": "missing.json"}}}`,
+		},
+		"circular inlined references": {
+			"swagger.json": `{"swagger": "2.0", "paths": {"/a": {"$ref": "a.json"}}}`,
+			"a.json":       `{"$ref": "b.json"}`,
+			"b.json":       `{"$ref": "a.json"}`,
+		},
+		"remote references": {
+			"swagger.json": `{"swagger": "2.0", "paths": {"/a": {"$ref": "https://example.com/a.json"}}}`,
+		},
+	}
+	for should, files := range tests {
+		t.Run(should, func(t *testing.T) {
+			if _, err := LoadProject(writeProject(t, files)); err == nil {
+				t.Error("LoadProject() should fail")
+			}
+		})
+	}
+}
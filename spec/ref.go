@@ -21,6 +21,21 @@ func (r *Reference) URI() string {
 	return r.uri
 }
 
+// DefinitionName returns the name of the definition this refers to and if it is a local definition reference
+func (r *Reference) DefinitionName() (string, bool) {
+	return r.definitionKey()
+}
+
+// ParameterName returns the name of the parameter this refers to and if it is a local parameter reference
+func (r *Reference) ParameterName() (string, bool) {
+	full := r.URI()
+	if full == "" {
+		return "", false
+	}
+	frag := strings.TrimPrefix(full, "#/parameters/")
+	return frag, frag != full
+}
+
 // definitionKey returns the definition name portion of the URI and if it is a definition key
 func (r *Reference) definitionKey() (string, bool) {
 	full := r.URI()
@@ -31,11 +46,13 @@ func (r *Reference) definitionKey() (string, bool) {
 	return frag, frag != full
 }
 
+// UniqueDefinitionRefs collects the names of referenced definitions without duplicates in the order first added
 type UniqueDefinitionRefs struct {
 	unique map[string]struct{}
 	refs   []string
 }
 
+// NewUniqueDefinitionRefs returns a new empty UniqueDefinitionRefs with the capacity
 func NewUniqueDefinitionRefs(cap int) *UniqueDefinitionRefs {
 	return &UniqueDefinitionRefs{
 		unique: make(map[string]struct{}, cap),
@@ -43,6 +60,7 @@ func NewUniqueDefinitionRefs(cap int) *UniqueDefinitionRefs {
 	}
 }
 
+// Values returns the definition names in the order first added
 func (u *UniqueDefinitionRefs) Values() []string {
 	if u == nil {
 		return nil
@@ -54,6 +72,7 @@ func (u *UniqueDefinitionRefs) Values() []string {
 	return results
 }
 
+// AddRefs adds the names of any local definitions referenced
 func (u *UniqueDefinitionRefs) AddRefs(refs ...*Reference) {
 	if u == nil {
 		return
@@ -80,6 +99,7 @@ func (u *UniqueDefinitionRefs) addRefStrings(refs []string) {
 	}
 }
 
+// Merge returns a new UniqueDefinitionRefs of the names from this followed by any new ones from the other
 func (u *UniqueDefinitionRefs) Merge(other *UniqueDefinitionRefs) *UniqueDefinitionRefs {
 	if u == nil {
 		return other
@@ -0,0 +1,161 @@
+package spec
+
+import (
+	"errors"
+	"strings"
+)
+
+// TextEdit replaces the bytes of a document from Start up to End with Text
+type TextEdit struct {
+	Start int
+	End   int
+	Text  []byte
+}
+
+// Reparse applies the edit to the raw document this spec was parsed from using WithSourceMap, returning the spec of the
+// edited document along with its bytes. When the edit is within a single definition or path item, only that value is
+// parsed again and this spec is updated in place along with its source ranges, otherwise the whole edited document is
+// parsed. The returned *ParseError includes the errors from the previous parse outside of what was parsed again, though
+// duplicated operationIds are only checked against the operations of the unchanged path items.
+func (s *Swagger) Reparse(raw []byte, edit TextEdit, opts ...ParserOption) (*Swagger, []byte, error) {
+	if edit.Start < 0 || edit.Start > edit.End || edit.End > len(raw) {
+		return nil, nil, errors.New("edit is out of the bounds of the document")
+	}
+	edited := make([]byte, 0, len(raw)-(edit.End-edit.Start)+len(edit.Text))
+	edited = append(append(append(edited, raw[:edit.Start]...), edit.Text...), raw[edit.End:]...)
+	if s != nil && s.sourceMap != nil && s.keyOrder == nil {
+		if reparsed, err := s.reparseSubtree(edited, edit, opts); reparsed {
+			return s, edited, err
+		}
+	}
+	swagger, err := NewParser(edited, append(opts, WithSourceMap())...).Parse()
+	return swagger, edited, err
+}
+
+// reparseSubtree parses again only the definition or path item containing the edit, returning false if there is
+// none or the edited value could not be parsed on its own
+func (s *Swagger) reparseSubtree(edited []byte, edit TextEdit, opts []ParserOption) (reparsed bool, err error) {
+	var (
+		loc      string
+		r        SourceRange
+		path     string
+		defName  string
+		isInside = func(l string) bool {
+			var exists bool
+			r, exists = s.sourceMap[l]
+			// the edit must leave the braces of the value intact
+			return exists && r.Start < edit.Start && edit.End < r.End
+		}
+	)
+	for name := range s.Definitions {
+		if l := ".definitions." + name; isInside(l) {
+			loc, defName = l, name
+			break
+		}
+	}
+	if loc == "" {
+		for p := range s.Paths.Items {
+			if l := ".paths." + p; isInside(l) {
+				loc, path = l, p
+				break
+			}
+		}
+	}
+	if loc == "" {
+		return false, nil
+	}
+	delta := len(edit.Text) - (edit.End - edit.Start)
+	sub := edited[r.Start : r.End+delta]
+
+	p := NewParser(sub, opts...)
+	p.baseLoc = loc
+	p.swagger = NewSwagger()
+	// the JSON parser is released once the value is parsed, so any values kept by this spec must be copied from it
+	p.detachValues = true
+	for key, op := range s.operationMap {
+		if key.Path != path {
+			if op.ID != "" {
+				p.uniqueOperationIDs[op.ID] = key.Location()
+			}
+			p.operationLocations[key] = key.Location()
+		}
+	}
+	p.jp = parserPool.Get()
+	defer p.Release()
+	val, e := p.jp.ParseBytes(sub)
+	if e != nil {
+		return false, nil
+	}
+	defer func() {
+		// any error limit or canceled context falls back to parsing the whole document
+		if r := recover(); r != nil {
+			switch r.(type) {
+			case errorLimitReached, contextDone:
+				reparsed, err = false, nil
+			default:
+				panic(r)
+			}
+		}
+	}()
+	if defName != "" {
+		schema := parseSchema(val, p)
+		if schema == nil {
+			return false, nil
+		}
+		s.Definitions[defName] = *schema
+	} else {
+		pi := parsePathItem(val, p, path)
+		if pi == nil {
+			return false, nil
+		}
+		for key := range s.operationMap {
+			if key.Path == path {
+				delete(s.operationMap, key)
+			}
+		}
+		for _, op := range p.swagger.operationMap {
+			s.addOperation(op)
+		}
+		s.Paths.Items[path] = pi
+		s.InvalidateRoutes()
+	}
+
+	// replace the source ranges within the value and shift those after the edit
+	within := func(l string) bool {
+		return l == loc || strings.HasPrefix(l, loc+".") || strings.HasPrefix(l, loc+"[")
+	}
+	for l, sr := range s.sourceMap {
+		switch {
+		case within(l):
+			delete(s.sourceMap, l)
+		case sr.Start >= edit.End:
+			s.sourceMap[l] = SourceRange{Start: sr.Start + delta, End: sr.End + delta}
+		case sr.End >= edit.End:
+			s.sourceMap[l] = SourceRange{Start: sr.Start, End: sr.End + delta}
+		}
+	}
+	for l, sr := range mapSourcesAt(sub, loc, r.Start) {
+		s.sourceMap[l] = sr
+	}
+
+	if s.parseErrors == nil {
+		s.parseErrors = make(map[string][]error)
+	}
+	for l := range s.parseErrors {
+		if within(l) {
+			delete(s.parseErrors, l)
+		}
+	}
+	for l, errs := range p.errorsByLocation {
+		s.parseErrors[l] = errs
+	}
+	if len(s.parseErrors) == 0 {
+		return true, nil
+	}
+	// the errors are copied so that callers cannot change those kept by this spec
+	byLocation := make(map[string][]error, len(s.parseErrors))
+	for l, errs := range s.parseErrors {
+		byLocation[l] = append([]error(nil), errs...)
+	}
+	return true, &ParseError{ByLocation: byLocation}
+}
@@ -0,0 +1,127 @@
+package spec
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSwagger_Reparse(t *testing.T) {
+	raw := []byte(`{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {"get": {"operationId": "listPets", "responses": {"200": {"description": "ok"}}}},
+    "/owners": {"get": {"operationId": "listOwners", "responses": {"200": {"description": "ok"}}}}
+  },
+  "definitions": {
+    "Pet": {"type": "object", "properties": {"name": {"type": "string"}}},
+    "Owner": {"type": "object", "minProperties": "x"}
+  }
+}`)
+	replace := func(doc []byte, old, text string) TextEdit {
+		start := strings.Index(string(doc), old)
+		return TextEdit{Start: start, End: start + len(old), Text: []byte(text)}
+	}
+	swagger, err := NewParser(raw, WithSourceMap()).Parse()
+	if err == nil {
+		t.Fatal("expected the invalid Owner definition to fail")
+	}
+
+	// an edit within a definition only reparses it
+	reparsed, edited, err := swagger.Reparse(raw, replace(raw, `{"type": "string"}`, `{"type": "integer", "format": "int64"}`))
+	if reparsed != swagger {
+		t.Fatal("Reparse() of a definition should update the spec in place")
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) || !reflect.DeepEqual(pe.Locations(), []string{".definitions.Owner.minProperties"}) {
+		t.Errorf("Reparse() should keep the errors outside of the definition but got %v", err)
+	}
+	if format := swagger.Definitions["Pet"].Properties["name"].Format; format != "int64" {
+		t.Errorf("Pet.name format = %s", format)
+	}
+	if !reflect.DeepEqual(swagger.sourceMap, mapSources(edited)) {
+		t.Error("Reparse() source ranges differ from those of the whole edited document")
+	}
+
+	// an edit within a path item reparses its operations
+	raw = edited
+	reparsed, edited, err = swagger.Reparse(raw, replace(raw, `"listPets"`, `"listOwners"`))
+	if reparsed != swagger {
+		t.Fatal("Reparse() of a path item should update the spec in place")
+	}
+	if !errors.As(err, &pe) || len(pe.WithCode(ErrorCodeDuplicateOperationID)) != 1 {
+		t.Errorf("Reparse() should report the duplicated operationId but got %v", err)
+	}
+	if op, _, _ := swagger.FindOperation("GET", "/pets"); op == nil || op != swagger.Paths.Items["/pets"].Get {
+		t.Error("Reparse() should replace the operations of the path item")
+	}
+	if !reflect.DeepEqual(swagger.sourceMap, mapSources(edited)) {
+		t.Error("Reparse() source ranges differ from those of the whole edited document")
+	}
+
+	// fixing the errors of the reparsed values leaves none
+	raw = edited
+	reparsed, edited, _ = swagger.Reparse(raw, replace(raw, `"listOwners", "responses": {"200": {"description": "ok"}}}},
+    "/owners"`, `"listPets", "responses": {"200": {"description": "ok"}}}},
+    "/owners"`))
+	raw = edited
+	if _, edited, err = reparsed.Reparse(raw, replace(raw, `"minProperties": "x"`, `"minProperties": 1`)); err != nil {
+		t.Errorf("Reparse() unexpected error = %v", err)
+	}
+
+	// an edit outside any definition or path item reparses the whole document
+	raw = edited
+	reparsed, _, err = swagger.Reparse(raw, replace(raw, `"pets"`, `"animals"`))
+	if err != nil || reparsed == swagger || reparsed.Info.Title != "animals" {
+		t.Errorf("Reparse() = %v, %v", reparsed, err)
+	}
+
+	// invalid JSON within a definition reports the whole document as invalid
+	if _, _, err = swagger.Reparse(raw, replace(raw, `"minProperties": 1`, `"minProperties": `)); err == nil {
+		t.Error("Reparse() of invalid JSON should fail")
+	}
+	if _, _, err = swagger.Reparse(raw, TextEdit{Start: 5, End: 2}); err == nil {
+		t.Error("Reparse() of an invalid edit should fail")
+	}
+}
+
+func TestSwagger_Reparse_retainedValues(t *testing.T) {
+	raw := []byte(`{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {},
+  "definitions": {
+    "Pet": {"type": "string", "x-k": "keep", "enum": ["cat", "dog"]}
+  }
+}`)
+	swagger, err := NewParser(raw, WithSourceMap()).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	start := strings.Index(string(raw), `"dog"`)
+	if _, _, err = swagger.Reparse(raw, TextEdit{Start: start, End: start + len(`"dog"`), Text: []byte(`"fish"`)}); err != nil {
+		t.Fatalf("Reparse() unexpected error: %s", err)
+	}
+	// later parses reuse the pooled JSON parsers, which must not overwrite the values kept by the reparsed definition
+	for i := 0; i < 10; i++ {
+		p := NewParser([]byte(`{"swagger": "2.0", "info": {"title": "other", "version": "2.0"}, "paths": {"/x": {"x-y": [1, 2, 3]}}}`))
+		if _, err = p.Parse(); err != nil {
+			t.Fatalf("failed to parse: %s", err)
+		}
+		p.Release()
+	}
+	pet := swagger.Definitions["Pet"]
+	if got, _ := pet.Extensions.GetString("x-k"); got != "keep" {
+		t.Errorf("x-k = %q, want keep", got)
+	}
+	var enum []string
+	for _, v := range pet.Enum {
+		s, _ := StringValue(v)
+		enum = append(enum, s)
+	}
+	if !reflect.DeepEqual(enum, []string{"cat", "fish"}) {
+		t.Errorf("enum = %v, want [cat fish]", enum)
+	}
+}
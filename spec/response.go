@@ -2,6 +2,8 @@ package spec
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/valyala/fastjson"
 )
@@ -39,19 +41,18 @@ func NewResponses() *Responses {
 }
 
 func parseResponses(val *fastjson.Value, parser *Parser) *Responses {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid responses value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid responses value: %w", err))
 		return nil
 	}
 	result := NewResponses()
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "default"):
 			if r := parseResponse(v, parser); r != nil {
@@ -62,28 +63,27 @@ func parseResponses(val *fastjson.Value, parser *Parser) *Responses {
 				result.ByStatusCode[bytesToInt(key)] = r
 			}
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
 
 func parseResponseDefinitions(val *fastjson.Value, parser *Parser) map[string]Response {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid response definitions value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid response definitions value: %w", err))
 		return nil
 	}
 	result := make(map[string]Response, obj.Len())
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		if resp := parseResponse(v, parser); resp != nil {
 			result[string(key)] = *resp
 		}
@@ -92,19 +92,18 @@ func parseResponseDefinitions(val *fastjson.Value, parser *Parser) map[string]Re
 }
 
 func parseResponse(val *fastjson.Value, parser *Parser) *Response {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid response value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid response value: %w", err))
 		return nil
 	}
 	result := NewResponse()
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "description"):
 			parser.parseString(v, "description", false, func(s string) {
@@ -114,22 +113,60 @@ func parseResponse(val *fastjson.Value, parser *Parser) *Response {
 			result.Schema = parseSchema(v, parser)
 		case matchString(key, "headers"):
 			if hMap, e := v.Object(); e != nil {
-				parser.appendError(fmt.Errorf("invalid headers type: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid headers type: %w", e))
 			} else {
 				result.Headers = make(map[string]*Header, hMap.Len())
-				hdrLoc := parser.currentLoc
+				hdrLoc := parser.mark()
 				hMap.Visit(func(hKey []byte, hVal *fastjson.Value) {
-					parser.currentLoc = fmt.Sprintf("%s.%s", hdrLoc, hKey)
+					parser.atKey(hdrLoc, hKey)
 					if hdr := parseHeader(hVal, parser); hdr != nil {
 						result.Headers[string(hKey)] = hdr
 					}
 				})
 			}
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
+
+func (r *Response) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	val.Set("description", a.NewString(r.Description))
+	if r.Schema != nil {
+		val.Set("schema", r.Schema.marshal(a))
+	}
+	if len(r.Headers) > 0 {
+		headers := a.NewObject()
+		for _, name := range sortedKeys(r.Headers) {
+			if h := r.Headers[name]; h != nil {
+				headers.Set(name, h.marshal(a))
+			}
+		}
+		val.Set("headers", headers)
+	}
+	r.marshalExtensions(val)
+	return val
+}
+
+func (r *Responses) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	codes := make([]int, 0, len(r.ByStatusCode))
+	for code := range r.ByStatusCode {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		if resp := r.ByStatusCode[code]; resp != nil {
+			val.Set(strconv.Itoa(code), resp.marshal(a))
+		}
+	}
+	if r.Default != nil {
+		val.Set("default", r.Default.marshal(a))
+	}
+	r.marshalExtensions(val)
+	return val
+}
@@ -0,0 +1,107 @@
+package spec
+
+// routeNode is a node of the route tree indexing the operations of a spec by the segments of their paths
+type routeNode struct {
+	literals  map[string]*routeNode
+	templates []*templateRoute
+	// ops are the operations of the path ending at this node by method
+	ops map[string]*Operation
+}
+
+// templateRoute is the child of a routeNode for a templated segment
+type templateRoute struct {
+	segment string
+	prefix  string
+	suffix  string
+	node    *routeNode
+}
+
+func newRouteTree(ops OperationMap) *routeNode {
+	root := &routeNode{}
+	for key, op := range ops {
+		node := root
+		for _, seg := range splitPath(key.Path) {
+			node = node.child(seg)
+		}
+		if node.ops == nil {
+			node.ops = make(map[string]*Operation)
+		}
+		node.ops[key.Method] = op
+	}
+	return root
+}
+
+// child returns the child node for the segment of a templated path, adding it if needed
+func (n *routeNode) child(seg string) *routeNode {
+	prefix, _, suffix, templated := templateSegment(seg)
+	if !templated {
+		if n.literals == nil {
+			n.literals = make(map[string]*routeNode)
+		}
+		child := n.literals[seg]
+		if child == nil {
+			child = &routeNode{}
+			n.literals[seg] = child
+		}
+		return child
+	}
+	for _, t := range n.templates {
+		if t.segment == seg {
+			return t.node
+		}
+	}
+	t := &templateRoute{segment: seg, prefix: prefix, suffix: suffix, node: &routeNode{}}
+	n.templates = append(n.templates, t)
+	return t.node
+}
+
+// find returns the operation for the method matching the concrete segments with the most literal segments, breaking
+// ties by the lowest path, along with that count of literal segments or -1 if none match
+func (n *routeNode) find(method string, segs []string) (*Operation, int) {
+	if len(segs) == 0 {
+		if op := n.ops[method]; op != nil {
+			return op, 0
+		}
+		return nil, -1
+	}
+	var (
+		best      *Operation
+		bestScore = -1
+	)
+	if child := n.literals[segs[0]]; child != nil {
+		if op, score := child.find(method, segs[1:]); op != nil {
+			best, bestScore = op, score+1
+		}
+	}
+	for _, t := range n.templates {
+		if _, ok := matchSegment(t.prefix, t.suffix, segs[0]); !ok {
+			continue
+		}
+		op, score := t.node.find(method, segs[1:])
+		if op != nil && (score > bestScore || (score == bestScore && op.Key.Path < best.Key.Path)) {
+			best, bestScore = op, score
+		}
+	}
+	return best, bestScore
+}
+
+// routeTree returns the route tree of the operations of this spec, building it if needed
+func (s *Swagger) routeTree() *routeNode {
+	s.routesMu.Lock()
+	defer s.routesMu.Unlock()
+	if s.routes == nil {
+		s.routes = newRouteTree(s.operationMap)
+	}
+	return s.routes
+}
+
+// InvalidateRoutes discards the index used by FindOperation so that it is rebuilt on next use, which is only needed
+// after changing the keys of operations directly rather than by AddOperation or RemoveOperation
+func (s *Swagger) InvalidateRoutes() {
+	if s == nil {
+		return
+	}
+	s.routesMu.Lock()
+	s.routes = nil
+	s.routesMu.Unlock()
+}
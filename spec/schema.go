@@ -18,19 +18,19 @@ type Schema struct {
 	Format                string
 	Title                 string
 	Description           string
-	MultipleOf            int
-	Maximum               int
+	MultipleOf            *float64
+	Maximum               *float64
 	ExclusiveMaximum      bool
-	Minimum               int
+	Minimum               *float64
 	ExclusiveMinimum      bool
-	MaxLength             int
-	MinLength             int
+	MaxLength             *int
+	MinLength             *int
 	Pattern               string
-	MaxItems              int
-	MinItems              int
+	MaxItems              *int
+	MinItems              *int
 	UniqueItems           bool
-	MaxProperties         int
-	MinProperties         int
+	MaxProperties         *int
+	MinProperties         *int
 	Required              []string
 	Enum                  []any
 	Type                  *StringOrStrings
@@ -50,6 +50,7 @@ func NewSchema() *Schema {
 	}
 }
 
+// ReferencedDefinitions returns the definitions referenced from anywhere within this schema or nil if there are none
 func (s *Schema) ReferencedDefinitions() *UniqueDefinitionRefs {
 	if refs := s.allRefs(); len(refs) > 0 {
 		result := NewUniqueDefinitionRefs(len(refs))
@@ -127,7 +128,7 @@ func NewStringOrStrings(s ...string) *StringOrStrings {
 	}
 }
 
-// Values will return itself as a slice of either the single value or the many
+// Values will return itself as a new slice of either the single value or the many
 func (s *StringOrStrings) Values() []string {
 	if s == nil {
 		return nil
@@ -135,7 +136,7 @@ func (s *StringOrStrings) Values() []string {
 	if s.value != nil {
 		return []string{*s.value}
 	}
-	return s.items
+	return append([]string(nil), s.items...)
 }
 
 // SchemaOrSchemas intended for Schema.Items as it may be either one Schema or many
@@ -161,6 +162,25 @@ func NewSchemaOrSchemas(ss ...Schema) *SchemaOrSchemas {
 	}
 }
 
+// AsSchema returns this as a single Schema and if it is a single Schema
+func (s *SchemaOrSchemas) AsSchema() (*Schema, bool) {
+	if s == nil || s.value == nil {
+		return nil, false
+	}
+	return s.value, true
+}
+
+// Values will return itself as a new slice of either the single Schema or the many
+func (s *SchemaOrSchemas) Values() []Schema {
+	if s == nil {
+		return nil
+	}
+	if s.value != nil {
+		return []Schema{*s.value}
+	}
+	return append([]Schema(nil), s.items...)
+}
+
 // SchemaOrBool intended for Schema.AdditionalItems as it may be either a Schema or a bool
 type SchemaOrBool struct {
 	object *Schema
@@ -204,19 +224,18 @@ func (sb *SchemaOrBool) AsSchema() (*Schema, bool) {
 }
 
 func parseDefinitions(val *fastjson.Value, parser *Parser) map[string]Schema {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid security value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid security value: %w", err))
 		return nil
 	}
 	result := make(map[string]Schema, obj.Len())
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		if s := parseSchema(v, parser); s != nil {
 			result[string(key)] = *s
 		}
@@ -225,19 +244,18 @@ func parseDefinitions(val *fastjson.Value, parser *Parser) map[string]Schema {
 }
 
 func parseSchema(val *fastjson.Value, parser *Parser) *Schema {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid schema value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid schema value: %w", err))
 		return nil
 	}
 	result := NewSchema()
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "$ref"):
 			parser.parseString(v, "$ref", false, func(s string) {
@@ -256,22 +274,22 @@ func parseSchema(val *fastjson.Value, parser *Parser) *Schema {
 				result.Description = s
 			})
 		case matchString(key, "default"):
-			result.Default = v
+			result.Default = detachValue(v)
 		case matchString(key, "multipleOf"):
-			parser.parseInt(v, "multipleOf", func(i int) {
-				result.MultipleOf = i
+			parser.parseFloat(v, "multipleOf", func(f float64) {
+				result.MultipleOf = &f
 			})
 		case matchString(key, "maximum"):
-			parser.parseInt(v, "maximum", func(i int) {
-				result.Maximum = i
+			parser.parseFloat(v, "maximum", func(f float64) {
+				result.Maximum = &f
 			})
 		case matchString(key, "exclusiveMaximum"):
 			parser.parseBool(v, "exclusiveMaximum", func(b bool) {
 				result.ExclusiveMaximum = b
 			})
 		case matchString(key, "minimum"):
-			parser.parseInt(v, "minimum", func(i int) {
-				result.Minimum = i
+			parser.parseFloat(v, "minimum", func(f float64) {
+				result.Minimum = &f
 			})
 		case matchString(key, "exclusiveMinimum"):
 			parser.parseBool(v, "exclusiveMinimum", func(b bool) {
@@ -279,11 +297,11 @@ func parseSchema(val *fastjson.Value, parser *Parser) *Schema {
 			})
 		case matchString(key, "maxLength"):
 			parser.parseInt(v, "maxLength", func(i int) {
-				result.MaxLength = i
+				result.MaxLength = &i
 			})
 		case matchString(key, "minLength"):
 			parser.parseInt(v, "minLength", func(i int) {
-				result.MinLength = i
+				result.MinLength = &i
 			})
 		case matchString(key, "pattern"):
 			parser.parseString(v, "pattern", true, func(s string) {
@@ -291,11 +309,11 @@ func parseSchema(val *fastjson.Value, parser *Parser) *Schema {
 			})
 		case matchString(key, "maxItems"):
 			parser.parseInt(v, "maxItems", func(i int) {
-				result.MaxItems = i
+				result.MaxItems = &i
 			})
 		case matchString(key, "minItems"):
 			parser.parseInt(v, "minItems", func(i int) {
-				result.MinItems = i
+				result.MinItems = &i
 			})
 		case matchString(key, "uniqueItems"):
 			parser.parseBool(v, "uniqueItems", func(b bool) {
@@ -303,32 +321,32 @@ func parseSchema(val *fastjson.Value, parser *Parser) *Schema {
 			})
 		case matchString(key, "maxProperties"):
 			parser.parseInt(v, "maxProperties", func(i int) {
-				result.MaxProperties = i
+				result.MaxProperties = &i
 			})
 		case matchString(key, "minProperties"):
 			parser.parseInt(v, "minProperties", func(i int) {
-				result.MinProperties = i
+				result.MinProperties = &i
 			})
 		case matchString(key, "required"):
 			// should be an array of strings representing the property names that are required
 			if vals, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid 'required' value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid 'required' value: %w", e))
 			} else {
-				reqLoc := parser.currentLoc
+				reqLoc := parser.mark()
 				for i, reqVal := range vals {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", reqLoc, i)
-					parser.parseString(reqVal, fmt.Sprintf("required[%d]", i), false, func(s string) {
+					parser.atIndex(reqLoc, i)
+					parser.parseString(reqVal, "required item", false, func(s string) {
 						result.Required = append(result.Required, s)
 					})
 				}
 			}
 		case matchString(key, "enum"):
 			if vals, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid enum value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid enum value: %w", e))
 			} else {
 				result.Enum = make([]any, len(vals))
 				for i := range vals {
-					result.Enum[i] = vals[i]
+					result.Enum[i] = parser.retain(vals[i])
 				}
 			}
 		case matchString(key, "type"):
@@ -341,9 +359,9 @@ func parseSchema(val *fastjson.Value, parser *Parser) *Schema {
 				n := len(vals)
 				if n > 0 {
 					schemas := make([]Schema, 0, n)
-					itemsLoc := parser.currentLoc
+					itemsLoc := parser.mark()
 					for i, sVal := range vals {
-						parser.currentLoc = fmt.Sprintf("%s[%d]", itemsLoc, i)
+						parser.atIndex(itemsLoc, i)
 						if schema := parseSchema(sVal, parser); schema != nil {
 							schemas = append(schemas, *schema)
 						}
@@ -355,6 +373,18 @@ func parseSchema(val *fastjson.Value, parser *Parser) *Schema {
 					result.Items = NewSchemaOrSchemas(*schema)
 				}
 			}
+		case matchString(key, "allOf"):
+			if vals, e := v.Array(); e != nil {
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid allOf value: %w", e))
+			} else {
+				allOfLoc := parser.mark()
+				for i, sVal := range vals {
+					parser.atIndex(allOfLoc, i)
+					if schema := parseSchema(sVal, parser); schema != nil {
+						result.AllOf = append(result.AllOf, *schema)
+					}
+				}
+			}
 		case matchString(key, "properties"):
 			if props := parseProperties(v, parser); len(props) > 0 {
 				result.Properties = props
@@ -384,33 +414,116 @@ func parseSchema(val *fastjson.Value, parser *Parser) *Schema {
 		case matchString(key, "externalDocs"):
 			result.ExternalDocumentation = parseExternalDocumentation(v, parser)
 		case matchString(key, "example"):
-			result.Example = v
+			result.Example = detachValue(v)
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
 
 func parseProperties(val *fastjson.Value, parser *Parser) map[string]Schema {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid properties value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid properties value: %w", err))
 		return nil
 	}
 	result := make(map[string]Schema, obj.Len())
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		if schema := parseSchema(v, parser); schema != nil {
 			result[string(key)] = *schema
 		}
 	})
 	return result
 }
+
+func (s *Schema) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setString(a, val, "$ref", s.Ref.URI())
+	setString(a, val, "title", s.Title)
+	setString(a, val, "description", s.Description)
+	if s.Type != nil {
+		if s.Type.value != nil {
+			val.Set("type", a.NewString(*s.Type.value))
+		} else {
+			setStrings(a, val, "type", s.Type.items)
+		}
+	}
+	setString(a, val, "format", s.Format)
+	if s.Items != nil {
+		if s.Items.value != nil {
+			val.Set("items", s.Items.value.marshal(a))
+		} else if len(s.Items.items) > 0 {
+			val.Set("items", marshalSchemas(a, s.Items.items))
+		}
+	}
+	if s.AdditionalItems != nil {
+		val.Set("additionalItems", s.AdditionalItems.marshal(a))
+	}
+	if len(s.AllOf) > 0 {
+		val.Set("allOf", marshalSchemas(a, s.AllOf))
+	}
+	setString(a, val, "discriminator", s.Discriminator)
+	setStrings(a, val, "required", s.Required)
+	if len(s.Properties) > 0 {
+		props := a.NewObject()
+		for _, name := range sortedKeys(s.Properties) {
+			prop := s.Properties[name]
+			props.Set(name, prop.marshal(a))
+		}
+		val.Set("properties", props)
+	}
+	if s.AdditionalProperties != nil {
+		val.Set("additionalProperties", s.AdditionalProperties.marshal(a))
+	}
+	setAny(a, val, "default", s.Default)
+	setFloat(a, val, "multipleOf", s.MultipleOf)
+	setFloat(a, val, "maximum", s.Maximum)
+	setBool(a, val, "exclusiveMaximum", s.ExclusiveMaximum)
+	setFloat(a, val, "minimum", s.Minimum)
+	setBool(a, val, "exclusiveMinimum", s.ExclusiveMinimum)
+	setInt(a, val, "maxLength", s.MaxLength)
+	setInt(a, val, "minLength", s.MinLength)
+	setString(a, val, "pattern", s.Pattern)
+	setInt(a, val, "maxItems", s.MaxItems)
+	setInt(a, val, "minItems", s.MinItems)
+	setBool(a, val, "uniqueItems", s.UniqueItems)
+	setInt(a, val, "maxProperties", s.MaxProperties)
+	setInt(a, val, "minProperties", s.MinProperties)
+	setEnum(a, val, "enum", s.Enum)
+	setBool(a, val, "readOnly", s.IsReadOnly)
+	if s.XML != nil {
+		val.Set("xml", s.XML.marshal(a))
+	}
+	if s.ExternalDocumentation != nil {
+		val.Set("externalDocs", s.ExternalDocumentation.marshal(a))
+	}
+	setAny(a, val, "example", s.Example)
+	s.marshalExtensions(val)
+	return val
+}
+
+func (sb *SchemaOrBool) marshal(a *fastjson.Arena) *fastjson.Value {
+	if sb.object != nil {
+		return sb.object.marshal(a)
+	}
+	if sb.value {
+		return a.NewTrue()
+	}
+	return a.NewFalse()
+}
+
+func marshalSchemas(a *fastjson.Arena, schemas []Schema) *fastjson.Value {
+	arr := a.NewArray()
+	for i := range schemas {
+		arr.SetArrayItem(i, schemas[i].marshal(a))
+	}
+	return arr
+}
@@ -43,19 +43,18 @@ func NewScopes() *Scopes {
 }
 
 func parseSecurityDefinitions(val *fastjson.Value, parser *Parser) map[string]SecurityScheme {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid security definitions value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid security definitions value: %w", err))
 		return nil
 	}
 	result := make(map[string]SecurityScheme, obj.Len())
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		if ss := parseSecurityScheme(v, parser); ss != nil {
 			result[string(key)] = *ss
 		}
@@ -64,19 +63,18 @@ func parseSecurityDefinitions(val *fastjson.Value, parser *Parser) map[string]Se
 }
 
 func parseSecurityScheme(val *fastjson.Value, parser *Parser) *SecurityScheme {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid security scheme value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid security scheme value: %w", err))
 		return nil
 	}
 	result := NewSecurityScheme()
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "type"):
 			parser.parseString(v, "type", false, func(s string) {
@@ -111,33 +109,32 @@ func parseSecurityScheme(val *fastjson.Value, parser *Parser) *SecurityScheme {
 				result.Scopes = *scopes
 			}
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
 
 func parseScopes(val *fastjson.Value, parser *Parser) *Scopes {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid security value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid security value: %w", err))
 		return nil
 	}
 	result := NewScopes()
 	result.Values = make(map[string]string, obj.Len())
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		if matchExtension(key) {
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		} else {
-			parser.parseString(v, fmt.Sprintf("scopes[%s]", key), true, func(s string) {
+			parser.parseString(v, "scopes item", true, func(s string) {
 				result.Values[string(key)] = s
 			})
 		}
@@ -146,31 +143,72 @@ func parseScopes(val *fastjson.Value, parser *Parser) *Scopes {
 }
 
 func parseSecurityRequirements(val *fastjson.Value, parser *Parser) SecurityRequirements {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid security value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid security value: %w", err))
 		return nil
 	}
 	sec := make(SecurityRequirements, obj.Len())
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		if secVals, e := v.Array(); e != nil {
-			parser.appendError(fmt.Errorf("invalid value: %w", e))
+			parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid value: %w", e))
 		} else {
-			secLoc := parser.currentLoc
+			scopes := make([]string, 0, len(secVals))
+			secLoc := parser.mark()
 			for i, secVal := range secVals {
-				parser.currentLoc = fmt.Sprintf("%s[%d]", secLoc, i)
+				parser.atIndex(secLoc, i)
 				parser.parseString(secVal, "security scheme", true, func(s string) {
-					keyStr := string(key)
-					sec[keyStr] = append(sec[keyStr], s)
+					scopes = append(scopes, s)
 				})
 			}
+			sec[string(key)] = scopes
 		}
 	})
 	return sec
 }
+
+func (ss *SecurityScheme) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setString(a, val, "type", ss.Type)
+	setString(a, val, "description", ss.Description)
+	setString(a, val, "name", ss.Name)
+	setString(a, val, "in", ss.In)
+	setString(a, val, "flow", ss.Flow)
+	setString(a, val, "authorizationUrl", ss.AuthorizationURL)
+	setString(a, val, "tokenUrl", ss.TokenURL)
+	if len(ss.Scopes.Values) > 0 || len(ss.Scopes.Extensions) > 0 {
+		val.Set("scopes", ss.Scopes.marshal(a))
+	}
+	ss.marshalExtensions(val)
+	return val
+}
+
+func (s *Scopes) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	for _, name := range sortedKeys(s.Values) {
+		val.Set(name, a.NewString(s.Values[name]))
+	}
+	s.marshalExtensions(val)
+	return val
+}
+
+func (sr SecurityRequirements) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	for _, name := range sortedKeys(sr) {
+		val.Set(name, marshalStrings(a, sr[name]))
+	}
+	return val
+}
+
+func marshalSecurity(a *fastjson.Arena, reqs []SecurityRequirements) *fastjson.Value {
+	arr := a.NewArray()
+	for i := range reqs {
+		arr.SetArrayItem(i, reqs[i].marshal(a))
+	}
+	return arr
+}
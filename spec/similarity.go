@@ -0,0 +1,167 @@
+package spec
+
+import "github.com/valyala/fastjson"
+
+// SchemaSimilarity returns how structurally similar the schemas are from 0 for unrelated to 1 for equivalent, by
+// comparing their types, formats, properties, items, required properties, enums and constraints, where nested
+// schemas are compared recursively but references are only equal when they refer to the same URI
+func SchemaSimilarity(a, b *Schema) float64 {
+	if a == nil || b == nil {
+		if a == b {
+			return 1
+		}
+		return 0
+	}
+	if a.Ref != nil || b.Ref != nil {
+		if a.Ref.URI() == b.Ref.URI() {
+			return 1
+		}
+		return 0
+	}
+	var score, weight float64
+	add := func(w, s float64) {
+		score += w * s
+		weight += w
+	}
+	add(3, jaccard(a.Type.Values(), b.Type.Values()))
+	if a.Format != "" || b.Format != "" {
+		add(1, boolScore(a.Format == b.Format))
+	}
+	if len(a.Properties) > 0 || len(b.Properties) > 0 {
+		add(4, propertiesSimilarity(a.Properties, b.Properties))
+	}
+	if len(a.Required) > 0 || len(b.Required) > 0 {
+		add(1, jaccard(a.Required, b.Required))
+	}
+	if a.Items != nil || b.Items != nil {
+		add(2, itemsSimilarity(a.Items, b.Items))
+	}
+	if len(a.AllOf) > 0 || len(b.AllOf) > 0 {
+		add(2, allOfSimilarity(a.AllOf, b.AllOf))
+	}
+	if len(a.Enum) > 0 || len(b.Enum) > 0 {
+		add(1, enumSimilarity(a.Enum, b.Enum))
+	}
+	if equal, total := constraintsMatching(a, b); total > 0 {
+		add(1, float64(equal)/float64(total))
+	}
+	return score / weight
+}
+
+// propertiesSimilarity averages the similarity of every property of either schema, where those missing from one
+// of them count as 0
+func propertiesSimilarity(a, b map[string]Schema) float64 {
+	var (
+		total float64
+		union = len(a)
+	)
+	for name, pa := range a {
+		if pb, exists := b[name]; exists {
+			total += SchemaSimilarity(&pa, &pb)
+		}
+	}
+	for name := range b {
+		if _, exists := a[name]; !exists {
+			union++
+		}
+	}
+	return total / float64(union)
+}
+
+func itemsSimilarity(a, b *SchemaOrSchemas) float64 {
+	as, bs := a.Values(), b.Values()
+	if len(as) != len(bs) {
+		return 0
+	}
+	return allOfSimilarity(as, bs)
+}
+
+// allOfSimilarity averages the similarity of the schemas by position
+func allOfSimilarity(a, b []Schema) float64 {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	var total float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		total += SchemaSimilarity(&a[i], &b[i])
+	}
+	return total / float64(n)
+}
+
+func enumSimilarity(a, b []any) float64 {
+	var (
+		arena  fastjson.Arena
+		as, bs = make([]string, len(a)), make([]string, len(b))
+	)
+	for i := range a {
+		as[i] = marshalAny(&arena, a[i]).String()
+	}
+	for i := range b {
+		bs[i] = marshalAny(&arena, b[i]).String()
+	}
+	return jaccard(as, bs)
+}
+
+// constraintsMatching returns how many of the validation constraints set by either schema are equal and the total
+func constraintsMatching(a, b *Schema) (equal int, total int) {
+	compare := func(set bool, same bool) {
+		if set {
+			total++
+			if same {
+				equal++
+			}
+		}
+	}
+	compare(a.MultipleOf != nil || b.MultipleOf != nil, equalOptional(a.MultipleOf, b.MultipleOf))
+	compare(a.Maximum != nil || b.Maximum != nil || a.ExclusiveMaximum || b.ExclusiveMaximum,
+		equalOptional(a.Maximum, b.Maximum) && a.ExclusiveMaximum == b.ExclusiveMaximum)
+	compare(a.Minimum != nil || b.Minimum != nil || a.ExclusiveMinimum || b.ExclusiveMinimum,
+		equalOptional(a.Minimum, b.Minimum) && a.ExclusiveMinimum == b.ExclusiveMinimum)
+	compare(a.MaxLength != nil || b.MaxLength != nil, equalOptional(a.MaxLength, b.MaxLength))
+	compare(a.MinLength != nil || b.MinLength != nil, equalOptional(a.MinLength, b.MinLength))
+	compare(a.Pattern != "" || b.Pattern != "", a.Pattern == b.Pattern)
+	compare(a.MaxItems != nil || b.MaxItems != nil, equalOptional(a.MaxItems, b.MaxItems))
+	compare(a.MinItems != nil || b.MinItems != nil, equalOptional(a.MinItems, b.MinItems))
+	compare(a.UniqueItems || b.UniqueItems, a.UniqueItems == b.UniqueItems)
+	compare(a.MaxProperties != nil || b.MaxProperties != nil, equalOptional(a.MaxProperties, b.MaxProperties))
+	compare(a.MinProperties != nil || b.MinProperties != nil, equalOptional(a.MinProperties, b.MinProperties))
+	compare(a.IsReadOnly || b.IsReadOnly, a.IsReadOnly == b.IsReadOnly)
+	return equal, total
+}
+
+// equalOptional returns true if both values are unset or both are set to the same value
+func equalOptional[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// jaccard returns the size of the intersection of the sets over that of their union, which is 1 when both are empty
+func jaccard(a, b []string) float64 {
+	union := make(map[string]int, len(a)+len(b))
+	for _, s := range a {
+		union[s] |= 1
+	}
+	for _, s := range b {
+		union[s] |= 2
+	}
+	if len(union) == 0 {
+		return 1
+	}
+	var both int
+	for _, in := range union {
+		if in == 3 {
+			both++
+		}
+	}
+	return float64(both) / float64(len(union))
+}
+
+func boolScore(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
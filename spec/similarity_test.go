@@ -0,0 +1,62 @@
+package spec
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSchemaSimilarity(t *testing.T) {
+	swagger, err := NewParser([]byte(`{
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {"name": {"type": "string"}, "age": {"type": "integer", "format": "int32"}}
+			},
+			"Animal": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {"name": {"type": "string"}, "age": {"type": "integer", "format": "int32"}, "kind": {"type": "string"}}
+			},
+			"Names": {"type": "array", "items": {"type": "string", "maxLength": 10}},
+			"Labels": {"type": "array", "items": {"type": "string", "maxLength": 20}},
+			"Count": {"type": "integer"},
+			"Color": {"type": "string", "enum": ["red", "green"]},
+			"Colour": {"type": "string", "enum": ["red", "blue"]},
+			"PetRef": {"$ref": "#/definitions/Pet"},
+			"OtherPetRef": {"$ref": "#/definitions/Pet"}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	def := func(name string) *Schema {
+		s := swagger.Definitions[name]
+		return &s
+	}
+	tests := map[string]struct {
+		a, b     *Schema
+		expected float64
+	}{
+		"nil schemas are equivalent":           {expected: 1},
+		"nil and a schema are unrelated":       {a: def("Pet"), expected: 0},
+		"a schema is equivalent to itself":     {a: def("Pet"), b: def("Pet"), expected: 1},
+		"an extra property lowers similarity":  {a: def("Pet"), b: def("Animal"), expected: (3 + 4*2.0/3 + 1) / 8},
+		"differing types are unrelated":        {a: def("Count"), b: def("Color"), expected: 0},
+		"item constraints are compared":        {a: def("Names"), b: def("Labels"), expected: (3 + 2*(3.0/4)) / 5},
+		"enum values are compared as sets":     {a: def("Color"), b: def("Colour"), expected: (3 + 1.0/3) / 4},
+		"same references are equivalent":       {a: def("PetRef"), b: def("OtherPetRef"), expected: 1},
+		"references differ from their targets": {a: def("PetRef"), b: def("Pet"), expected: 0},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			got := SchemaSimilarity(tt.a, tt.b)
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("SchemaSimilarity() = %v, want %v", got, tt.expected)
+			}
+			if reverse := SchemaSimilarity(tt.b, tt.a); math.Abs(reverse-got) > 1e-9 {
+				t.Errorf("SchemaSimilarity() is not symmetric: %v != %v", got, reverse)
+			}
+		})
+	}
+}
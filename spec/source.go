@@ -0,0 +1,136 @@
+package spec
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// SourceRange is the span of bytes of a value within the raw document it was parsed from, where End is exclusive
+type SourceRange struct {
+	Start int
+	End   int
+}
+
+// SourceRange returns the span of the value at the location within the raw document, using the same form of location
+// as ParseError, and true only if it was recorded by parsing using WithSourceMap
+func (s *Swagger) SourceRange(loc string) (SourceRange, bool) {
+	if s == nil {
+		return SourceRange{}, false
+	}
+	r, exists := s.sourceMap[loc]
+	return r, exists
+}
+
+// sourceMap maps the location of every value in a parsed document to its span
+type sourceMap map[string]SourceRange
+
+// mapSources returns the sourceMap of the raw document, which must be valid JSON
+func mapSources(raw []byte) sourceMap {
+	return mapSourcesAt(raw, "", 0)
+}
+
+// mapSourcesAt returns the sourceMap of the raw value found at the location and offset within a document
+func mapSourcesAt(raw []byte, loc string, offset int) sourceMap {
+	m := sourceMapper{raw: raw, offset: offset, ranges: make(sourceMap)}
+	m.value(loc)
+	return m.ranges
+}
+
+// sourceMapper scans a valid JSON value recording the span of each value within it
+type sourceMapper struct {
+	raw    []byte
+	pos    int
+	offset int
+	ranges sourceMap
+}
+
+func (m *sourceMapper) skipSpace() {
+	for m.pos < len(m.raw) {
+		switch m.raw[m.pos] {
+		case ' ', '\t', '\n', '\r':
+			m.pos++
+		default:
+			return
+		}
+	}
+}
+
+// value scans the value at the current position which is found at the location
+func (m *sourceMapper) value(loc string) {
+	m.skipSpace()
+	if m.pos >= len(m.raw) {
+		return
+	}
+	start := m.pos
+	switch m.raw[m.pos] {
+	case '{':
+		m.pos++
+		for m.next('}') {
+			key := m.key()
+			m.skipSpace()
+			m.pos++ // the colon
+			m.value(loc + "." + key)
+		}
+	case '[':
+		m.pos++
+		for i := 0; m.next(']'); i++ {
+			m.value(loc + "[" + strconv.Itoa(i) + "]")
+		}
+	case '"':
+		m.skipString()
+	default:
+		for m.pos < len(m.raw) {
+			switch m.raw[m.pos] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+			default:
+				m.pos++
+				continue
+			}
+			break
+		}
+	}
+	m.ranges[rootLoc(loc)] = SourceRange{Start: start + m.offset, End: m.pos + m.offset}
+}
+
+// next skips any comma before the next member of an object or array, returning false after consuming its closing
+func (m *sourceMapper) next(closing byte) bool {
+	m.skipSpace()
+	if m.pos < len(m.raw) && m.raw[m.pos] == ',' {
+		m.pos++
+		m.skipSpace()
+	}
+	if m.pos >= len(m.raw) || m.raw[m.pos] == closing {
+		m.pos++
+		return false
+	}
+	return true
+}
+
+// key returns the unescaped object key at the current position
+func (m *sourceMapper) key() string {
+	start := m.pos
+	escaped := m.skipString()
+	quoted := m.raw[start:m.pos]
+	if !escaped {
+		return string(quoted[1 : len(quoted)-1])
+	}
+	var key string
+	_ = json.Unmarshal(quoted, &key)
+	return key
+}
+
+// skipString moves past the string at the current position, returning true if it contains any escapes
+func (m *sourceMapper) skipString() bool {
+	var escaped bool
+	for m.pos++; m.pos < len(m.raw); m.pos++ {
+		switch m.raw[m.pos] {
+		case '\\':
+			escaped = true
+			m.pos++
+		case '"':
+			m.pos++
+			return escaped
+		}
+	}
+	return escaped
+}
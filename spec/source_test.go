@@ -0,0 +1,58 @@
+package spec
+
+import (
+	"testing"
+)
+
+func TestSwagger_SourceRange(t *testing.T) {
+	raw := `{
+  "swagger": "2.0",
+  "info": {"title": "pets \"api\"", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "tags": ["pets", "animals"],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  },
+  "x-escaped!": -1.5e3,
+  "x-\u0061": true
+}`
+	swagger, err := NewParser([]byte(raw), WithSourceMap()).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	tests := map[string]string{
+		".":                          raw,
+		".swagger":                   `"2.0"`,
+		".info":                      `{"title": "pets \"api\"", "version": "1.0"}`,
+		".info.title":                `"pets \"api\""`,
+		".paths./pets.get.tags":      `["pets", "animals"]`,
+		".paths./pets.get.tags[1]":   `"animals"`,
+		".paths./pets.get.responses": `{"200": {"description": "ok"}}`,
+		".x-escaped!":                `-1.5e3`,
+		".x-a":                       `true`,
+	}
+	for loc, expected := range tests {
+		r, ok := swagger.SourceRange(loc)
+		if !ok {
+			t.Errorf("SourceRange(%s) not found", loc)
+			continue
+		}
+		if got := raw[r.Start:r.End]; got != expected {
+			t.Errorf("SourceRange(%s) = %q, want %q", loc, got, expected)
+		}
+	}
+	if _, ok := swagger.SourceRange(".missing"); ok {
+		t.Error("SourceRange() of a missing location should not be found")
+	}
+
+	swagger, err = NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	if _, ok := swagger.SourceRange("."); ok {
+		t.Error("SourceRange() should not be recorded without WithSourceMap")
+	}
+}
@@ -2,6 +2,7 @@ package spec
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/valyala/fastjson"
 )
@@ -26,6 +27,12 @@ type Swagger struct {
 	Tags                  []Tag
 	ExternalDocumentation *ExternalDocumentation
 	operationMap          OperationMap
+	keyOrder              keyOrder
+	sourceMap             sourceMap
+	parseErrors           map[string][]error
+	duplicateOperations   Operations
+	routesMu              sync.Mutex
+	routes                *routeNode
 }
 
 // OperationCount returns the count of total operations contained within this spec
@@ -56,6 +63,63 @@ func (s *Swagger) Operations() Operations {
 	return s.operationMap.Sorted()
 }
 
+// DuplicateOperations returns the operations having the same key as an earlier operation in the order they were found,
+// which are only kept when parsed using WithKeepDuplicateOperations
+func (s *Swagger) DuplicateOperations() Operations {
+	if s == nil {
+		return nil
+	}
+	return append(Operations(nil), s.duplicateOperations...)
+}
+
+// AddOperation will add the specified Operation to both the Paths and the operations of this spec, returning true
+// only if it was added and not preexisting.
+func (s *Swagger) AddOperation(op *Operation) bool {
+	if s == nil || op == nil {
+		return false
+	}
+	key := op.Key.Canonicalize()
+	if _, preexisting := s.operationMap[key]; preexisting {
+		return false
+	}
+	if s.Paths.Items == nil {
+		s.Paths.Items = make(map[string]*PathItem)
+	}
+	pi := s.Paths.Items[key.Path]
+	if pi == nil {
+		pi = NewPathItem()
+	}
+	if !pi.SetOperation(key.Method, op) {
+		return false
+	}
+	s.Paths.Items[key.Path] = pi
+	return s.addOperation(op)
+}
+
+// RemoveOperation will remove the Operation with the key from both the Paths and the operations of this spec, along
+// with its PathItem when no other operations remain, returning true only if it was present.
+func (s *Swagger) RemoveOperation(key OperationKey) bool {
+	if s == nil {
+		return false
+	}
+	key = key.Canonicalize()
+	if _, exists := s.operationMap[key]; !exists {
+		return false
+	}
+	delete(s.operationMap, key)
+	s.InvalidateRoutes()
+	if pi := s.Paths.Items[key.Path]; pi != nil {
+		pi.SetOperation(key.Method, nil)
+		for _, method := range pathItemMethods {
+			if pi.Operation(method) != nil {
+				return true
+			}
+		}
+		delete(s.Paths.Items, key.Path)
+	}
+	return true
+}
+
 // addOperation will add the specified Operation to metadata and return true only if it as added and not preexisting.
 func (s *Swagger) addOperation(op *Operation) bool {
 	if s == nil || op == nil {
@@ -66,9 +130,88 @@ func (s *Swagger) addOperation(op *Operation) bool {
 		return false
 	}
 	s.operationMap[op.Key] = op
+	s.InvalidateRoutes()
 	return true
 }
 
+// MarshalJSON returns the JSON encoding of this spec with the fields of each object in the order defined by the swagger
+// specification followed by extensions, and the keys of maps sorted. If parsed using WithKeyOrder, all keys are
+// instead emitted in their authored order.
+func (s *Swagger) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	a := arenaPool.Get()
+	defer func() {
+		a.Reset()
+		arenaPool.Put(a)
+	}()
+	val := s.marshal(a)
+	if s.keyOrder != nil {
+		val = s.keyOrder.apply(a, val, "")
+	}
+	return val.MarshalTo(nil), nil
+}
+
+func (s *Swagger) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	val.Set("swagger", a.NewString("2.0"))
+	val.Set("info", s.Info.marshal(a))
+	setString(a, val, "host", s.Host)
+	setString(a, val, "basePath", s.BasePath)
+	setStrings(a, val, "schemes", s.Schemes)
+	setStrings(a, val, "consumes", s.Consumes)
+	setStrings(a, val, "produces", s.Produces)
+	val.Set("paths", s.Paths.marshal(a))
+	if len(s.Definitions) > 0 {
+		defs := a.NewObject()
+		for _, name := range sortedKeys(s.Definitions) {
+			def := s.Definitions[name]
+			defs.Set(name, def.marshal(a))
+		}
+		val.Set("definitions", defs)
+	}
+	if len(s.Parameters) > 0 {
+		params := a.NewObject()
+		for _, name := range sortedKeys(s.Parameters) {
+			param := s.Parameters[name]
+			params.Set(name, param.marshal(a))
+		}
+		val.Set("parameters", params)
+	}
+	if len(s.Responses) > 0 {
+		responses := a.NewObject()
+		for _, name := range sortedKeys(s.Responses) {
+			resp := s.Responses[name]
+			responses.Set(name, resp.marshal(a))
+		}
+		val.Set("responses", responses)
+	}
+	if len(s.SecurityDefinitions) > 0 {
+		secDefs := a.NewObject()
+		for _, name := range sortedKeys(s.SecurityDefinitions) {
+			ss := s.SecurityDefinitions[name]
+			secDefs.Set(name, ss.marshal(a))
+		}
+		val.Set("securityDefinitions", secDefs)
+	}
+	if s.Security != nil {
+		val.Set("security", marshalSecurity(a, s.Security))
+	}
+	if len(s.Tags) > 0 {
+		tags := a.NewArray()
+		for i := range s.Tags {
+			tags.SetArrayItem(i, s.Tags[i].marshal(a))
+		}
+		val.Set("tags", tags)
+	}
+	if s.ExternalDocumentation != nil {
+		val.Set("externalDocs", s.ExternalDocumentation.marshal(a))
+	}
+	s.marshalExtensions(val)
+	return val
+}
+
 // NewSwagger returns a new Swagger
 func NewSwagger() *Swagger {
 	return &Swagger{
@@ -79,20 +222,19 @@ func NewSwagger() *Swagger {
 
 // parseSwagger will attempt to parse the root swagger object from the root JSON value
 func parseSwagger(swagVal *fastjson.Value, parser *Parser) *Swagger {
+	parser.checkContext()
 	swagObj, err := swagVal.Object()
 	if err != nil {
 		err = fmt.Errorf("invalid swagger value: %w", err)
-		parser.appendError(err)
+		parser.appendError(ErrorCodeInvalidType, err)
 		return nil
 	}
 	result := NewSwagger()
 	parser.swagger = result
-	defer func() {
-		// reset after
-		parser.currentLoc = "."
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	swagObj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf(".%s", key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "swagger"):
 			parser.parseAndValidateString(v, "swagger", func(s string) error {
@@ -112,10 +254,11 @@ func parseSwagger(swagVal *fastjson.Value, parser *Parser) *Swagger {
 			})
 		case matchString(key, "schemes"):
 			if schemes, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid schemes value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid schemes value: %w", e))
 			} else {
+				schemesLoc := parser.mark()
 				for i, sVal := range schemes {
-					parser.currentLoc = fmt.Sprintf(".schemes[%d]", i)
+					parser.atIndex(schemesLoc, i)
 					parser.parseString(sVal, "schemes item", true, func(s string) {
 						result.Schemes = append(result.Schemes, s)
 					})
@@ -123,11 +266,11 @@ func parseSwagger(swagVal *fastjson.Value, parser *Parser) *Swagger {
 			}
 		case matchString(key, "consumes"):
 			if consumes, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid consumes value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid consumes value: %w", e))
 			} else {
-				consumesLoc := parser.currentLoc
+				consumesLoc := parser.mark()
 				for i, cVal := range consumes {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", consumesLoc, i)
+					parser.atIndex(consumesLoc, i)
 					parser.parseString(cVal, "consumes item", true, func(s string) {
 						result.Consumes = append(result.Consumes, s)
 					})
@@ -135,11 +278,11 @@ func parseSwagger(swagVal *fastjson.Value, parser *Parser) *Swagger {
 			}
 		case matchString(key, "produces"):
 			if produces, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid produces value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid produces value: %w", e))
 			} else {
-				producesLoc := parser.currentLoc
+				producesLoc := parser.mark()
 				for i, pVal := range produces {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", producesLoc, i)
+					parser.atIndex(producesLoc, i)
 					parser.parseString(pVal, "produces item", true, func(s string) {
 						result.Produces = append(result.Produces, s)
 					})
@@ -172,24 +315,26 @@ func parseSwagger(swagVal *fastjson.Value, parser *Parser) *Swagger {
 		case matchString(key, "security"):
 			// this is an array of security requirements, so parse the array then parse each
 			if secReqs, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid 'security' value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid 'security' value: %w", e))
 			} else {
-				secLoc := parser.currentLoc
+				// an empty array is kept as it explicitly declares no security
+				result.Security = make([]SecurityRequirements, 0, len(secReqs))
+				secLoc := parser.mark()
 				for i, secVal := range secReqs {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", secLoc, i)
-					if sec := parseSecurityRequirements(secVal, parser); len(sec) > 0 {
+					parser.atIndex(secLoc, i)
+					if sec := parseSecurityRequirements(secVal, parser); sec != nil {
 						result.Security = append(result.Security, sec)
 					}
 				}
 			}
 		case matchString(key, "tags"):
 			if tags, e := v.Array(); e != nil {
-				parser.appendError(fmt.Errorf("invalid tags value: %w", e))
+				parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid tags value: %w", e))
 			} else {
 				result.Tags = make([]Tag, 0, len(tags))
-				tagsLoc := parser.currentLoc
+				tagsLoc := parser.mark()
 				for i, tagVal := range tags {
-					parser.currentLoc = fmt.Sprintf("%s[%d]", tagsLoc, i)
+					parser.atIndex(tagsLoc, i)
 					if tag := parseTag(tagVal, parser); tag != nil {
 						result.Tags = append(result.Tags, *tag)
 					}
@@ -200,9 +345,9 @@ func parseSwagger(swagVal *fastjson.Value, parser *Parser) *Swagger {
 				result.ExternalDocumentation = ed
 			}
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	parser.swagger = result
@@ -236,6 +381,7 @@ func (ed *ExternalDocumentation) marshal(a *fastjson.Arena) *fastjson.Value {
 	return val
 }
 
+// String returns the JSON encoding of this object
 func (ed *ExternalDocumentation) String() string {
 	if ed == nil {
 		return ""
@@ -265,19 +411,18 @@ func (ed *ExternalDocumentation) url() string {
 
 // parseExternalDocumentation will attempt to parse an ExternalDocumentation from the source swagger .externalDocumentation JSON values
 func parseExternalDocumentation(edVal *fastjson.Value, parser *Parser) *ExternalDocumentation {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	edObj, err := edVal.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid externalDocs value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid externalDocs value: %w", err))
 		return nil
 	}
 	result := NewExternalDocumentation()
 	edObj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "url"):
 			parser.parseString(v, "url", true, func(s string) {
@@ -288,9 +433,9 @@ func parseExternalDocumentation(edVal *fastjson.Value, parser *Parser) *External
 				result.Description = s
 			})
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
@@ -33,6 +33,7 @@ func (t *Tag) marshal(a *fastjson.Arena) *fastjson.Value {
 	return v
 }
 
+// String returns the JSON encoding of this object
 func (t *Tag) String() string {
 	if t == nil {
 		return ""
@@ -48,18 +49,17 @@ func (t *Tag) String() string {
 
 // parseTag will attempt to parse a Tag from the source swagger .tags JSON array values
 func parseTag(tagVal *fastjson.Value, parser *Parser) *Tag {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	tagObj, err := tagVal.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid result value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid result value: %w", err))
 	}
 	result := NewTag()
 	tagObj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "name"):
 			parser.parseString(v, "name", false, func(s string) {
@@ -72,10 +72,61 @@ func parseTag(tagVal *fastjson.Value, parser *Parser) *Tag {
 		case matchString(key, "externalDocs"):
 			result.ExternalDocumentation = parseExternalDocumentation(v, parser)
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
+
+// OperationsByTag returns the sorted operations of this spec by each tag they declare, where an operation with many
+// tags is included under each of them and those without any tags are not included
+func (s *Swagger) OperationsByTag() map[string]Operations {
+	if s == nil {
+		return nil
+	}
+	results := make(map[string]Operations)
+	for _, op := range s.Operations() {
+		for _, tag := range op.Tags {
+			if !endsWithOperation(results[tag], op) {
+				results[tag] = append(results[tag], op)
+			}
+		}
+	}
+	return results
+}
+
+// TagsInUse returns the sorted names of the tags declared by any operation of this spec
+func (s *Swagger) TagsInUse() []string {
+	return sortedKeys(s.OperationsByTag())
+}
+
+// UndeclaredTags returns the sorted names of the tags declared by any operation of this spec which are not declared
+// by the tags of the spec itself
+func (s *Swagger) UndeclaredTags() []string {
+	if s == nil {
+		return nil
+	}
+	var results []string
+	for _, name := range s.TagsInUse() {
+		if !s.declaresTag(name) {
+			results = append(results, name)
+		}
+	}
+	return results
+}
+
+func (s *Swagger) declaresTag(name string) bool {
+	for _, tag := range s.Tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// endsWithOperation returns true if the last of the operations is the operation, as an operation may repeat a tag
+func endsWithOperation(ops Operations, op *Operation) bool {
+	return len(ops) > 0 && ops[len(ops)-1] == op
+}
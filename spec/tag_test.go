@@ -1,6 +1,7 @@
 package spec
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/valyala/fastjson"
@@ -44,7 +45,7 @@ func Test_parseTag(t *testing.T) {
 	}
 	for should, tt := range tests {
 		parser := NewParser(nil)
-		parser.currentLoc = tt.location
+		parser.baseLoc = tt.location
 		tagVal := tt.expectedTag.marshal(&arena)
 		t.Run(should, func(t *testing.T) {
 			got := parseTag(tagVal, parser)
@@ -85,3 +86,38 @@ func tagsEqual(t1, t2 *Tag) bool {
 	}
 	return true
 }
+
+func TestSwagger_OperationsByTag(t *testing.T) {
+	swagger, err := NewParser([]byte(`{
+		"tags": [{"name": "pets"}, {"name": "unused"}],
+		"paths": {
+			"/pets": {"get": {"tags": ["pets", "pets"], "responses": {"200": {"description": "ok"}}}},
+			"/pets/{id}": {"get": {"tags": ["pets", "beta"], "responses": {"200": {"description": "ok"}}}},
+			"/stores": {"get": {"tags": ["stores"], "responses": {"200": {"description": "ok"}}}},
+			"/health": {"get": {"responses": {"200": {"description": "ok"}}}}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	got := make(map[string][]string)
+	for tag, ops := range swagger.OperationsByTag() {
+		for _, op := range ops {
+			got[tag] = append(got[tag], op.Key.Path)
+		}
+	}
+	expected := map[string][]string{
+		"beta":   {"/pets/{id}"},
+		"pets":   {"/pets", "/pets/{id}"},
+		"stores": {"/stores"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("OperationsByTag() = %v, want %v", got, expected)
+	}
+	if got, expected := swagger.TagsInUse(), []string{"beta", "pets", "stores"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("TagsInUse() = %v, want %v", got, expected)
+	}
+	if got, expected := swagger.UndeclaredTags(), []string{"beta", "stores"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("UndeclaredTags() = %v, want %v", got, expected)
+	}
+}
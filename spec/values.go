@@ -0,0 +1,245 @@
+package spec
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/erraggy/goats/formats"
+	"github.com/valyala/fastjson"
+)
+
+// ValidateValue returns the errors found validating the JSON value against the schema, resolving references to the
+// definitions of this spec. Each error is a *ValueError prefixed by the location of the invalid value within it.
+func (s *Swagger) ValidateValue(schema *Schema, value *fastjson.Value) []error {
+	if schema == nil || value == nil {
+		return nil
+	}
+	v := valueValidator{swagger: s}
+	v.validate(schema, value, "", make(map[string]bool))
+	return v.errs
+}
+
+// ValueError is a single error found validating a JSON value against a schema
+type ValueError struct {
+	// Location is that of the invalid value within the validated value, such as .pets[0].name, or empty for the
+	// validated value itself
+	Location string
+	Message  string
+}
+
+func (e *ValueError) Error() string {
+	if e.Location == "" {
+		return "value: " + e.Message
+	}
+	return e.Location + ": " + e.Message
+}
+
+type valueValidator struct {
+	swagger *Swagger
+	errs    []error
+}
+
+func (v *valueValidator) fail(loc string, format string, args ...any) {
+	v.errs = append(v.errs, &ValueError{Location: loc, Message: fmt.Sprintf(format, args...)})
+}
+
+// validate checks the value against the schema, where resolving tracks the definitions already resolved for this same
+// value to stop cyclic references
+func (v *valueValidator) validate(s *Schema, val *fastjson.Value, loc string, resolving map[string]bool) {
+	if name, ok := s.referencedDefinition(); ok {
+		if resolving[name] {
+			return
+		}
+		def, exists := v.swagger.definition(name)
+		if !exists {
+			v.fail(loc, "unresolved reference '%s'", s.Ref.URI())
+			return
+		}
+		resolving[name] = true
+		v.validate(def, val, loc, resolving)
+		delete(resolving, name)
+		return
+	}
+	for i := range s.AllOf {
+		v.validate(&s.AllOf[i], val, loc, resolving)
+	}
+	if types := s.Type.Values(); len(types) > 0 && !matchesAnyType(types, val) {
+		v.fail(loc, "expected type %v but got %s", types, val.Type())
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, val) {
+		v.fail(loc, "value %s is not one of the enum values", val)
+	}
+	if err := formats.Validate("", s.Format, val); err != nil {
+		v.fail(loc, "%s", err)
+	}
+	switch val.Type() {
+	case fastjson.TypeNumber:
+		v.validateNumber(s.MultipleOf, s.Maximum, s.ExclusiveMaximum, s.Minimum, s.ExclusiveMinimum, val.GetFloat64(), loc)
+	case fastjson.TypeString:
+		v.validateString(s.MaxLength, s.MinLength, s.Pattern, string(val.GetStringBytes()), loc)
+	case fastjson.TypeArray:
+		vals := val.GetArray()
+		v.validateArray(s.MaxItems, s.MinItems, s.UniqueItems, vals, loc)
+		if s.Items != nil {
+			if items, single := s.Items.AsSchema(); single {
+				for i, item := range vals {
+					v.validate(items, item, fmt.Sprintf("%s[%d]", loc, i), make(map[string]bool))
+				}
+			} else {
+				tuple := s.Items.Values()
+				for i, item := range vals {
+					itemLoc := fmt.Sprintf("%s[%d]", loc, i)
+					if i < len(tuple) {
+						v.validate(&tuple[i], item, itemLoc, make(map[string]bool))
+					} else if additional, ok := s.AdditionalItems.AsSchema(); ok {
+						v.validate(additional, item, itemLoc, make(map[string]bool))
+					} else if allowed, ok := s.AdditionalItems.AsBool(); ok && !allowed {
+						v.fail(itemLoc, "additional items are not allowed")
+					}
+				}
+			}
+		}
+	case fastjson.TypeObject:
+		v.validateObject(s, val.GetObject(), loc)
+	}
+}
+
+func (v *valueValidator) validateObject(s *Schema, obj *fastjson.Object, loc string) {
+	if s.MaxProperties != nil && obj.Len() > *s.MaxProperties {
+		v.fail(loc, "has %d properties which is more than the maxProperties of %d", obj.Len(), *s.MaxProperties)
+	}
+	if s.MinProperties != nil && obj.Len() < *s.MinProperties {
+		v.fail(loc, "has %d properties which is less than the minProperties of %d", obj.Len(), *s.MinProperties)
+	}
+	for _, name := range s.Required {
+		if obj.Get(name) == nil {
+			v.fail(loc, "missing required property '%s'", name)
+		}
+	}
+	obj.Visit(func(key []byte, child *fastjson.Value) {
+		name := string(key)
+		propLoc := loc + "." + name
+		if prop, exists := s.Properties[name]; exists {
+			v.validate(&prop, child, propLoc, make(map[string]bool))
+		} else if additional, ok := s.AdditionalProperties.AsSchema(); ok {
+			v.validate(additional, child, propLoc, make(map[string]bool))
+		} else if allowed, ok := s.AdditionalProperties.AsBool(); ok && !allowed {
+			v.fail(propLoc, "additional properties are not allowed")
+		}
+	})
+}
+
+func (v *valueValidator) validateNumber(multipleOf, maximum *float64, exclusiveMaximum bool, minimum *float64, exclusiveMinimum bool, n float64, loc string) {
+	if multipleOf != nil && *multipleOf > 0 && !isMultipleOf(n, *multipleOf) {
+		v.fail(loc, "%v is not a multiple of %v", n, *multipleOf)
+	}
+	if maximum != nil {
+		if n > *maximum || exclusiveMaximum && n == *maximum {
+			v.fail(loc, "%v exceeds the maximum of %v", n, *maximum)
+		}
+	}
+	if minimum != nil {
+		if n < *minimum || exclusiveMinimum && n == *minimum {
+			v.fail(loc, "%v is below the minimum of %v", n, *minimum)
+		}
+	}
+}
+
+// isMultipleOf returns true if n is a multiple of the divisor, allowing for the rounding of decimal fractions such as
+// 0.3 being a multiple of 0.1
+func isMultipleOf(n, divisor float64) bool {
+	q := n / divisor
+	return math.Abs(q-math.Round(q)) <= 1e-9*math.Max(1, math.Abs(q))
+}
+
+func (v *valueValidator) validateString(maxLength, minLength *int, pattern string, str string, loc string) {
+	length := utf8.RuneCountInString(str)
+	if maxLength != nil && length > *maxLength {
+		v.fail(loc, "length of %d exceeds the maxLength of %d", length, *maxLength)
+	}
+	if minLength != nil && length < *minLength {
+		v.fail(loc, "length of %d is less than the minLength of %d", length, *minLength)
+	}
+	if pattern != "" {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(str) {
+			v.fail(loc, "'%s' does not match the pattern '%s'", str, pattern)
+		}
+	}
+}
+
+func (v *valueValidator) validateArray(maxItems, minItems *int, uniqueItems bool, vals []*fastjson.Value, loc string) {
+	if maxItems != nil && len(vals) > *maxItems {
+		v.fail(loc, "has %d items which is more than the maxItems of %d", len(vals), *maxItems)
+	}
+	if minItems != nil && len(vals) < *minItems {
+		v.fail(loc, "has %d items which is less than the minItems of %d", len(vals), *minItems)
+	}
+	if uniqueItems {
+		for i := range vals {
+			for j := i + 1; j < len(vals); j++ {
+				if equalValues(vals[i], vals[j]) {
+					v.fail(loc, "items %d and %d are not unique", i, j)
+				}
+			}
+		}
+	}
+}
+
+// definition returns the definition with the name and if it exists
+func (s *Swagger) definition(name string) (*Schema, bool) {
+	if s == nil {
+		return nil, false
+	}
+	def, exists := s.Definitions[name]
+	return &def, exists
+}
+
+// matchesAnyType returns true if the JSON value is of any of the swagger types
+func matchesAnyType(types []string, val *fastjson.Value) bool {
+	for _, t := range types {
+		switch val.Type() {
+		case fastjson.TypeObject:
+			if t == "object" {
+				return true
+			}
+		case fastjson.TypeArray:
+			if t == "array" {
+				return true
+			}
+		case fastjson.TypeString:
+			if t == "string" || t == "file" {
+				return true
+			}
+		case fastjson.TypeNumber:
+			if t == "number" {
+				return true
+			}
+			if f := val.GetFloat64(); t == "integer" && f == math.Trunc(f) {
+				return true
+			}
+		case fastjson.TypeTrue, fastjson.TypeFalse:
+			if t == "boolean" {
+				return true
+			}
+		case fastjson.TypeNull:
+			if t == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inEnum returns true if the value equals any of the enum values
+func inEnum(enum []any, val *fastjson.Value) bool {
+	var a fastjson.Arena
+	for _, e := range enum {
+		if equalValues(marshalAny(&a, e), val) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUndefinedVariable is wrapped by the error returned when expanding a placeholder of a variable that is not defined
+var ErrUndefinedVariable = errors.New("undefined variable")
+
+// VariableLookup returns the value of the named variable and if it is defined, such as os.LookupEnv
+type VariableLookup func(name string) (string, bool)
+
+// VariablesFromMap returns a VariableLookup of the variables within the map
+func VariablesFromMap(vars map[string]string) VariableLookup {
+	return func(name string) (string, bool) {
+		value, defined := vars[name]
+		return value, defined
+	}
+}
+
+// ExpandVariables replaces every ${name} placeholder within the strings of the raw JSON document with the value of
+// the variable, which is escaped as needed. A placeholder is escaped as $${name} to keep it literally. Names may only
+// contain letters, digits, '_', '.' and '-', and anything else is kept as is. The returned error wraps
+// ErrUndefinedVariable and names each undefined variable.
+func ExpandVariables(raw []byte, lookup VariableLookup) ([]byte, error) {
+	var (
+		result    = make([]byte, 0, len(raw))
+		undefined = make(map[string]bool)
+	)
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '"' {
+			result = append(result, raw[i])
+			continue
+		}
+		end := i + 1
+		for end < len(raw) && raw[end] != '"' {
+			if raw[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end > len(raw) {
+			end = len(raw)
+		}
+		result = append(result, '"')
+		result = expandPlaceholders(result, string(raw[i+1:end]), lookup, true, undefined)
+		if end < len(raw) {
+			result = append(result, '"')
+		}
+		i = end
+	}
+	if err := undefinedVariablesError(undefined); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExpandString replaces every ${name} placeholder within the string like ExpandVariables without any escaping
+func ExpandString(s string, lookup VariableLookup) (string, error) {
+	undefined := make(map[string]bool)
+	result := expandPlaceholders(nil, s, lookup, false, undefined)
+	if err := undefinedVariablesError(undefined); err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+// WithVariables expands the variable placeholders of the raw document using ExpandVariables before it is parsed
+func WithVariables(lookup VariableLookup) ParserOption {
+	return func(p *Parser) {
+		p.variables = lookup
+	}
+}
+
+// expandPlaceholders appends s to dst with its placeholders replaced, adding the names of any undefined variables to
+// undefined while keeping their placeholders as is
+func expandPlaceholders(dst []byte, s string, lookup VariableLookup, jsonEscape bool, undefined map[string]bool) []byte {
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			return append(dst, s...)
+		}
+		if start > 0 && s[start-1] == '$' {
+			dst = append(dst, s[:start-1]...)
+			dst = append(dst, "${"...)
+			s = s[start+2:]
+			continue
+		}
+		dst = append(dst, s[:start]...)
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 || !validVariableName(s[start+2:start+end]) {
+			dst = append(dst, "${"...)
+			s = s[start+2:]
+			continue
+		}
+		end += start
+		name := s[start+2 : end]
+		if value, defined := lookup(name); !defined {
+			undefined[name] = true
+			dst = append(dst, s[start:end+1]...)
+		} else if jsonEscape {
+			escaped := appendJSONString(nil, value)
+			dst = append(dst, escaped[1:len(escaped)-1]...)
+		} else {
+			dst = append(dst, value...)
+		}
+		s = s[end+1:]
+	}
+}
+
+func validVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func undefinedVariablesError(undefined map[string]bool) error {
+	if len(undefined) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(undefined))
+	for name := range undefined {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%w: %s", ErrUndefinedVariable, strings.Join(names, ", "))
+}
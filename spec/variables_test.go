@@ -0,0 +1,78 @@
+package spec
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpandVariables(t *testing.T) {
+	vars := VariablesFromMap(map[string]string{
+		"host":    "api.example.com",
+		"quote":   `say "hi"`,
+		"version": "1.2",
+	})
+	tests := map[string]struct {
+		raw      string
+		expected string
+		wantErr  string
+	}{
+		"placeholders in strings should be replaced": {
+			raw:      `{"host": "${host}", "info": {"version": "v${version}-${version}"}}`,
+			expected: `{"host": "api.example.com", "info": {"version": "v1.2-1.2"}}`,
+		},
+		"values should be escaped": {
+			raw:      `{"title": "${quote}"}`,
+			expected: `{"title": "say \"hi\""}`,
+		},
+		"escaped placeholders should be kept literally": {
+			raw:      `{"title": "$${host} and \"${host}\""}`,
+			expected: `{"title": "${host} and \"api.example.com\""}`,
+		},
+		"invalid placeholders should be kept as is": {
+			raw:      `{"title": "${} ${no spaces} ${unclosed"}`,
+			expected: `{"title": "${} ${no spaces} ${unclosed"}`,
+		},
+		"undefined variables should be named": {
+			raw:     `{"host": "${zone}.${host}", "basePath": "/${prefix}", "x-zone": "${zone}"}`,
+			wantErr: "undefined variable: prefix, zone",
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			got, err := ExpandVariables([]byte(tt.raw), vars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr || !errors.Is(err, ErrUndefinedVariable) {
+					t.Errorf("ExpandVariables() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandVariables() unexpected error: %s", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("ExpandVariables() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParser_WithVariables(t *testing.T) {
+	raw := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "${version}"},
+		"host": "${host}",
+		"paths": {"/${prefix}/pets": {"get": {"responses": {"200": {"description": "ok"}}}}}
+	}`)
+	vars := map[string]string{"version": "2.0", "host": "staging.example.com", "prefix": "v2"}
+	swagger, err := NewParser(raw, WithVariables(VariablesFromMap(vars))).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	if swagger.Host != "staging.example.com" || swagger.Info.Version != "2.0" || swagger.Paths.Items["/v2/pets"] == nil {
+		t.Errorf("Parse() did not expand the variables: host=%s version=%s", swagger.Host, swagger.Info.Version)
+	}
+	delete(vars, "host")
+	if _, err = NewParser(raw, WithVariables(VariablesFromMap(vars))).Parse(); !errors.Is(err, ErrUndefinedVariable) {
+		t.Errorf("Parse() error = %v, want %s", err, ErrUndefinedVariable)
+	}
+}
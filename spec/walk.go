@@ -0,0 +1,98 @@
+package spec
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// walkSchemas calls fn with every schema within the spec and its location, in a deterministic order
+func (s *Swagger) walkSchemas(fn func(loc string, schema *Schema)) {
+	if s == nil {
+		return
+	}
+	for _, name := range sortedKeys(s.Definitions) {
+		def := s.Definitions[name]
+		walkSchema(".definitions."+name, &def, fn)
+	}
+	for _, name := range sortedKeys(s.Parameters) {
+		if param := s.Parameters[name]; param.Schema != nil {
+			walkSchema(".parameters."+name+".schema", param.Schema, fn)
+		}
+	}
+	for _, name := range sortedKeys(s.Responses) {
+		if resp := s.Responses[name]; resp.Schema != nil {
+			walkSchema(".responses."+name+".schema", resp.Schema, fn)
+		}
+	}
+	for _, path := range sortedKeys(s.Paths.Items) {
+		pi := s.Paths.Items[path]
+		if pi == nil {
+			continue
+		}
+		pathLoc := ".paths." + path
+		walkParameterSchemas(pathLoc, pi.Parameters, fn)
+		for _, method := range pathItemMethods {
+			op := pi.Operation(method)
+			if op == nil {
+				continue
+			}
+			opLoc := pathLoc + "." + strings.ToLower(method)
+			walkParameterSchemas(opLoc, op.Parameters, fn)
+			for _, code := range sortedStatusCodes(op.Responses.ByStatusCode) {
+				if r := op.Responses.ByStatusCode[code]; r != nil && r.Schema != nil {
+					walkSchema(opLoc+".responses."+strconv.Itoa(code)+".schema", r.Schema, fn)
+				}
+			}
+			if r := op.Responses.Default; r != nil && r.Schema != nil {
+				walkSchema(opLoc+".responses.default.schema", r.Schema, fn)
+			}
+		}
+	}
+}
+
+// sortedStatusCodes returns the status codes of the responses in ascending order
+func sortedStatusCodes(responses map[int]*Response) []int {
+	codes := make([]int, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+func walkParameterSchemas(loc string, params []Parameter, fn func(loc string, schema *Schema)) {
+	for i := range params {
+		if params[i].Schema != nil {
+			walkSchema(loc+".parameters["+strconv.Itoa(i)+"].schema", params[i].Schema, fn)
+		}
+	}
+}
+
+// walkSchema calls fn with the schema and every schema nested within it, which are never cyclic as references are
+// not followed
+func walkSchema(loc string, s *Schema, fn func(loc string, schema *Schema)) {
+	fn(loc, s)
+	if s.Items != nil {
+		if items, single := s.Items.AsSchema(); single {
+			walkSchema(loc+".items", items, fn)
+		} else {
+			for i := range s.Items.items {
+				walkSchema(loc+".items["+strconv.Itoa(i)+"]", &s.Items.items[i], fn)
+			}
+		}
+	}
+	if additional, ok := s.AdditionalItems.AsSchema(); ok {
+		walkSchema(loc+".additionalItems", additional, fn)
+	}
+	for i := range s.AllOf {
+		walkSchema(loc+".allOf["+strconv.Itoa(i)+"]", &s.AllOf[i], fn)
+	}
+	for _, name := range sortedKeys(s.Properties) {
+		prop := s.Properties[name]
+		walkSchema(loc+".properties."+name, &prop, fn)
+	}
+	if additional, ok := s.AdditionalProperties.AsSchema(); ok {
+		walkSchema(loc+".additionalProperties", additional, fn)
+	}
+}
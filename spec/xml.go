@@ -25,19 +25,18 @@ func NewXML() *XML {
 }
 
 func parseXML(val *fastjson.Value, parser *Parser) *XML {
+	parser.checkContext()
 	// first be sure to capture and reset our parser's location
-	fromLoc := parser.currentLoc
-	defer func() {
-		parser.currentLoc = fromLoc
-	}()
+	fromLoc := parser.mark()
+	defer parser.reset(fromLoc)
 	obj, err := val.Object()
 	if err != nil {
-		parser.appendError(fmt.Errorf("invalid security value: %w", err))
+		parser.appendError(ErrorCodeInvalidType, fmt.Errorf("invalid security value: %w", err))
 		return nil
 	}
 	result := NewXML()
 	obj.Visit(func(key []byte, v *fastjson.Value) {
-		parser.currentLoc = fmt.Sprintf("%s.%s", fromLoc, key)
+		parser.atKey(fromLoc, key)
 		switch {
 		case matchString(key, "name"):
 			parser.parseString(v, "name", true, func(s string) {
@@ -60,10 +59,21 @@ func parseXML(val *fastjson.Value, parser *Parser) *XML {
 				result.IsWrapped = b
 			})
 		case matchExtension(key):
-			result.Extensions[string(key)] = v
+			parser.acceptExtension(result.Extensions, key, v)
 		default:
-			parser.appendError(fmt.Errorf("invalid field name: '%s'", key))
+			parser.appendError(ErrorCodeUnknownField, fmt.Errorf("invalid field name: '%s'", key))
 		}
 	})
 	return result
 }
+
+func (x *XML) marshal(a *fastjson.Arena) *fastjson.Value {
+	val := a.NewObject()
+	setString(a, val, "name", x.Name)
+	setString(a, val, "namespace", x.Namespace)
+	setString(a, val, "prefix", x.Prefix)
+	setBool(a, val, "attribute", x.IsAttribute)
+	setBool(a, val, "wrapped", x.IsWrapped)
+	x.marshalExtensions(val)
+	return val
+}
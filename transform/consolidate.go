@@ -0,0 +1,127 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// ConsolidateOptions defines the configuration of PlanConsolidation
+type ConsolidateOptions struct {
+	// Threshold is the minimum spec.SchemaSimilarity of definitions to consolidate, when 0 only structurally identical
+	// definitions are
+	Threshold float64
+}
+
+// Consolidation is a group of similar definitions where every reference to those replaced should be rewritten to
+// refer to the one kept
+type Consolidation struct {
+	Keep    string
+	Replace []string
+	// Similarity is the lowest similarity of any replaced definition to the one kept
+	Similarity float64
+	// Refs are the locations of the references to the replaced definitions
+	Refs []string
+}
+
+// PlanConsolidation finds the groups of structurally identical or similar definitions of the swagger spec, keeping the
+// most referenced definition of each group and then the first by name. Definitions are grouped with the first
+// definition by name they are similar enough to.
+func PlanConsolidation(swagger *spec.Swagger, opts ConsolidateOptions) []Consolidation {
+	if swagger == nil {
+		return nil
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = 1
+	}
+	var (
+		names   = sortedDefinitionNames(swagger)
+		grouped = make(map[string]bool, len(names))
+		results []Consolidation
+	)
+	for i, name := range names {
+		if grouped[name] {
+			continue
+		}
+		group := []string{name}
+		def := swagger.Definitions[name]
+		for _, other := range names[i+1:] {
+			if grouped[other] {
+				continue
+			}
+			otherDef := swagger.Definitions[other]
+			if spec.SchemaSimilarity(&def, &otherDef) >= opts.Threshold {
+				group = append(group, other)
+				grouped[other] = true
+			}
+		}
+		if len(group) > 1 {
+			results = append(results, planGroup(swagger, group))
+		}
+	}
+	return results
+}
+
+// planGroup returns the consolidation of the group of definitions sorted by name
+func planGroup(swagger *spec.Swagger, group []string) Consolidation {
+	refs := make(map[string][]string, len(group))
+	for _, name := range group {
+		refs[name] = swagger.DefinitionRefs(name)
+	}
+	keep := group[0]
+	for _, name := range group[1:] {
+		if len(refs[name]) > len(refs[keep]) {
+			keep = name
+		}
+	}
+	result := Consolidation{Keep: keep, Similarity: 1}
+	keepDef := swagger.Definitions[keep]
+	for _, name := range group {
+		if name == keep {
+			continue
+		}
+		def := swagger.Definitions[name]
+		if similarity := spec.SchemaSimilarity(&keepDef, &def); similarity < result.Similarity {
+			result.Similarity = similarity
+		}
+		result.Replace = append(result.Replace, name)
+		result.Refs = append(result.Refs, refs[name]...)
+	}
+	sort.Strings(result.Refs)
+	return result
+}
+
+// Consolidate applies the consolidations to the swagger spec, rewriting the references to each replaced definition
+// and then removing it, returning the count of references rewritten
+func Consolidate(swagger *spec.Swagger, plan []Consolidation) (int, error) {
+	if swagger == nil {
+		return 0, errors.New("cannot consolidate a nil swagger")
+	}
+	replacements := make(map[string]string)
+	for _, c := range plan {
+		if _, exists := swagger.Definitions[c.Keep]; !exists {
+			return 0, fmt.Errorf("cannot keep undefined definition %s", c.Keep)
+		}
+		for _, name := range c.Replace {
+			if _, exists := swagger.Definitions[name]; !exists {
+				return 0, fmt.Errorf("cannot replace undefined definition %s", name)
+			}
+			if name == c.Keep {
+				return 0, fmt.Errorf("cannot both keep and replace definition %s", name)
+			}
+			replacements[name] = c.Keep
+		}
+	}
+	for _, c := range plan {
+		if _, replaced := replacements[c.Keep]; replaced {
+			return 0, fmt.Errorf("cannot keep definition %s as it is also replaced", c.Keep)
+		}
+	}
+	count := swagger.ReplaceDefinitionRefs(replacements)
+	for name := range replacements {
+		delete(swagger.Definitions, name)
+	}
+	return count, nil
+}
@@ -0,0 +1,143 @@
+package transform
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestConsolidate(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"get": {"responses": {
+					"200": {"description": "ok", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+					"default": {"description": "error", "schema": {"$ref": "#/definitions/ApiError"}}
+				}},
+				"post": {"responses": {
+					"201": {"description": "created", "schema": {"$ref": "#/definitions/Animal"}},
+					"400": {"description": "bad", "schema": {"$ref": "#/definitions/Error"}},
+					"409": {"description": "conflict", "schema": {"$ref": "#/definitions/Error"}},
+					"default": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}
+				}}
+			}
+		},
+		"definitions": {
+			"ApiError": {"type": "object", "properties": {"code": {"type": "integer"}, "message": {"type": "string"}}},
+			"Error": {"type": "object", "properties": {"code": {"type": "integer"}, "message": {"type": "string"}}},
+			"Pet": {"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}},
+			"Animal": {"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer"}, "kind": {"type": "string"}}},
+			"Wrapper": {"type": "object", "properties": {"error": {"$ref": "#/definitions/ApiError"}}}
+		}
+	}`
+	tests := map[string]struct {
+		threshold float64
+		expected  []Consolidation
+		refs      map[string][]string
+	}{
+		"identical definitions should be consolidated": {
+			expected: []Consolidation{{
+				Keep:       "Error",
+				Replace:    []string{"ApiError"},
+				Similarity: 1,
+				Refs:       []string{".definitions.Wrapper.properties.error", ".paths./pets.get.responses.default.schema"},
+			}},
+			refs: map[string][]string{
+				"Error": {
+					".definitions.Wrapper.properties.error",
+					".paths./pets.get.responses.default.schema",
+					".paths./pets.post.responses.400.schema",
+					".paths./pets.post.responses.409.schema",
+					".paths./pets.post.responses.default.schema",
+				},
+			},
+		},
+		"similar definitions should be consolidated above the threshold": {
+			threshold: 0.8,
+			expected: []Consolidation{
+				{
+					Keep:       "Animal",
+					Replace:    []string{"Pet"},
+					Similarity: (3 + 4*2.0/3) / 7,
+					Refs:       []string{".paths./pets.get.responses.200.schema.items"},
+				},
+				{
+					Keep:       "Error",
+					Replace:    []string{"ApiError"},
+					Similarity: 1,
+					Refs:       []string{".definitions.Wrapper.properties.error", ".paths./pets.get.responses.default.schema"},
+				},
+			},
+			refs: map[string][]string{
+				"Animal": {".paths./pets.get.responses.200.schema.items", ".paths./pets.post.responses.201.schema"},
+			},
+		},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			swagger, err := spec.NewParser([]byte(raw)).Parse()
+			if err != nil {
+				t.Fatalf("failed to parse: %s", err)
+			}
+			plan := PlanConsolidation(swagger, ConsolidateOptions{Threshold: tt.threshold})
+			got := make([]Consolidation, len(plan))
+			for i, c := range plan {
+				if i < len(tt.expected) && math.Abs(c.Similarity-tt.expected[i].Similarity) < 1e-9 {
+					c.Similarity = tt.expected[i].Similarity
+				}
+				got[i] = c
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("PlanConsolidation() =\n%+v\nwant\n%+v", got, tt.expected)
+			}
+			count, err := Consolidate(swagger, plan)
+			if err != nil {
+				t.Fatalf("Consolidate() unexpected error: %s", err)
+			}
+			var expectedCount int
+			for _, c := range plan {
+				expectedCount += len(c.Refs)
+				for _, name := range c.Replace {
+					if _, exists := swagger.Definitions[name]; exists {
+						t.Errorf("Consolidate() did not remove %s", name)
+					}
+				}
+			}
+			if count != expectedCount {
+				t.Errorf("Consolidate() = %d, want %d", count, expectedCount)
+			}
+			for name, expected := range tt.refs {
+				if got := swagger.DefinitionRefs(name); !reflect.DeepEqual(got, expected) {
+					t.Errorf("DefinitionRefs(%s) =\n%v\nwant\n%v", name, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestConsolidate_invalidPlan(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(`{"definitions": {"A": {"type": "string"}, "B": {"type": "string"}}}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	plans := map[string][]Consolidation{
+		"undefined kept definition":     {{Keep: "C", Replace: []string{"A"}}},
+		"undefined replaced definition": {{Keep: "A", Replace: []string{"C"}}},
+		"kept and replaced":             {{Keep: "A", Replace: []string{"A"}}},
+		"kept definition replaced":      {{Keep: "A", Replace: []string{"B"}}, {Keep: "B", Replace: []string{"A"}}},
+	}
+	for should, plan := range plans {
+		t.Run(should, func(t *testing.T) {
+			if _, err := Consolidate(swagger, plan); err == nil {
+				t.Error("Consolidate() expected an error")
+			}
+			if len(swagger.Definitions) != 2 {
+				t.Errorf("Consolidate() changed the definitions: %v", swagger.Definitions)
+			}
+		})
+	}
+}
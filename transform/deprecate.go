@@ -0,0 +1,60 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erraggy/goats/spec"
+	"github.com/valyala/fastjson"
+)
+
+// DeprecateOptions defines the configuration of the Deprecate transform
+type DeprecateOptions struct {
+	// Select returns true for each operation to deprecate
+	Select func(op *spec.Operation) bool
+	// Sunset is the date after which the operations will be removed, in the form 2006-01-02, which is the value of the
+	// x-sunset extension added to them
+	Sunset string
+}
+
+// Deprecate marks the selected operations of the swagger spec as deprecated along with their sunset date, returning
+// the keys of the operations that were not already deprecated with the same sunset date
+func Deprecate(swagger *spec.Swagger, opts DeprecateOptions) ([]spec.OperationKey, error) {
+	if swagger == nil {
+		return nil, errors.New("cannot deprecate operations of a nil swagger")
+	}
+	if opts.Select == nil {
+		return nil, errors.New("no operations selected to deprecate")
+	}
+	if _, err := time.Parse("2006-01-02", opts.Sunset); err != nil {
+		return nil, fmt.Errorf("invalid sunset date '%s': must be of the form 2006-01-02", opts.Sunset)
+	}
+	var (
+		a       fastjson.Arena
+		results []spec.OperationKey
+	)
+	for _, op := range swagger.Operations() {
+		if !opts.Select(op) {
+			continue
+		}
+		if sunset, _ := op.Extensions.GetString(SunsetExtension); op.Deprecated && sunset == opts.Sunset {
+			continue
+		}
+		deprecate(&a, op, opts.Sunset)
+		results = append(results, op.Key)
+	}
+	return results, nil
+}
+
+// deprecate marks the operation as deprecated, adding the x-sunset extension unless the sunset date is empty
+func deprecate(a *fastjson.Arena, op *spec.Operation, sunset string) {
+	op.Deprecated = true
+	if sunset == "" {
+		return
+	}
+	if op.Extensions == nil {
+		op.Extensions = make(spec.Extensions)
+	}
+	op.Extensions[SunsetExtension] = a.NewString(sunset)
+}
@@ -0,0 +1,68 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestDeprecate(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"get": {"tags": ["pets"], "responses": {"200": {"description": "ok"}}},
+				"post": {"tags": ["pets"], "deprecated": true, "x-sunset": "2027-01-31", "responses": {"201": {"description": "created"}}}
+			},
+			"/pets/{id}": {
+				"delete": {"tags": ["pets"], "deprecated": true, "parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}], "responses": {"204": {"description": "deleted"}}}
+			},
+			"/owners": {
+				"get": {"tags": ["owners"], "responses": {"200": {"description": "ok"}}}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	byTag := func(op *spec.Operation) bool {
+		for _, tag := range op.Tags {
+			if tag == "pets" {
+				return true
+			}
+		}
+		return false
+	}
+	if _, err = Deprecate(swagger, DeprecateOptions{Select: byTag, Sunset: "31/01/2027"}); err == nil {
+		t.Error("Deprecate() expected an error for an invalid sunset date")
+	}
+	if _, err = Deprecate(swagger, DeprecateOptions{Sunset: "2027-01-31"}); err == nil {
+		t.Error("Deprecate() expected an error without any selection")
+	}
+	got, err := Deprecate(swagger, DeprecateOptions{Select: byTag, Sunset: "2027-01-31"})
+	if err != nil {
+		t.Fatalf("Deprecate() unexpected error: %s", err)
+	}
+	expected := []spec.OperationKey{
+		{Path: "/pets", Method: "GET"},
+		{Path: "/pets/{id}", Method: "DELETE"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Deprecate() = %v, want %v", got, expected)
+	}
+	for _, op := range swagger.Operations() {
+		sunset, found := op.Extensions.GetString(SunsetExtension)
+		if byTag(op) != op.Deprecated || byTag(op) != found {
+			t.Errorf("%s deprecated = %t with sunset %t", op.Key.Location(), op.Deprecated, found)
+		}
+		if found && sunset != "2027-01-31" {
+			t.Errorf("%s sunset = %s, want 2027-01-31", op.Key.Location(), sunset)
+		}
+	}
+	if got, _ = Deprecate(swagger, DeprecateOptions{Select: byTag, Sunset: "2027-01-31"}); len(got) != 0 {
+		t.Errorf("Deprecate() again = %v, want none", got)
+	}
+}
@@ -0,0 +1,2 @@
+// Package transform provides the modification of parsed swagger specifications
+package transform
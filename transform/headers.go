@@ -0,0 +1,75 @@
+package transform
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// ResponseHeadersOptions defines the configuration of the ResponseHeaders transform
+type ResponseHeadersOptions struct {
+	// Headers are the templates of the response headers to apply by their names
+	Headers map[string]*spec.Header
+	// Select returns true for each operation to apply the headers to, when nil all operations are
+	Select func(op *spec.Operation) bool
+	// Status returns true for each response to apply the headers to by its status code or "default", when nil all
+	// responses are
+	Status func(status string) bool
+	// Overwrite replaces any header already declared with the same name, otherwise those are left as is
+	Overwrite bool
+}
+
+// ResponseHeaders applies a copy of each of the header templates to the selected responses of the selected operations
+// of the swagger spec, returning the count of headers applied. Responses are copied before being changed as they may
+// be shared with other operations.
+func ResponseHeaders(swagger *spec.Swagger, opts ResponseHeadersOptions) (int, error) {
+	if swagger == nil {
+		return 0, errors.New("cannot apply headers to a nil swagger")
+	}
+	if len(opts.Headers) == 0 {
+		return 0, errors.New("no response headers to apply")
+	}
+	if opts.Select == nil {
+		opts.Select = func(*spec.Operation) bool { return true }
+	}
+	if opts.Status == nil {
+		opts.Status = func(string) bool { return true }
+	}
+	apply := func(r *spec.Response) (*spec.Response, int) {
+		var applied int
+		result := *r
+		result.Headers = make(map[string]*spec.Header, len(r.Headers)+len(opts.Headers))
+		for name, h := range r.Headers {
+			result.Headers[name] = h
+		}
+		for name, tmpl := range opts.Headers {
+			if _, exists := result.Headers[name]; exists && !opts.Overwrite {
+				continue
+			}
+			h := *tmpl
+			result.Headers[name] = &h
+			applied++
+		}
+		return &result, applied
+	}
+	var count int
+	for _, op := range swagger.Operations() {
+		if !opts.Select(op) {
+			continue
+		}
+		for code, r := range op.Responses.ByStatusCode {
+			if r != nil && opts.Status(strconv.Itoa(code)) {
+				var applied int
+				op.Responses.ByStatusCode[code], applied = apply(r)
+				count += applied
+			}
+		}
+		if r := op.Responses.Default; r != nil && opts.Status("default") {
+			var applied int
+			op.Responses.Default, applied = apply(r)
+			count += applied
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,65 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/erraggy/goats/lint"
+	"github.com/erraggy/goats/spec"
+)
+
+func TestResponseHeaders(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"paths": {
+			"/pets": {
+				"get": {"responses": {"200": {"description": "ok", "headers": {"X-Request-Id": {"type": "integer"}}}, "default": {"description": "error"}}},
+				"post": {"responses": {"201": {"description": "created"}}}
+			},
+			"/health": {
+				"get": {"responses": {"200": {"description": "ok"}}}
+			}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	names := []string{"X-Request-Id", "X-RateLimit-Remaining"}
+	rule := lint.RequiredResponseHeaders(names, func(status string) bool {
+		return strings.HasPrefix(status, "2")
+	})
+	if findings := lint.Lint(swagger, rule); len(findings) != 3 {
+		t.Errorf("expected 3 findings before the transform but got: %v", findings)
+	}
+
+	headers := map[string]*spec.Header{
+		"X-Request-Id":          {Type: "string", Description: "the id of the request"},
+		"X-RateLimit-Remaining": {Type: "integer"},
+	}
+	count, err := ResponseHeaders(swagger, ResponseHeadersOptions{
+		Headers: headers,
+		Select: func(op *spec.Operation) bool {
+			return op.Key.Path != "/health"
+		},
+	})
+	if err != nil {
+		t.Fatalf("ResponseHeaders() failed: %s", err)
+	}
+	if count != 5 {
+		t.Errorf("ResponseHeaders() applied %d headers, want 5", count)
+	}
+	ops := swagger.OperationMap()
+	get := ops[spec.OperationKey{Path: "/pets", Method: "GET"}]
+	if h := get.Responses.ByStatusCode[200].Headers["X-Request-Id"]; h.Type != "integer" {
+		t.Errorf("an existing header should not be overwritten but got type %s", h.Type)
+	}
+	if h := get.Responses.Default.Headers["X-Request-Id"]; h == nil || h == headers["X-Request-Id"] {
+		t.Error("the default response should have a copy of the template header")
+	}
+	findings := lint.Lint(swagger, rule)
+	if len(findings) != 1 || findings[0].Location != ".paths./health.get.responses.200" {
+		t.Errorf("expected only the unselected operation to be missing headers but got: %v", findings)
+	}
+}
@@ -0,0 +1,132 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// InternalExtension is the default extension marking operations, definitions and tags as internal
+const InternalExtension = "x-internal"
+
+// StripInternalOptions defines the configuration of the StripInternal transform
+type StripInternalOptions struct {
+	// Extension marks the internal objects, when empty InternalExtension is used
+	Extension string
+	// IsInternal returns true for the objects to strip by their extensions, when nil those with the extension set to
+	// true are
+	IsInternal func(exts spec.Extensions) bool
+}
+
+// StripInternalResult lists what was removed by the StripInternal transform
+type StripInternalResult struct {
+	Operations []spec.OperationKey
+	// Definitions are both those marked as internal and those no longer referenced once the rest were removed
+	Definitions []string
+	Tags        []string
+}
+
+// StripInternal removes the internal operations, definitions and tags of the swagger spec to produce its public spec.
+// Operations are also removed when all of their tags are internal, and the internal tags are removed from the remaining
+// operations. Definitions that were only referenced by removed objects are pruned, though any that were already
+// unreferenced are kept. An internal definition still referenced by a public object is kept and reported as an error.
+func StripInternal(swagger *spec.Swagger, opts StripInternalOptions) (*StripInternalResult, error) {
+	if swagger == nil {
+		return nil, errors.New("cannot strip a nil swagger")
+	}
+	if opts.Extension == "" {
+		opts.Extension = InternalExtension
+	}
+	if opts.IsInternal == nil {
+		opts.IsInternal = func(exts spec.Extensions) bool {
+			internal, _ := exts.GetBool(opts.Extension)
+			return internal
+		}
+	}
+
+	result := &StripInternalResult{}
+	reachable := make(map[string]bool, len(swagger.Definitions))
+	for _, name := range swagger.ReachableDefinitions() {
+		reachable[name] = true
+	}
+
+	internalTags := make(map[string]bool)
+	tags := swagger.Tags[:0]
+	for _, tag := range swagger.Tags {
+		if opts.IsInternal(tag.Extensions) {
+			internalTags[tag.Name] = true
+			result.Tags = append(result.Tags, tag.Name)
+		} else {
+			tags = append(tags, tag)
+		}
+	}
+	swagger.Tags = tags
+
+	for _, op := range swagger.Operations() {
+		internal := opts.IsInternal(op.Extensions)
+		if !internal && len(op.Tags) > 0 {
+			internal = true
+			for _, tag := range op.Tags {
+				internal = internal && internalTags[tag]
+			}
+		}
+		if internal {
+			swagger.RemoveOperation(op.Key)
+			result.Operations = append(result.Operations, op.Key)
+		}
+	}
+	if len(internalTags) > 0 {
+		for _, op := range swagger.Operations() {
+			var tags []string
+			for _, tag := range op.Tags {
+				if !internalTags[tag] {
+					tags = append(tags, tag)
+				}
+			}
+			op.Tags = tags
+		}
+	}
+
+	// internal definitions still referenced by what remains cannot be removed without leaving dangling references
+	var errs []error
+	referenced := make(map[string]bool, len(swagger.Definitions))
+	for _, name := range swagger.ReachableDefinitions() {
+		referenced[name] = true
+	}
+	for _, name := range sortedDefinitionNames(swagger) {
+		if !opts.IsInternal(swagger.Definitions[name].Extensions) {
+			continue
+		}
+		if referenced[name] {
+			errs = append(errs, fmt.Errorf("cannot strip internal definition %s as it is still referenced", name))
+			continue
+		}
+		delete(swagger.Definitions, name)
+		result.Definitions = append(result.Definitions, name)
+	}
+
+	// prune what was only referenced by the removed objects
+	referenced = make(map[string]bool, len(swagger.Definitions))
+	for _, name := range swagger.ReachableDefinitions() {
+		referenced[name] = true
+	}
+	for _, name := range sortedDefinitionNames(swagger) {
+		if reachable[name] && !referenced[name] {
+			delete(swagger.Definitions, name)
+			result.Definitions = append(result.Definitions, name)
+		}
+	}
+	sort.Strings(result.Definitions)
+	return result, errors.Join(errs...)
+}
+
+func sortedDefinitionNames(swagger *spec.Swagger) []string {
+	names := make([]string, 0, len(swagger.Definitions))
+	for name := range swagger.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,72 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestStripInternal(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"tags": [{"name": "pets"}, {"name": "ops", "x-internal": true}],
+		"paths": {
+			"/pets": {
+				"get": {"tags": ["pets", "ops"], "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}}}},
+				"delete": {"x-internal": true, "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Purge"}}}}
+			},
+			"/metrics": {
+				"get": {"tags": ["ops"], "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Metrics"}}}}
+			},
+			"/audit": {
+				"get": {"x-internal": true, "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Audit"}}}}
+			}
+		},
+		"definitions": {
+			"Pet": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Owner"}}},
+			"Owner": {"type": "object", "x-internal": true},
+			"Purge": {"type": "object", "properties": {"count": {"$ref": "#/definitions/Count"}}},
+			"Count": {"type": "integer"},
+			"Metrics": {"type": "object"},
+			"Audit": {"type": "object", "x-internal": true},
+			"Unused": {"type": "object"}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	result, err := StripInternal(swagger, StripInternalOptions{})
+	if err == nil || err.Error() != "cannot strip internal definition Owner as it is still referenced" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := &StripInternalResult{
+		Operations: []spec.OperationKey{
+			{Path: "/audit", Method: "GET"},
+			{Path: "/metrics", Method: "GET"},
+			{Path: "/pets", Method: "DELETE"},
+		},
+		Definitions: []string{"Audit", "Count", "Metrics", "Purge"},
+		Tags:        []string{"ops"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("StripInternal() = %+v, want %+v", result, expected)
+	}
+	if swagger.OperationCount() != 1 {
+		t.Errorf("expected 1 remaining operation but got %d", swagger.OperationCount())
+	}
+	pets := swagger.Paths.Items["/pets"]
+	if pets == nil || pets.Delete != nil || !reflect.DeepEqual(pets.Get.Tags, []string{"pets"}) {
+		t.Errorf("unexpected /pets path item: %+v", pets)
+	}
+	for _, path := range []string{"/metrics", "/audit"} {
+		if _, exists := swagger.Paths.Items[path]; exists {
+			t.Errorf("path %s should have been removed", path)
+		}
+	}
+	if defs := sortedDefinitionNames(swagger); !reflect.DeepEqual(defs, []string{"Owner", "Pet", "Unused"}) {
+		t.Errorf("unexpected remaining definitions: %v", defs)
+	}
+}
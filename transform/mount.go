@@ -0,0 +1,258 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/erraggy/goats/spec"
+)
+
+// MountOptions defines the configuration of the Mount transform
+type MountOptions struct {
+	// Prefix is the path prefix the source spec is mounted under, e.g. "/billing"
+	Prefix string
+	// Namespace is prefixed to the names of the definitions, parameters, responses and security schemes of the source
+	// that collide with those of the target, when empty it is derived from the prefix, e.g. "/billing" becomes
+	// "Billing". Colliding operationIds are prefixed with it as well, e.g. "getInvoice" becomes "billingGetInvoice".
+	Namespace string
+}
+
+// MountResult lists what the Mount transform added to the target spec
+type MountResult struct {
+	Operations []spec.OperationKey
+	// Renamed maps the locations within the source of the names that collided with the target to their new names,
+	// e.g. ".definitions.Error" to "BillingError" or ".paths./invoices.get.operationId" to "billingGetInvoice"
+	Renamed map[string]string
+}
+
+// Mount merges a copy of the source spec into the target spec under the path prefix followed by the basePath of the
+// source, so that many service specs can be merged into a single gateway spec. The security, consumes and produces
+// of the source apply to each of its operations that do not override them, and its tags not already declared by the
+// target are added. Operations whose mounted path and method already exist in the target are not mounted and are
+// reported in the returned error.
+func Mount(target, source *spec.Swagger, opts MountOptions) (*MountResult, error) {
+	if target == nil || source == nil {
+		return nil, errors.New("cannot mount a nil swagger")
+	}
+	prefix := "/" + strings.Trim(opts.Prefix, "/")
+	if prefix == "/" {
+		return nil, errors.New("cannot mount with an empty prefix")
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = namespaceOf(prefix)
+	}
+	// mount a copy so that nothing of the source is shared with or changed by the target
+	raw, err := source.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy source: %w", err)
+	}
+	mounted, err := spec.NewParser(raw).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy source: %w", err)
+	}
+
+	result := &MountResult{Renamed: make(map[string]string)}
+	rename := func(loc, name string, taken func(name string) bool) string {
+		if !taken(name) {
+			return name
+		}
+		renamed := opts.Namespace + upperFirst(name)
+		for i := 2; taken(renamed); i++ {
+			renamed = opts.Namespace + upperFirst(name) + strconv.Itoa(i)
+		}
+		result.Renamed[loc+"."+name] = renamed
+		return renamed
+	}
+
+	definitions := make(map[string]string, len(mounted.Definitions))
+	for _, name := range sortedDefinitionNames(mounted) {
+		definitions[name] = rename(".definitions", name, func(n string) bool {
+			_, exists := target.Definitions[n]
+			return exists || n != name && hasDefinition(mounted, n)
+		})
+	}
+	mounted.ReplaceDefinitionRefs(definitions)
+	if len(definitions) > 0 && target.Definitions == nil {
+		target.Definitions = make(map[string]spec.Schema, len(definitions))
+	}
+	for name, renamed := range definitions {
+		target.Definitions[renamed] = mounted.Definitions[name]
+	}
+	parameters := make(map[string]string, len(mounted.Parameters))
+	for _, name := range sortedKeys(mounted.Parameters) {
+		renamed := rename(".parameters", name, func(n string) bool {
+			_, exists := target.Parameters[n]
+			return exists
+		})
+		if target.Parameters == nil {
+			target.Parameters = make(map[string]spec.Parameter)
+		}
+		target.Parameters[renamed] = mounted.Parameters[name]
+		if renamed != name {
+			parameters[name] = renamed
+		}
+	}
+	mounted.ReplaceParameterRefs(parameters)
+	for _, name := range sortedKeys(mounted.Responses) {
+		renamed := rename(".responses", name, func(n string) bool {
+			_, exists := target.Responses[n]
+			return exists
+		})
+		if target.Responses == nil {
+			target.Responses = make(map[string]spec.Response)
+		}
+		target.Responses[renamed] = mounted.Responses[name]
+	}
+	schemes := make(map[string]string, len(mounted.SecurityDefinitions))
+	for _, name := range sortedKeys(mounted.SecurityDefinitions) {
+		ss := mounted.SecurityDefinitions[name]
+		if existing, exists := target.SecurityDefinitions[name]; exists && reflect.DeepEqual(existing, ss) {
+			// an identical scheme is shared
+			continue
+		}
+		renamed := rename(".securityDefinitions", name, func(n string) bool {
+			_, exists := target.SecurityDefinitions[n]
+			return exists
+		})
+		if target.SecurityDefinitions == nil {
+			target.SecurityDefinitions = make(map[string]spec.SecurityScheme)
+		}
+		target.SecurityDefinitions[renamed] = ss
+		schemes[name] = renamed
+	}
+	for _, tag := range mounted.Tags {
+		if !declaresTag(target, tag.Name) {
+			target.Tags = append(target.Tags, tag)
+		}
+	}
+
+	ids := make(map[string]bool)
+	for _, op := range target.Operations() {
+		if op.ID != "" {
+			ids[op.ID] = true
+		}
+	}
+	existingPaths := make(map[string]bool, len(target.Paths.Items))
+	for path := range target.Paths.Items {
+		existingPaths[path] = true
+	}
+	basePath := strings.TrimSuffix(mounted.BasePath, "/")
+	var errs []error
+	for _, op := range mounted.Operations() {
+		key := op.Key
+		op.Key.Path = prefix + basePath + key.Path
+		if op.Security == nil {
+			// an empty slice keeps operations of a source without security from inheriting that of the target
+			op.Security = append(make([]spec.SecurityRequirements, 0, len(mounted.Security)), mounted.Security...)
+		}
+		op.Security = renameSecurity(op.Security, schemes)
+		if len(op.Consumes) == 0 {
+			op.Consumes = mounted.Consumes
+		}
+		if len(op.Produces) == 0 {
+			op.Produces = mounted.Produces
+		}
+		if op.ID != "" && ids[op.ID] {
+			id := lowerFirst(opts.Namespace) + upperFirst(op.ID)
+			for i := 2; ids[id]; i++ {
+				id = lowerFirst(opts.Namespace) + upperFirst(op.ID) + strconv.Itoa(i)
+			}
+			result.Renamed[key.Location()+".operationId"] = id
+			op.ID = id
+		}
+		if !target.AddOperation(op) {
+			errs = append(errs, fmt.Errorf("cannot mount %s %s as %s already exists", key.Method, key.Path, op.Key.Path))
+			continue
+		}
+		if op.ID != "" {
+			ids[op.ID] = true
+		}
+		result.Operations = append(result.Operations, op.Key)
+	}
+	for path, pi := range mounted.Paths.Items {
+		mountedPath := prefix + basePath + path
+		if added := target.Paths.Items[mountedPath]; pi != nil && added != nil && !existingPaths[mountedPath] {
+			added.Extensions = pi.Extensions
+			added.Parameters = pi.Parameters
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// renameSecurity returns the security requirements with the renamed schemes, copying them only when any are
+func renameSecurity(reqs []spec.SecurityRequirements, renamed map[string]string) []spec.SecurityRequirements {
+	if len(renamed) == 0 || reqs == nil {
+		return reqs
+	}
+	results := make([]spec.SecurityRequirements, len(reqs))
+	for i, req := range reqs {
+		results[i] = make(spec.SecurityRequirements, len(req))
+		for name, scopes := range req {
+			if r, ok := renamed[name]; ok {
+				name = r
+			}
+			results[i][name] = scopes
+		}
+	}
+	return results
+}
+
+// namespaceOf returns the namespace from the words of the prefix, e.g. "/billing-api/v2" becomes "BillingApiV2"
+func namespaceOf(prefix string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range prefix {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	for _, r := range s {
+		return string(unicode.ToLower(r)) + s[len(string(r)):]
+	}
+	return s
+}
+
+func upperFirst(s string) string {
+	for _, r := range s {
+		return string(unicode.ToUpper(r)) + s[len(string(r)):]
+	}
+	return s
+}
+
+func hasDefinition(swagger *spec.Swagger, name string) bool {
+	_, exists := swagger.Definitions[name]
+	return exists
+}
+
+func declaresTag(swagger *spec.Swagger, name string) bool {
+	for _, tag := range swagger.Tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,174 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestMount(t *testing.T) {
+	target, err := spec.NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "gateway", "version": "1.0"},
+		"security": [{"key": []}],
+		"securityDefinitions": {"key": {"type": "apiKey", "name": "X-Key", "in": "header"}},
+		"tags": [{"name": "pets"}],
+		"paths": {
+			"/pets": {"get": {"operationId": "getPet", "tags": ["pets"], "responses": {"200": {"description": "ok"}}}},
+			"/billing/v1/invoices": {"delete": {"responses": {"204": {"description": "gone"}}}}
+		},
+		"definitions": {"Error": {"type": "object", "properties": {"message": {"type": "string"}}}}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse target: %s", err)
+	}
+	source, err := spec.NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "billing", "version": "1.0"},
+		"basePath": "/v1",
+		"produces": ["application/json"],
+		"security": [{"key": []}],
+		"securityDefinitions": {"key": {"type": "apiKey", "name": "Api-Key", "in": "header"}},
+		"tags": [{"name": "pets"}, {"name": "invoices"}],
+		"paths": {
+			"/invoices": {
+				"parameters": [{"name": "tenant", "in": "header", "type": "string"}],
+				"get": {"operationId": "getPet", "tags": ["invoices"], "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Invoice"}}, "default": {"description": "error", "schema": {"$ref": "#/definitions/Error"}}}},
+				"delete": {"responses": {"204": {"description": "gone"}}}
+			},
+			"/health": {"get": {"security": [], "responses": {"200": {"description": "ok"}}}}
+		},
+		"definitions": {
+			"Error": {"type": "object", "properties": {"code": {"type": "integer"}}},
+			"Invoice": {"type": "object", "properties": {"error": {"$ref": "#/definitions/Error"}}}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse source: %s", err)
+	}
+	sourceJSON, _ := source.MarshalJSON()
+
+	result, err := Mount(target, source, MountOptions{Prefix: "/billing/"})
+	if err == nil || !strings.Contains(err.Error(), "cannot mount DELETE /invoices as /billing/v1/invoices already exists") {
+		t.Errorf("Mount() error = %v, want the existing operation reported", err)
+	}
+	expectedOps := []spec.OperationKey{{Path: "/billing/v1/health", Method: "GET"}, {Path: "/billing/v1/invoices", Method: "GET"}}
+	if !reflect.DeepEqual(result.Operations, expectedOps) {
+		t.Errorf("Mount() operations = %v, want %v", result.Operations, expectedOps)
+	}
+	expectedRenamed := map[string]string{
+		".definitions.Error":               "BillingError",
+		".securityDefinitions.key":         "BillingKey",
+		".paths./invoices.get.operationId": "billingGetPet",
+	}
+	if !reflect.DeepEqual(result.Renamed, expectedRenamed) {
+		t.Errorf("Mount() renamed = %v, want %v", result.Renamed, expectedRenamed)
+	}
+
+	op := target.OperationMap()[spec.OperationKey{Path: "/billing/v1/invoices", Method: "GET"}]
+	if op == nil {
+		t.Fatal("Mount() did not add the invoices operation")
+	}
+	if op.ID != "billingGetPet" || !reflect.DeepEqual(op.Produces, []string{"application/json"}) {
+		t.Errorf("Mount() operation id = %s produces = %v", op.ID, op.Produces)
+	}
+	if got := op.Security; !reflect.DeepEqual(got, []spec.SecurityRequirements{{"BillingKey": {}}}) {
+		t.Errorf("Mount() operation security = %v", got)
+	}
+	if got := op.Responses.Default.Schema.Ref.URI(); got != "#/definitions/BillingError" {
+		t.Errorf("Mount() response ref = %s", got)
+	}
+	if got := target.Definitions["Invoice"].Properties["error"].Ref.URI(); got != "#/definitions/BillingError" {
+		t.Errorf("Mount() definition ref = %s", got)
+	}
+	if code := target.Definitions["BillingError"].Properties["code"]; code.TypeName() != "integer" {
+		t.Errorf("Mount() did not add the renamed definition: %s", code.TypeName())
+	}
+	if health := target.OperationMap()[spec.OperationKey{Path: "/billing/v1/health", Method: "GET"}]; health == nil || health.Security == nil || len(health.Security) != 0 {
+		t.Error("Mount() did not keep the explicit empty security")
+	}
+	var tags []string
+	for _, tag := range target.Tags {
+		tags = append(tags, tag.Name)
+	}
+	if !reflect.DeepEqual(tags, []string{"pets", "invoices"}) {
+		t.Errorf("Mount() tags = %v", tags)
+	}
+	if afterJSON, _ := source.MarshalJSON(); string(afterJSON) != string(sourceJSON) {
+		t.Error("Mount() changed the source")
+	}
+}
+
+func TestMount_parameterRefs(t *testing.T) {
+	target, err := spec.NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "gateway", "version": "1.0"},
+		"parameters": {"limit": {"name": "limit", "in": "query", "type": "integer"}},
+		"paths": {
+			"/pets": {"get": {"parameters": [{"$ref": "#/parameters/limit"}], "responses": {"200": {"description": "ok"}}}}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse target: %s", err)
+	}
+	source, err := spec.NewParser([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "billing", "version": "1.0"},
+		"parameters": {
+			"limit": {"name": "pageSize", "in": "header", "type": "integer"},
+			"tenant": {"name": "tenant", "in": "header", "type": "string"}
+		},
+		"paths": {
+			"/invoices": {
+				"parameters": [{"$ref": "#/parameters/tenant"}],
+				"get": {"parameters": [{"$ref": "#/parameters/limit"}], "responses": {"200": {"description": "ok"}}}
+			}
+		}
+	}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse source: %s", err)
+	}
+
+	result, err := Mount(target, source, MountOptions{Prefix: "/billing"})
+	if err != nil {
+		t.Fatalf("Mount() error = %v", err)
+	}
+	if expected := map[string]string{".parameters.limit": "BillingLimit"}; !reflect.DeepEqual(result.Renamed, expected) {
+		t.Errorf("Mount() renamed = %v, want %v", result.Renamed, expected)
+	}
+
+	describe := func(path, method string) []string {
+		op := target.OperationMap()[spec.OperationKey{Path: path, Method: method}]
+		if op == nil {
+			t.Fatalf("missing operation %s %s", method, path)
+		}
+		var results []string
+		for _, p := range op.EffectiveParameters(target.Paths.Items[path], target.Parameters) {
+			results = append(results, string(p.In)+":"+p.Name)
+		}
+		return results
+	}
+	if got, expected := describe("/billing/invoices", "GET"), []string{"header:pageSize", "header:tenant"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("mounted operation parameters = %v, want %v", got, expected)
+	}
+	if got, expected := describe("/pets", "GET"), []string{"query:limit"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("target operation parameters = %v, want %v", got, expected)
+	}
+	if got := source.Paths.Items["/invoices"].Get.Parameters[0].Ref.URI(); got != "#/parameters/limit" {
+		t.Errorf("Mount() changed the source reference to %s", got)
+	}
+}
+
+func TestMount_namespace(t *testing.T) {
+	tests := map[string]string{
+		"/billing":         "Billing",
+		"/billing-api/v2/": "BillingApiV2",
+	}
+	for prefix, expected := range tests {
+		if got := namespaceOf(prefix); got != expected {
+			t.Errorf("namespaceOf(%s) = %s, want %s", prefix, got, expected)
+		}
+	}
+}
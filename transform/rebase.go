@@ -0,0 +1,120 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+	"github.com/valyala/fastjson"
+)
+
+// RebaseOptions defines the configuration of the Rebase transform
+type RebaseOptions struct {
+	// RewriteURLs also rewrites the absolute URLs under the old host and basePath found in the external docs of the
+	// spec, its tags, operations and definitions, and within the string values of the extensions of the spec, its
+	// info, tags, path items and operations
+	RewriteURLs bool
+}
+
+// Rebase replaces the host, basePath and schemes of the swagger spec, where empty values remove them, returning the
+// count of URLs rewritten. A rewritten URL keeps its scheme when it is one of the new schemes, otherwise it uses the
+// first of them.
+func Rebase(swagger *spec.Swagger, host, basePath string, schemes []string, opts RebaseOptions) (int, error) {
+	if swagger == nil {
+		return 0, errors.New("cannot rebase a nil swagger")
+	}
+	if strings.ContainsAny(host, "/?#") {
+		return 0, fmt.Errorf("invalid host '%s': must not include a scheme or path", host)
+	}
+	if basePath != "" && !strings.HasPrefix(basePath, "/") {
+		return 0, fmt.Errorf("invalid basePath '%s': must start with '/'", basePath)
+	}
+	for _, scheme := range schemes {
+		switch scheme {
+		case "http", "https", "ws", "wss":
+		default:
+			return 0, fmt.Errorf("invalid scheme '%s'", scheme)
+		}
+	}
+	oldHost, oldBasePath, oldSchemes := swagger.Host, strings.TrimSuffix(swagger.BasePath, "/"), swagger.Schemes
+	swagger.Host, swagger.BasePath = host, basePath
+	swagger.Schemes = append([]string(nil), schemes...)
+	if !opts.RewriteURLs || oldHost == "" || host == "" {
+		return 0, nil
+	}
+
+	if len(oldSchemes) == 0 {
+		oldSchemes = []string{"http", "https"}
+	}
+	var count int
+	rewrite := func(url string) (string, bool) {
+		for _, scheme := range oldSchemes {
+			prefix := scheme + "://" + oldHost + oldBasePath
+			rest := strings.TrimPrefix(url, prefix)
+			if rest == url || rest != "" && !strings.ContainsRune("/?#", rune(rest[0])) {
+				continue
+			}
+			if len(schemes) > 0 && !containsScheme(schemes, scheme) {
+				scheme = schemes[0]
+			}
+			count++
+			return scheme + "://" + host + strings.TrimSuffix(basePath, "/") + rest, true
+		}
+		return url, false
+	}
+	rewriteDocs := func(ed *spec.ExternalDocumentation) {
+		if ed != nil {
+			ed.URL, _ = rewrite(ed.URL)
+		}
+	}
+	rewriteDocs(swagger.ExternalDocumentation)
+	for i := range swagger.Tags {
+		rewriteDocs(swagger.Tags[i].ExternalDocumentation)
+	}
+	for _, op := range swagger.Operations() {
+		rewriteDocs(op.ExternalDocumentation)
+	}
+	for _, def := range swagger.Definitions {
+		// the documentation is shared by every copy of the definition
+		rewriteDocs(def.ExternalDocumentation)
+	}
+	var a fastjson.Arena
+	forEachExtensions(swagger, func(exts spec.Extensions) {
+		for key, val := range exts {
+			exts[key] = rewriteStrings(&a, val, rewrite)
+		}
+	})
+	return count, nil
+}
+
+// rewriteStrings returns the value with every string within it rewritten, which is the value itself when none are
+func rewriteStrings(a *fastjson.Arena, v *fastjson.Value, rewrite func(s string) (string, bool)) *fastjson.Value {
+	if v == nil {
+		return nil
+	}
+	switch v.Type() {
+	case fastjson.TypeString:
+		if s, rewritten := rewrite(string(v.GetStringBytes())); rewritten {
+			return a.NewString(s)
+		}
+	case fastjson.TypeArray:
+		for i, item := range v.GetArray() {
+			v.SetArrayItem(i, rewriteStrings(a, item, rewrite))
+		}
+	case fastjson.TypeObject:
+		v.GetObject().Visit(func(key []byte, child *fastjson.Value) {
+			v.Set(string(key), rewriteStrings(a, child, rewrite))
+		})
+	}
+	return v
+}
+
+func containsScheme(schemes []string, scheme string) bool {
+	for _, s := range schemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestRebase(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "test", "version": "1.0"},
+		"host": "pets.internal:8080",
+		"basePath": "/v1",
+		"schemes": ["http", "https"],
+		"externalDocs": {"url": "http://pets.internal:8080/v1/docs"},
+		"x-links": {"status": "https://pets.internal:8080/v1?health", "other": "https://example.com/v1", "sibling": "http://pets.internal:8080/v10/x", "retries": [1, "http://pets.internal:8080/v1"]},
+		"tags": [{"name": "pets", "externalDocs": {"url": "https://pets.internal:8080/v1/tags/pets#top"}}],
+		"paths": {
+			"/pets": {"get": {"x-docs": "http://pets.internal:8080/v1/pets", "responses": {"200": {"description": "ok"}}}}
+		},
+		"definitions": {
+			"Pet": {"type": "object", "externalDocs": {"url": "http://pets.internal:8080/v1/schemas/pet"}}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	count, err := Rebase(swagger, "api.example.com", "/billing/v1", []string{"https"}, RebaseOptions{RewriteURLs: true})
+	if err != nil {
+		t.Fatalf("Rebase() unexpected error: %s", err)
+	}
+	if count != 6 {
+		t.Errorf("Rebase() = %d, want 6", count)
+	}
+	if swagger.Host != "api.example.com" || swagger.BasePath != "/billing/v1" || !reflect.DeepEqual(swagger.Schemes, []string{"https"}) {
+		t.Errorf("Rebase() root = %s %s %v", swagger.Host, swagger.BasePath, swagger.Schemes)
+	}
+	checks := map[string][2]string{
+		"externalDocs": {swagger.ExternalDocumentation.URL, "https://api.example.com/billing/v1/docs"},
+		"tag":          {swagger.Tags[0].ExternalDocumentation.URL, "https://api.example.com/billing/v1/tags/pets#top"},
+		"definition":   {swagger.Definitions["Pet"].ExternalDocumentation.URL, "https://api.example.com/billing/v1/schemas/pet"},
+		"x-links": {
+			swagger.Extensions["x-links"].String(),
+			`{"status":"https://api.example.com/billing/v1?health","other":"https://example.com/v1","sibling":"http://pets.internal:8080/v10/x","retries":[1,"https://api.example.com/billing/v1"]}`,
+		},
+		"x-docs": {swagger.Paths.Items["/pets"].Get.Extensions["x-docs"].String(), `"https://api.example.com/billing/v1/pets"`},
+	}
+	for name, check := range checks {
+		if check[0] != check[1] {
+			t.Errorf("Rebase() %s = %s, want %s", name, check[0], check[1])
+		}
+	}
+}
+
+func TestRebase_invalid(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(`{"swagger": "2.0", "host": "old.example.com"}`)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	tests := map[string]struct {
+		host, basePath string
+		schemes        []string
+	}{
+		"host with a scheme": {host: "https://new.example.com"},
+		"host with a path":   {host: "new.example.com/v1"},
+		"relative basePath":  {host: "new.example.com", basePath: "v1"},
+		"unknown scheme":     {host: "new.example.com", schemes: []string{"ftp"}},
+	}
+	for should, tt := range tests {
+		t.Run(should, func(t *testing.T) {
+			if _, err := Rebase(swagger, tt.host, tt.basePath, tt.schemes, RebaseOptions{}); err == nil {
+				t.Error("Rebase() expected an error")
+			}
+			if swagger.Host != "old.example.com" {
+				t.Errorf("Rebase() changed the host to %s", swagger.Host)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/erraggy/goats/spec"
+	"github.com/valyala/fastjson"
+)
+
+// SubstituteVariables replaces the ${name} placeholders within the host, basePath and info of the swagger spec, and
+// within the string values of the extensions of the spec, its info, tags, path items and operations, with the values
+// of the variables. Placeholders of undefined variables are kept as is while the rest are substituted, and the
+// returned error wraps spec.ErrUndefinedVariable naming each of them.
+func SubstituteVariables(swagger *spec.Swagger, lookup spec.VariableLookup) error {
+	if swagger == nil {
+		return errors.New("cannot substitute variables of a nil swagger")
+	}
+	undefined := make(map[string]bool)
+	// undefined variables are recorded and their placeholders kept so the rest are still substituted
+	keep := func(name string) (string, bool) {
+		value, defined := lookup(name)
+		if !defined {
+			undefined[name] = true
+			return "${" + name + "}", true
+		}
+		return value, true
+	}
+	expand := func(s *string) {
+		*s, _ = spec.ExpandString(*s, keep)
+	}
+	expandExtensions := func(exts spec.Extensions) {
+		for key, val := range exts {
+			if val == nil {
+				continue
+			}
+			// substituted values are escaped so the expanded JSON remains valid
+			raw, _ := spec.ExpandVariables(val.MarshalTo(nil), keep)
+			if expanded, err := fastjson.ParseBytes(raw); err == nil {
+				exts[key] = expanded
+			}
+		}
+	}
+
+	expand(&swagger.Host)
+	expand(&swagger.BasePath)
+	expand(&swagger.Info.Title)
+	expand(&swagger.Info.Version)
+	expand(&swagger.Info.Description)
+	expand(&swagger.Info.TermsOfService)
+	forEachExtensions(swagger, expandExtensions)
+
+	if len(undefined) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(undefined))
+	for name := range undefined {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%w: %s", spec.ErrUndefinedVariable, strings.Join(names, ", "))
+}
+
+// forEachExtensions calls fn with the extensions of the swagger spec, its info, tags, path items and operations
+func forEachExtensions(swagger *spec.Swagger, fn func(exts spec.Extensions)) {
+	fn(swagger.Extensions)
+	fn(swagger.Info.Extensions)
+	for i := range swagger.Tags {
+		fn(swagger.Tags[i].Extensions)
+	}
+	for _, pi := range swagger.Paths.Items {
+		if pi != nil {
+			fn(pi.Extensions)
+		}
+	}
+	for _, op := range swagger.Operations() {
+		fn(op.Extensions)
+	}
+}
@@ -0,0 +1,47 @@
+package transform
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+func TestSubstituteVariables(t *testing.T) {
+	raw := `{
+		"swagger": "2.0",
+		"info": {"title": "Pets (${env})", "version": "${version}", "x-audience": "${env}"},
+		"host": "${env}.example.com",
+		"basePath": "/${prefix}",
+		"x-gateway": {"url": "https://${env}.gw.example.com", "retries": 3},
+		"paths": {
+			"/pets": {"get": {"x-owner": "team-${team}", "responses": {"200": {"description": "ok"}}}}
+		}
+	}`
+	swagger, err := spec.NewParser([]byte(raw)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	err = SubstituteVariables(swagger, spec.VariablesFromMap(map[string]string{
+		"env":     "staging",
+		"version": "1.0",
+		"team":    `"pets"`,
+	}))
+	if !errors.Is(err, spec.ErrUndefinedVariable) || err.Error() != "undefined variable: prefix" {
+		t.Errorf("SubstituteVariables() error = %v, want undefined variable: prefix", err)
+	}
+	checks := map[string][2]string{
+		"host":       {swagger.Host, "staging.example.com"},
+		"basePath":   {swagger.BasePath, "/${prefix}"},
+		"title":      {swagger.Info.Title, "Pets (staging)"},
+		"version":    {swagger.Info.Version, "1.0"},
+		"x-audience": {swagger.Info.Extensions["x-audience"].String(), `"staging"`},
+		"x-gateway":  {swagger.Extensions["x-gateway"].String(), `{"url":"https://staging.gw.example.com","retries":3}`},
+		"x-owner":    {swagger.Paths.Items["/pets"].Get.Extensions["x-owner"].String(), `"team-\"pets\""`},
+	}
+	for name, check := range checks {
+		if check[0] != check[1] {
+			t.Errorf("SubstituteVariables() %s = %s, want %s", name, check[0], check[1])
+		}
+	}
+}
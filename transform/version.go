@@ -0,0 +1,108 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/erraggy/goats/spec"
+	"github.com/valyala/fastjson"
+)
+
+// SunsetExtension is the extension declaring the date after which a deprecated operation will be removed
+const SunsetExtension = "x-sunset"
+
+// VersionPrefixOptions defines the configuration of the VersionPrefix transform
+type VersionPrefixOptions struct {
+	// Prefix is the versioned path prefix for the cloned operations, e.g. "/v2"
+	Prefix string
+	// Select returns true for each operation to be cloned, when nil all operations not already under the prefix are
+	Select func(op *spec.Operation) bool
+	// Sunset is the value of the x-sunset extension added to the original operations, when empty it is not added
+	Sunset string
+	// OperationID returns the operationId of a clone from the original, when nil the version is appended, e.g.
+	// "getPet" becomes "getPetV2"
+	OperationID func(id string) string
+	// Tag returns the tag of a clone from the original, when nil the version is appended, e.g. "pets" becomes "pets-v2"
+	Tag func(tag string) string
+}
+
+// VersionPrefix clones the selected operations of the swagger spec under the new versioned path prefix, marking the
+// originals as deprecated. The operationIds and tags of the clones are renamed and any renamed tags that are
+// declared by the spec are declared again under their new names. It returns the keys of the cloned operations.
+func VersionPrefix(swagger *spec.Swagger, opts VersionPrefixOptions) ([]spec.OperationKey, error) {
+	if swagger == nil {
+		return nil, errors.New("cannot version a nil swagger")
+	}
+	prefix := "/" + strings.Trim(opts.Prefix, "/")
+	if prefix == "/" {
+		return nil, errors.New("cannot version with an empty prefix")
+	}
+	version := strings.TrimPrefix(prefix[strings.LastIndex(prefix, "/"):], "/")
+	if opts.OperationID == nil {
+		suffix := []rune(version)
+		suffix[0] = unicode.ToUpper(suffix[0])
+		opts.OperationID = func(id string) string {
+			return id + string(suffix)
+		}
+	}
+	if opts.Tag == nil {
+		opts.Tag = func(tag string) string {
+			return tag + "-" + version
+		}
+	}
+	if opts.Select == nil {
+		opts.Select = func(op *spec.Operation) bool {
+			return op.Key.Path != prefix && !strings.HasPrefix(op.Key.Path, prefix+"/")
+		}
+	}
+
+	var (
+		a       fastjson.Arena
+		errs    []error
+		results []spec.OperationKey
+		tags    = make(map[string]string)
+	)
+	for _, op := range swagger.Operations() {
+		if !opts.Select(op) {
+			continue
+		}
+		clone := op.Clone()
+		clone.Key.Path = prefix + op.Key.Path
+		if op.ID != "" {
+			clone.ID = opts.OperationID(op.ID)
+		}
+		for i, tag := range clone.Tags {
+			clone.Tags[i] = opts.Tag(tag)
+			tags[tag] = clone.Tags[i]
+		}
+		if !swagger.AddOperation(clone) {
+			errs = append(errs, fmt.Errorf("cannot clone %s %s as %s already exists", op.Key.Method, op.Key.Path, clone.Key.Path))
+			continue
+		}
+		// the path item parameters apply to the clone as well
+		if pi := swagger.Paths.Items[op.Key.Path]; pi != nil && len(pi.Parameters) > 0 {
+			swagger.Paths.Items[clone.Key.Path].Parameters = append([]spec.Parameter(nil), pi.Parameters...)
+		}
+		deprecate(&a, op, opts.Sunset)
+		results = append(results, clone.Key)
+	}
+
+	// declare the renamed tags
+	declared := make(map[string]bool, len(swagger.Tags))
+	for _, tag := range swagger.Tags {
+		declared[tag.Name] = true
+	}
+	for _, tag := range swagger.Tags {
+		renamed, ok := tags[tag.Name]
+		if !ok || declared[renamed] {
+			continue
+		}
+		clone := tag
+		clone.Name = renamed
+		swagger.Tags = append(swagger.Tags, clone)
+		declared[renamed] = true
+	}
+	return results, errors.Join(errs...)
+}
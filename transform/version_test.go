@@ -0,0 +1,80 @@
+package transform
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/erraggy/goats/spec"
+)
+
+const petstore = `{
+	"swagger": "2.0",
+	"info": {"title": "Petstore", "version": "1.0"},
+	"tags": [{"name": "pets", "description": "Everything about pets"}],
+	"paths": {
+		"/pets": {
+			"get": {"operationId": "listPets", "tags": ["pets"], "responses": {"200": {"description": "ok"}}},
+			"post": {"operationId": "createPet", "tags": ["pets"], "responses": {"201": {"description": "created"}}}
+		},
+		"/pets/{id}": {
+			"parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}],
+			"get": {"operationId": "getPet", "tags": ["pets"], "responses": {"200": {"description": "ok"}}}
+		}
+	}
+}`
+
+func TestVersionPrefix(t *testing.T) {
+	swagger, err := spec.NewParser([]byte(petstore)).Parse()
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	keys, err := VersionPrefix(swagger, VersionPrefixOptions{
+		Prefix: "v2",
+		Select: func(op *spec.Operation) bool {
+			return op.Key.Method == http.MethodGet
+		},
+		Sunset: "2030-01-01",
+	})
+	if err != nil {
+		t.Fatalf("VersionPrefix() unexpected error: %s", err)
+	}
+	expectedKeys := []spec.OperationKey{
+		{Path: "/v2/pets", Method: http.MethodGet},
+		{Path: "/v2/pets/{id}", Method: http.MethodGet},
+	}
+	if !reflect.DeepEqual(keys, expectedKeys) {
+		t.Errorf("VersionPrefix() = %v, want %v", keys, expectedKeys)
+	}
+	if got := swagger.OperationCount(); got != 5 {
+		t.Errorf("OperationCount() = %d, want 5", got)
+	}
+	ops := swagger.OperationMap()
+	clone := ops[spec.OperationKey{Path: "/v2/pets/{id}", Method: http.MethodGet}]
+	if clone == nil || clone.ID != "getPetV2" || !reflect.DeepEqual(clone.Tags, []string{"pets-v2"}) || clone.Deprecated {
+		t.Errorf("unexpected clone: %+v", clone)
+	}
+	if params := swagger.Paths.Items["/v2/pets/{id}"].Parameters; len(params) != 1 || params[0].Name != "id" {
+		t.Errorf("path item parameters were not cloned: %v", params)
+	}
+	original := ops[spec.OperationKey{Path: "/pets/{id}", Method: http.MethodGet}]
+	if !original.Deprecated || original.ID != "getPet" || original.Tags[0] != "pets" {
+		t.Errorf("unexpected original: %+v", original)
+	}
+	if sunset := original.Extensions[SunsetExtension]; sunset == nil || string(sunset.GetStringBytes()) != "2030-01-01" {
+		t.Errorf("original is missing %s: %v", SunsetExtension, sunset)
+	}
+	if post := ops[spec.OperationKey{Path: "/pets", Method: http.MethodPost}]; post.Deprecated {
+		t.Error("unselected operation should not be deprecated")
+	}
+	if len(swagger.Tags) != 2 || swagger.Tags[1].Name != "pets-v2" || swagger.Tags[1].Description != swagger.Tags[0].Description {
+		t.Errorf("unexpected tags: %v", swagger.Tags)
+	}
+
+	// versioning again should fail for the already cloned operations
+	if _, err = VersionPrefix(swagger, VersionPrefixOptions{Prefix: "/v2", Select: func(op *spec.Operation) bool {
+		return op.Key.Path == "/pets" && op.Key.Method == http.MethodGet
+	}}); err == nil {
+		t.Error("VersionPrefix() should fail when the versioned operation already exists")
+	}
+}